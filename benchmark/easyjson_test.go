@@ -44,6 +44,46 @@ func BenchmarkEJ_Marshal_M(b *testing.B) {
 	b.SetBytes(l)
 }
 
+func BenchmarkEJ_Marshal_M_NoHint(b *testing.B) {
+	var l int64
+	for i := 0; i < b.N; i++ {
+		data, err := largeStructData.MarshalJSONSized(0)
+		if err != nil {
+			b.Error(err)
+		}
+		l = int64(len(data))
+	}
+	b.SetBytes(l)
+}
+
+func BenchmarkEJ_Marshal_M_Hint(b *testing.B) {
+	data, err := largeStructData.MarshalJSON()
+	if err != nil {
+		b.Fatal(err)
+	}
+	hint := len(data)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, err := largeStructData.MarshalJSONSized(hint)
+		if err != nil {
+			b.Error(err)
+		}
+		if len(data) != hint {
+			b.Errorf("MarshalJSONSized() = %d bytes; want %d", len(data), hint)
+		}
+	}
+	b.SetBytes(int64(hint))
+}
+
+func BenchmarkEJ_AppendJSON_M(b *testing.B) {
+	var buf []byte
+	for i := 0; i < b.N; i++ {
+		buf = largeStructData.AppendJSON(buf[:0])
+	}
+	b.SetBytes(int64(len(buf)))
+}
+
 func BenchmarkEJ_Marshal_L(b *testing.B) {
 	var l int64
 	for i := 0; i < b.N; i++ {
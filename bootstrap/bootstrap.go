@@ -7,7 +7,6 @@ package bootstrap
 
 import (
 	"fmt"
-	"go/format"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -20,6 +19,12 @@ const genPackage = "github.com/mailru/easyjson/gen"
 const pkgWriter = "github.com/mailru/easyjson/jwriter"
 const pkgLexer = "github.com/mailru/easyjson/jlexer"
 
+// nanPolicyNames maps the NaNPolicy values to the non-default jwriter.NaNPolicy constants.
+var nanPolicyNames = map[string]string{
+	"null":   "NaNNull",
+	"string": "NaNString",
+}
+
 var buildFlagsRegexp = regexp.MustCompile("'.+'|\".+\"|\\S+")
 
 type Generator struct {
@@ -28,10 +33,43 @@ type Generator struct {
 
 	NoStdMarshalers          bool
 	SnakeCase                bool
+	KebabCase                bool
 	LowerCamelCase           bool
 	OmitEmpty                bool
+	OmitEmptyIsZero          bool
 	DisallowUnknownFields    bool
+	RejectDuplicateKeys      bool
+	CaseInsensitive          bool
+	SkipUnsupportedFields    bool
 	SkipMemberNameUnescaping bool
+	InternMapKeys            bool
+	StrictArrays             bool
+	TagKey                   string
+	NoEscapeHTML             bool
+	NaNPolicy                string // "error" (default), "null" or "string"
+	FloatFormat              string // strconv format character, empty for the default
+	FloatPrecision           int
+	UseNumber                bool
+	LenientTypes             bool
+	AcceptQuotedNumbers      bool
+	JSON5                    bool
+	NoSortMapKeys            bool
+	SizeEstimator            bool
+	Reset                    bool
+	FuzzHarness              bool
+	AppendJSON               bool
+	MarshalJSONString        bool
+	WriterTo                 bool
+	PooledWriter             bool
+	DurationAsString         bool
+	AppendSlices             bool
+	MaxDepth                 int
+	MaxInputBytes            int
+	ErrorPaths               bool
+	Streaming                bool
+	StreamingDecode          bool
+	IndentPrefix             string
+	Indent                   string
 
 	OutName       string
 	BuildTags     string
@@ -64,6 +102,9 @@ func (g *Generator) writeStub() error {
 	if len(g.Types) > 0 {
 		fmt.Fprintln(f)
 		fmt.Fprintln(f, "import (")
+		if g.Streaming || g.StreamingDecode || g.WriterTo {
+			fmt.Fprintln(f, `  "io"`)
+		}
 		fmt.Fprintln(f, `  "`+pkgWriter+`"`)
 		fmt.Fprintln(f, `  "`+pkgLexer+`"`)
 		fmt.Fprintln(f, ")")
@@ -74,11 +115,33 @@ func (g *Generator) writeStub() error {
 		fmt.Fprintln(f)
 		if !g.NoStdMarshalers {
 			fmt.Fprintln(f, "func (", t, ") MarshalJSON() ([]byte, error) { return nil, nil }")
+			fmt.Fprintln(f, "func (", t, ") MarshalJSONSized(hint int) ([]byte, error) { return nil, nil }")
 			fmt.Fprintln(f, "func (*", t, ") UnmarshalJSON([]byte) error { return nil }")
 		}
 
 		fmt.Fprintln(f, "func (", t, ") MarshalEasyJSON(w *jwriter.Writer) {}")
 		fmt.Fprintln(f, "func (*", t, ") UnmarshalEasyJSON(l *jlexer.Lexer) {}")
+		if g.Streaming {
+			fmt.Fprintln(f, "func (", t, ") EncodeJSON(w io.Writer) error { return nil }")
+		}
+		if g.StreamingDecode {
+			fmt.Fprintln(f, "func (*", t, ") DecodeJSON(r io.Reader) error { return nil }")
+		}
+		if g.AppendJSON {
+			fmt.Fprintln(f, "func (", t, ") AppendJSON(dst []byte) []byte { return nil }")
+		}
+		if g.MarshalJSONString {
+			fmt.Fprintln(f, "func (", t, ") MarshalJSONString() (string, error) { return \"\", nil }")
+		}
+		if g.WriterTo {
+			fmt.Fprintln(f, "func (", t, ") WriteTo(w io.Writer) (int64, error) { return 0, nil }")
+		}
+		if g.Reset {
+			fmt.Fprintln(f, "func (*", t, ") Reset() {}")
+		}
+		if g.FuzzHarness {
+			fmt.Fprintln(f, "func Fuzz"+t+"(data []byte) int { return 0 }")
+		}
 		fmt.Fprintln(f)
 		fmt.Fprintln(f, "type EasyJSON_exporter_"+t+" *"+t)
 	}
@@ -104,6 +167,9 @@ func (g *Generator) writeMain() (path string, err error) {
 	fmt.Fprintln(f, `  "os"`)
 	fmt.Fprintln(f)
 	fmt.Fprintf(f, "  %q\n", genPackage)
+	if nanPolicyNames[g.NaNPolicy] != "" {
+		fmt.Fprintf(f, "  %q\n", pkgWriter)
+	}
 	if len(g.Types) > 0 {
 		fmt.Fprintln(f)
 		fmt.Fprintf(f, "  pkg %q\n", g.PkgPath)
@@ -116,31 +182,127 @@ func (g *Generator) writeMain() (path string, err error) {
 	if g.BuildTags != "" {
 		fmt.Fprintf(f, "  g.SetBuildTags(%q)\n", g.BuildTags)
 	}
+	if g.TagKey != "" {
+		fmt.Fprintf(f, "  g.SetTagKey(%q)\n", g.TagKey)
+	}
 	if g.SnakeCase {
 		fmt.Fprintln(f, "  g.UseSnakeCase()")
 	}
+	if g.KebabCase {
+		fmt.Fprintln(f, "  g.UseKebabCase()")
+	}
 	if g.LowerCamelCase {
 		fmt.Fprintln(f, "  g.UseLowerCamelCase()")
 	}
 	if g.OmitEmpty {
 		fmt.Fprintln(f, "  g.OmitEmpty()")
 	}
+	if g.OmitEmptyIsZero {
+		fmt.Fprintln(f, "  g.OmitEmptyIsZero()")
+	}
 	if g.NoStdMarshalers {
 		fmt.Fprintln(f, "  g.NoStdMarshalers()")
 	}
 	if g.DisallowUnknownFields {
 		fmt.Fprintln(f, "  g.DisallowUnknownFields()")
 	}
+	if g.RejectDuplicateKeys {
+		fmt.Fprintln(f, "  g.RejectDuplicateKeys()")
+	}
+	if g.CaseInsensitive {
+		fmt.Fprintln(f, "  g.SetCaseInsensitive(true)")
+	}
+	if g.SkipUnsupportedFields {
+		fmt.Fprintln(f, "  g.SkipUnsupportedFields()")
+	}
 	if g.SimpleBytes {
 		fmt.Fprintln(f, "  g.SimpleBytes()")
 	}
 	if g.SkipMemberNameUnescaping {
 		fmt.Fprintln(f, "  g.SkipMemberNameUnescaping()")
 	}
+	if g.InternMapKeys {
+		fmt.Fprintln(f, "  g.InternMapKeys()")
+	}
+	if g.StrictArrays {
+		fmt.Fprintln(f, "  g.StrictArrays()")
+	}
+	if g.NoEscapeHTML {
+		fmt.Fprintln(f, "  g.SetHTMLEscape(false)")
+	}
+	if name := nanPolicyNames[g.NaNPolicy]; name != "" {
+		fmt.Fprintln(f, "  g.SetFloatNaNPolicy(jwriter."+name+")")
+	}
+	if g.FloatFormat != "" {
+		fmt.Fprintf(f, "  g.SetFloatFormat(%q, %d)\n", g.FloatFormat[0], g.FloatPrecision)
+	}
+	if g.UseNumber {
+		fmt.Fprintln(f, "  g.SetInterfaceNumberMode(gen.NumberJSONNumber)")
+	}
+	if g.LenientTypes {
+		fmt.Fprintln(f, "  g.SetLenientTypes()")
+	}
+	if g.AcceptQuotedNumbers {
+		fmt.Fprintln(f, "  g.AcceptQuotedNumbers()")
+	}
+	if g.JSON5 {
+		fmt.Fprintln(f, "  g.SetJSON5()")
+	}
+	if g.NoSortMapKeys {
+		fmt.Fprintln(f, "  g.SetMapSortKeys(false)")
+	}
+	if g.SizeEstimator {
+		fmt.Fprintln(f, "  g.GenerateSizeEstimator()")
+	}
+	if g.Reset {
+		fmt.Fprintln(f, "  g.GenerateReset()")
+	}
+	if g.FuzzHarness {
+		fmt.Fprintln(f, "  g.GenerateFuzzHarness()")
+	}
+	if g.AppendJSON {
+		fmt.Fprintln(f, "  g.GenerateAppendJSON()")
+	}
+	if g.MarshalJSONString {
+		fmt.Fprintln(f, "  g.GenerateMarshalJSONString()")
+	}
+	if g.WriterTo {
+		fmt.Fprintln(f, "  g.GenerateWriterTo()")
+	}
+	if g.PooledWriter {
+		fmt.Fprintln(f, "  g.UsePooledWriter()")
+	}
+	if g.DurationAsString {
+		fmt.Fprintln(f, "  g.DurationAsString()")
+	}
+	if g.AppendSlices {
+		fmt.Fprintln(f, "  g.AppendSlices()")
+	}
+	if g.MaxDepth > 0 {
+		fmt.Fprintf(f, "  g.SetMaxDepth(%d)\n", g.MaxDepth)
+	}
+	if g.MaxInputBytes > 0 {
+		fmt.Fprintf(f, "  g.SetMaxInputBytes(%d)\n", g.MaxInputBytes)
+	}
+	if g.ErrorPaths {
+		fmt.Fprintln(f, "  g.SetErrorPaths()")
+	}
+	if g.NoFormat {
+		fmt.Fprintln(f, "  g.SetFormat(false)")
+	}
+	if g.IndentPrefix != "" || g.Indent != "" {
+		fmt.Fprintf(f, "  g.Indent(%q, %q)\n", g.IndentPrefix, g.Indent)
+	}
 
 	sort.Strings(g.Types)
 	for _, v := range g.Types {
 		fmt.Fprintln(f, "  g.Add(pkg.EasyJSON_exporter_"+v+"(nil))")
+		if g.Streaming {
+			fmt.Fprintln(f, "  g.AddStreaming(pkg.EasyJSON_exporter_"+v+"(nil))")
+		}
+		if g.StreamingDecode {
+			fmt.Fprintln(f, "  g.AddStreamingDecoder(pkg.EasyJSON_exporter_"+v+"(nil))")
+		}
 	}
 
 	fmt.Fprintln(f, "  if err := g.Run(os.Stdout); err != nil {")
@@ -198,19 +360,6 @@ func (g *Generator) Run() error {
 	}
 	f.Close()
 
-	// move unformatted file to out path
-	if g.NoFormat {
-		return os.Rename(f.Name(), g.OutName)
-	}
-
-	// format file and write to out path
-	in, err := ioutil.ReadFile(f.Name())
-	if err != nil {
-		return err
-	}
-	out, err := format.Source(in)
-	if err != nil {
-		return err
-	}
-	return ioutil.WriteFile(g.OutName, out, 0644)
+	// the generator formats the code itself unless NoFormat is set
+	return os.Rename(f.Name(), g.OutName)
 }
@@ -72,10 +72,61 @@ type Buffer struct {
 
 	toPool []byte
 	bufs   [][]byte
+
+	sink    io.Writer
+	sinkErr error
+
+	// set when Buf was given by the user, it is then grown like with append
+	// rather than chained with pooled chunks
+	userBuf bool
+}
+
+// UseBuf makes the buffer append the data to buf, which is grown by reallocation
+// like with append instead of being continued in new chunks, so that BuildBytes
+// returns buf extended with the data. The data has to be retrieved with
+// BuildBytes, which never puts buf into the reuse pool.
+func (b *Buffer) UseBuf(buf []byte) {
+	b.Buf = buf
+	b.toPool = nil
+	b.userBuf = true
+}
+
+// SetSink makes the buffer write filled chunks out to w as soon as a new chunk is
+// started, so that only the current chunk is kept in memory. Flush has to be called
+// to write out the rest of the data.
+func (b *Buffer) SetSink(w io.Writer) {
+	b.sink = w
+}
+
+// flushBufs writes the filled chunks out to the sink and releases them.
+func (b *Buffer) flushBufs() {
+	for _, buf := range b.bufs {
+		if b.sinkErr == nil {
+			_, b.sinkErr = b.sink.Write(buf)
+		}
+		putBuf(buf)
+	}
+	b.bufs = b.bufs[:0]
+}
+
+// Flush writes all the buffered data out to the sink and returns the first error
+// that occurred while writing to it.
+func (b *Buffer) Flush() error {
+	if b.sink == nil {
+		return nil
+	}
+	b.flushBufs()
+	if len(b.Buf) > 0 && b.sinkErr == nil {
+		_, b.sinkErr = b.sink.Write(b.Buf)
+	}
+	b.Buf = b.Buf[:0]
+	return b.sinkErr
 }
 
 // EnsureSpace makes sure that the current chunk contains at least s free bytes,
-// possibly creating a new chunk.
+// possibly creating a new chunk. New chunks are never larger than the maximum
+// chunk size, so calling it on an empty buffer can be used to pre-size the first
+// chunk for the expected size of the data.
 func (b *Buffer) EnsureSpace(s int) {
 	if cap(b.Buf)-len(b.Buf) < s {
 		b.ensureSpaceSlow(s)
@@ -83,6 +134,13 @@ func (b *Buffer) EnsureSpace(s int) {
 }
 
 func (b *Buffer) ensureSpaceSlow(s int) {
+	if b.userBuf {
+		buf := make([]byte, len(b.Buf), 2*cap(b.Buf)+s)
+		copy(buf, b.Buf)
+		b.Buf = buf
+		return
+	}
+
 	l := len(b.Buf)
 	if l > 0 {
 		if cap(b.toPool) != cap(b.Buf) {
@@ -94,9 +152,15 @@ func (b *Buffer) ensureSpaceSlow(s int) {
 		}
 		b.bufs = append(b.bufs, b.Buf)
 		l = cap(b.toPool) * 2
+		if b.sink != nil {
+			b.flushBufs()
+		}
 	} else {
 		l = config.StartSize
 	}
+	for l < s && l < config.MaxSize {
+		l *= 2
+	}
 
 	if l > config.MaxSize {
 		l = config.MaxSize
@@ -182,6 +246,7 @@ func (b *Buffer) DumpTo(w io.Writer) (written int, err error) {
 	b.bufs = nil
 	b.Buf = nil
 	b.toPool = nil
+	b.userBuf = false
 
 	return int(n), err
 }
@@ -194,6 +259,7 @@ func (b *Buffer) BuildBytes(reuse ...[]byte) []byte {
 		ret := b.Buf
 		b.toPool = nil
 		b.Buf = nil
+		b.userBuf = false
 		return ret
 	}
 
@@ -105,3 +105,71 @@ func TestReadCloser(t *testing.T) {
 		t.Errorf("DumpTo() = %v; want %v", n, len(want))
 	}
 }
+
+func TestSink(t *testing.T) {
+	var sink bytes.Buffer
+	var want []byte
+
+	var b Buffer
+	b.SetSink(&sink)
+	for i := 0; i < 100000; i++ {
+		b.AppendString("abcdef")
+		want = append(want, "abcdef"...)
+	}
+
+	if b.Size() > config.MaxSize {
+		t.Errorf("Size() = %d; want at most %d", b.Size(), config.MaxSize)
+	}
+	if err := b.Flush(); err != nil {
+		t.Errorf("Flush() error: %v", err)
+	}
+	if !bytes.Equal(sink.Bytes(), want) {
+		t.Errorf("sink got %d bytes; want %d", sink.Len(), len(want))
+	}
+}
+
+func TestEnsureSpacePresize(t *testing.T) {
+	for _, test := range []struct {
+		size    int
+		wantCap int
+	}{
+		{size: 0, wantCap: 0},
+		{size: 100, wantCap: config.StartSize},
+		{size: 1000, wantCap: 1024},
+		{size: 4096, wantCap: 4096},
+		{size: 10 * config.MaxSize, wantCap: config.MaxSize},
+	} {
+		var b Buffer
+		b.EnsureSpace(test.size)
+		if cap(b.Buf) != test.wantCap {
+			t.Errorf("EnsureSpace(%d) allocated %d bytes; want %d", test.size, cap(b.Buf), test.wantCap)
+		}
+
+		data := bytes.Repeat([]byte{'x'}, test.size)
+		b.AppendBytes(data)
+		if got := b.BuildBytes(); !bytes.Equal(got, data) {
+			t.Errorf("BuildBytes() after EnsureSpace(%d) = %d bytes; want %d", test.size, len(got), len(data))
+		}
+	}
+}
+
+func TestUseBuf(t *testing.T) {
+	for _, size := range []int{0, 10, 1000, 10 * config.MaxSize} {
+		dst := make([]byte, 3, 16)
+		copy(dst, "abc")
+		want := append([]byte("abc"), bytes.Repeat([]byte{'x'}, size)...)
+
+		var b Buffer
+		b.UseBuf(dst)
+		for i := 0; i < size; i++ {
+			b.AppendByte('x')
+		}
+		got := b.BuildBytes()
+		if !bytes.Equal(got, want) {
+			t.Errorf("BuildBytes() after UseBuf() and %d bytes = %d bytes; want %d", size, len(got), len(want))
+		}
+		if size <= cap(dst)-len(dst) && &got[0] != &dst[0] {
+			t.Errorf("BuildBytes() after UseBuf() and %d bytes did not reuse the buffer", size)
+		}
+	}
+}
@@ -19,9 +19,11 @@ import (
 var buildTags = flag.String("build_tags", "", "build tags to add to generated file")
 var genBuildFlags = flag.String("gen_build_flags", "", "build flags when running the generator while bootstrapping")
 var snakeCase = flag.Bool("snake_case", false, "use snake_case names instead of CamelCase by default")
+var kebabCase = flag.Bool("kebab_case", false, "use kebab-case names instead of CamelCase by default")
 var lowerCamelCase = flag.Bool("lower_camel_case", false, "use lowerCamelCase names instead of CamelCase by default")
 var noStdMarshalers = flag.Bool("no_std_marshalers", false, "don't generate MarshalJSON/UnmarshalJSON funcs")
 var omitEmpty = flag.Bool("omit_empty", false, "omit empty fields by default")
+var omitEmptyIsZero = flag.Bool("omit_empty_is_zero", false, "omit omitempty fields of types with an IsZero method when it returns true")
 var allStructs = flag.Bool("all", false, "generate marshaler/unmarshalers for all structs in a file")
 var simpleBytes = flag.Bool("byte", false, "use simple bytes instead of Base64Bytes for slice of bytes")
 var leaveTemps = flag.Bool("leave_temps", false, "do not delete temporary files")
@@ -30,7 +32,38 @@ var noformat = flag.Bool("noformat", false, "do not run 'gofmt -w' on output fil
 var specifiedName = flag.String("output_filename", "", "specify the filename of the output")
 var processPkg = flag.Bool("pkg", false, "process the whole package instead of just the given file")
 var disallowUnknownFields = flag.Bool("disallow_unknown_fields", false, "return error if any unknown field in json appeared")
+var rejectDuplicateKeys = flag.Bool("reject_duplicate_keys", false, "return error if the key of a field appears more than once in an object")
+var caseInsensitive = flag.Bool("case_insensitive", false, "match json keys to struct fields ignoring case if no field matches exactly")
+var skipUnsupportedFields = flag.Bool("skip_unsupported_fields", false, "skip fields of chan, func and complex types instead of failing")
 var skipMemberNameUnescaping = flag.Bool("disable_members_unescape", false, "don't perform unescaping of member names to improve performance")
+var internMapKeys = flag.Bool("intern_map_keys", false, "intern string map keys when decoding to reduce allocations")
+var strictArrays = flag.Bool("strict_arrays", false, "return error if a json array is decoded into a go array of a different length")
+var tagKey = flag.String("tag_key", "", "struct tag key to read field names and options from instead of 'json'")
+var noEscapeHTML = flag.Bool("no_escape_html", false, "don't escape '<', '>' and '&' in strings written by MarshalJSON")
+var streaming = flag.Bool("streaming", false, "generate EncodeJSON methods that write to an io.Writer while encoding")
+var streamingDecode = flag.Bool("streaming_decode", false, "generate DecodeJSON methods that read from an io.Reader while decoding")
+var indent = flag.String("indent", "", "indent MarshalJSON output with the given string, like json.MarshalIndent")
+var nanPolicy = flag.String("nan_policy", "error", "how MarshalJSON writes NaN and infinite floats: 'error', 'null' or 'string'")
+var floatFormat = flag.String("float_format", "", "strconv format MarshalJSON writes floats in: 'e', 'E', 'f', 'g' or 'G', the shortest form of encoding/json by default")
+var floatPrecision = flag.Int("float_precision", -1, "precision of floats written in -float_format, -1 for the fewest digits reading back as the same value")
+var useNumber = flag.Bool("use_number", false, "decode numbers in interface{} values as json.Number rather than float64")
+var lenientTypes = flag.Bool("lenient_types", false, "accept quoted numbers for numeric fields and 0 or 1 for bool fields when decoding")
+var acceptQuotedNumbers = flag.Bool("accept_quoted_numbers", false, "accept numbers and bools enclosed in strings, like \"5\" and \"true\", for all numeric and bool fields when decoding")
+var json5 = flag.Bool("json5", false, "accept unquoted keys and trailing commas in objects and arrays when decoding")
+var noSortMapKeys = flag.Bool("no_sort_map_keys", false, "don't sort map keys when encoding, saving time when the order doesn't matter")
+var appendJSON = flag.Bool("append_json", false, "generate AppendJSON methods appending the JSON encoding to a byte slice")
+var marshalJSONString = flag.Bool("marshal_json_string", false, "generate MarshalJSONString methods returning the JSON encoding quoted as a JSON string")
+var writerTo = flag.Bool("writer_to", false, "generate WriteTo methods implementing io.WriterTo by writing out the encoded buffer")
+var pooledWriter = flag.Bool("pooled_writer", false, "make MarshalJSON reuse writers and their buffers from a pool")
+var durationAsString = flag.Bool("duration_as_string", false, "encode time.Duration values as strings like \"1h30m0s\" rather than nanoseconds")
+var appendSlices = flag.Bool("append_slices", false, "make decoders append the elements of arrays to slices rather than replacing their contents")
+var maxDepth = flag.Int("max_depth", 0, "make decoders fail on input nested deeper than this many levels, 0 for no limit")
+var maxInputBytes = flag.Int("max_input_bytes", 0, "make decoders fail on input longer than this many bytes, 0 for no limit")
+var errorPaths = flag.Bool("error_paths", false, "report the JSON path of the value, like $.items[1].name, in decoding errors")
+var reset = flag.Bool("reset", false, "generate Reset methods setting values to zero for reuse, keeping the capacity of slices")
+var fuzzHarness = flag.Bool("fuzz_harness", false, "generate go-fuzz funcs FuzzT round-tripping input through UnmarshalJSON and MarshalJSON")
+var sizeEstimator = flag.Bool("size_estimator", false, "generate estimatedSize methods walking values to size the MarshalJSON buffer")
+var indentPrefix = flag.String("indent_prefix", "", "prefix for lines of indented MarshalJSON output")
 
 func generate(fname string) (err error) {
 	fInfo, err := os.Stat(fname)
@@ -75,11 +108,44 @@ func generate(fname string) (err error) {
 		PkgName:                  p.PkgName,
 		Types:                    p.StructNames,
 		SnakeCase:                *snakeCase,
+		KebabCase:                *kebabCase,
 		LowerCamelCase:           *lowerCamelCase,
 		NoStdMarshalers:          *noStdMarshalers,
 		DisallowUnknownFields:    *disallowUnknownFields,
+		RejectDuplicateKeys:      *rejectDuplicateKeys,
+		CaseInsensitive:          *caseInsensitive,
+		SkipUnsupportedFields:    *skipUnsupportedFields,
 		SkipMemberNameUnescaping: *skipMemberNameUnescaping,
+		InternMapKeys:            *internMapKeys,
+		StrictArrays:             *strictArrays,
+		TagKey:                   *tagKey,
+		NoEscapeHTML:             *noEscapeHTML,
+		NaNPolicy:                *nanPolicy,
+		FloatFormat:              *floatFormat,
+		FloatPrecision:           *floatPrecision,
+		UseNumber:                *useNumber,
+		LenientTypes:             *lenientTypes,
+		AcceptQuotedNumbers:      *acceptQuotedNumbers,
+		JSON5:                    *json5,
+		NoSortMapKeys:            *noSortMapKeys,
+		SizeEstimator:            *sizeEstimator,
+		Reset:                    *reset,
+		FuzzHarness:              *fuzzHarness,
+		AppendJSON:               *appendJSON,
+		MarshalJSONString:        *marshalJSONString,
+		WriterTo:                 *writerTo,
+		PooledWriter:             *pooledWriter,
+		DurationAsString:         *durationAsString,
+		AppendSlices:             *appendSlices,
+		MaxDepth:                 *maxDepth,
+		MaxInputBytes:            *maxInputBytes,
+		ErrorPaths:               *errorPaths,
+		Streaming:                *streaming,
+		StreamingDecode:          *streamingDecode,
+		IndentPrefix:             *indentPrefix,
+		Indent:                   *indent,
 		OmitEmpty:                *omitEmpty,
+		OmitEmptyIsZero:          *omitEmptyIsZero,
 		LeaveTemps:               *leaveTemps,
 		OutName:                  outName,
 		StubsOnly:                *stubs,
@@ -96,6 +162,19 @@ func generate(fname string) (err error) {
 func main() {
 	flag.Parse()
 
+	switch *nanPolicy {
+	case "error", "null", "string":
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid -nan_policy %q: must be 'error', 'null' or 'string'\n", *nanPolicy)
+		os.Exit(1)
+	}
+	switch *floatFormat {
+	case "", "e", "E", "f", "g", "G":
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid -float_format %q: must be 'e', 'E', 'f', 'g' or 'G'\n", *floatFormat)
+		os.Exit(1)
+	}
+
 	files := flag.Args()
 
 	gofile := os.Getenv("GOFILE")
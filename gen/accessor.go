@@ -0,0 +1,81 @@
+package gen
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// fieldAccessor holds the names of the methods registered for a field with
+// RegisterFieldAccessor.
+type fieldAccessor struct {
+	getter string
+	setter string
+}
+
+// RegisterFieldAccessor makes the generated code access the field fieldName of
+// the struct type t through methods of t instead of the field itself, which also
+// makes unexported fields encoded and decoded, e.g. for types of other packages.
+// The value is read with in.<getterExpr>() and written with out.<setterExpr>(v),
+// where v has the type of the field. An empty getter leaves the field out of
+// encoding, an empty setter drops its value when decoding. The field is named
+// and tagged like exported ones are.
+func (g *Generator) RegisterFieldAccessor(t reflect.Type, fieldName, getterExpr, setterExpr string) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if g.fieldAccessors[t] == nil {
+		g.fieldAccessors[t] = make(map[string]fieldAccessor)
+	}
+	g.fieldAccessors[t][fieldName] = fieldAccessor{getter: getterExpr, setter: setterExpr}
+}
+
+// fieldAccessor returns the accessor registered for the field f of the struct
+// type t, which applies to the fields of t itself only, not promoted ones.
+func (g *Generator) fieldAccessor(t reflect.Type, f reflect.StructField) (fieldAccessor, bool) {
+	if len(f.Index) != 1 {
+		return fieldAccessor{}, false
+	}
+	a, ok := g.fieldAccessors[t][f.Name]
+	return a, ok
+}
+
+// addAccessorFields adds the fields of the struct type t registered with
+// RegisterFieldAccessor to the fields fs from getStructFields, in the order they
+// are declared in, unless they are there already.
+func (g *Generator) addAccessorFields(t reflect.Type, fs []reflect.StructField) ([]reflect.StructField, error) {
+	accessors := g.fieldAccessors[t]
+	if len(accessors) == 0 {
+		return fs, nil
+	}
+	for name := range accessors {
+		f, ok := t.FieldByName(name)
+		if !ok || len(f.Index) != 1 {
+			return nil, fmt.Errorf("field %v registered with an accessor not found in %v", name, t)
+		}
+	}
+
+	var ret []reflect.StructField
+	i := 0
+	for j := 0; j < t.NumField(); j++ {
+		for ; i < len(fs) && len(fs[i].Index) == 1 && fs[i].Index[0] <= j; i++ {
+			ret = append(ret, fs[i])
+		}
+		f := t.Field(j)
+		if _, ok := accessors[f.Name]; ok && f.PkgPath != "" {
+			if parseFieldTags(f, g.tagKey).omit {
+				continue
+			}
+			ret = append(ret, f)
+		}
+	}
+	return append(ret, fs[i:]...), nil
+}
+
+// fieldAssign returns the statement setting the field f of the struct type t
+// decoded into to value.
+func (g *Generator) fieldAssign(t reflect.Type, f reflect.StructField, value string) string {
+	if a, ok := g.fieldAccessor(t, f); ok {
+		return "out." + a.setter + "(" + value + ")"
+	}
+	return "out." + fieldSelector(t, f) + " = " + value
+}
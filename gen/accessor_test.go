@@ -0,0 +1,74 @@
+package gen
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type accessorAccount struct {
+	ID      int
+	balance int
+	tags    []string
+	secret  string
+}
+
+func (a accessorAccount) Balance() int           { return a.balance }
+func (a *accessorAccount) SetBalance(b int)      { a.balance = b }
+func (a accessorAccount) Tags() []string         { return a.tags }
+func (a *accessorAccount) SetTags(tags []string) { a.tags = tags }
+
+func TestRegisterFieldAccessor(t *testing.T) {
+	g := NewGenerator("account.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	typ := reflect.TypeOf(accessorAccount{})
+	g.RegisterFieldAccessor(typ, "balance", "Balance", "SetBalance")
+	g.RegisterFieldAccessor(reflect.PtrTo(typ), "tags", "Tags", "SetTags")
+	g.Add(accessorAccount{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	code := out.String()
+	if _, err := parser.ParseFile(token.NewFileSet(), "account_easyjson.go", code, 0); err != nil {
+		t.Fatalf("Run() output does not parse: %v\n%s", err, code)
+	}
+	for _, want := range []string{
+		`case "balance":`,
+		":= in.Balance()",
+		":= out.Balance()",
+		"out.SetBalance(",
+		`case "tags":`,
+		":= in.Tags()",
+		"out.SetTags(nil)",
+		`",\"tags\":"`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Run() output does not contain %q:\n%s", want, code)
+		}
+	}
+	for _, unwanted := range []string{"in.balance", "out.balance", "in.tags", "out.tags", "secret"} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("Run() output contains %q:\n%s", unwanted, code)
+		}
+	}
+	if strings.Index(code, `"ID"`) > strings.Index(code, `"balance"`) {
+		t.Errorf("Run() output does not keep the declaration order of fields:\n%s", code)
+	}
+}
+
+func TestRegisterFieldAccessorUnknownField(t *testing.T) {
+	g := NewGenerator("account.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.RegisterFieldAccessor(reflect.TypeOf(accessorAccount{}), "missing", "Missing", "SetMissing")
+	g.Add(accessorAccount{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("Run() error = %v; want an error about the missing field", err)
+	}
+}
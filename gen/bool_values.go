@@ -0,0 +1,45 @@
+package gen
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// SetBoolDecodeMap makes the generated decoders accept the strings and numbers
+// which are keys of values as the bools they map to, besides true and false, as
+// needed for producers sending "Y" and 0, e.g. with map[string]bool{"Y": true,
+// "N": false, "1": true, "0": false}. Strings are matched by their value, numbers
+// by their text in the input.
+func (g *Generator) SetBoolDecodeMap(values map[string]bool) {
+	g.boolDecodeMap = make(map[string]bool, len(values))
+	for s, b := range values {
+		g.boolDecodeMap[s] = b
+	}
+}
+
+// boolDecodeMapVar returns the name of the variable holding the map set with
+// SetBoolDecodeMap, which is generated once the code refers to it.
+func (g *Generator) boolDecodeMapVar() string {
+	g.boolDecodeMapUsed = true
+	return joinFunctionNameParts(true, "easyjson", g.hashString, "boolValues")
+}
+
+// genBoolDecodeMap generates the variable holding the map set with
+// SetBoolDecodeMap.
+func (g *Generator) genBoolDecodeMap(out io.Writer) {
+	keys := make([]string, 0, len(g.boolDecodeMap))
+	for s := range g.boolDecodeMap {
+		keys = append(keys, s)
+	}
+	sort.Strings(keys)
+
+	name := g.boolDecodeMapVar()
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "// "+name+" maps the strings and numbers decoded as bools to them.")
+	fmt.Fprintln(out, "var "+name+" = map[string]bool{")
+	for _, s := range keys {
+		fmt.Fprintf(out, "  %q: %v,\n", s, g.boolDecodeMap[s])
+	}
+	fmt.Fprintln(out, "}")
+}
@@ -0,0 +1,89 @@
+package gen
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// typeRef is a type requested by name with AddByName.
+type typeRef struct {
+	pkgPath string
+	name    string
+}
+
+// AddByName requests to generate marshalers/unmarshalers and encoding/decoding
+// funcs for the type typeName of the package with import path pkgPath, like Add
+// does for the type of an object, for callers which cannot refer to the type
+// itself. An empty pkgPath stands for the package set with SetPkg.
+//
+// As the generator works with reflect types, the name is only resolved by Run or
+// RunSplit, among the types reachable from the ones added by value or registered
+// with RegisterInterfaceImpl: through struct fields and the elements of pointers,
+// slices, arrays and maps. They fail if no such type has the name.
+func (g *Generator) AddByName(pkgPath, typeName string) {
+	g.typesByName = append(g.typesByName, typeRef{pkgPath: pkgPath, name: typeName})
+}
+
+// resolveTypesByName adds the types requested with AddByName.
+func (g *Generator) resolveTypesByName() error {
+	if len(g.typesByName) == 0 {
+		return nil
+	}
+
+	var roots []reflect.Type
+	for t := range g.marshalers {
+		roots = append(roots, t)
+	}
+	for t := range g.unmarshalers {
+		roots = append(roots, t)
+	}
+	for _, impl := range g.interfaceImpls {
+		for _, t := range impl.impls {
+			roots = append(roots, t)
+		}
+	}
+
+	found := make(map[typeRef]reflect.Type)
+	seen := make(map[reflect.Type]bool)
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		if seen[t] {
+			return
+		}
+		seen[t] = true
+
+		if t.Name() != "" {
+			found[typeRef{pkgPath: fixPkgPathVendoring(t.PkgPath()), name: t.Name()}] = t
+		}
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			walk(t.Elem())
+		case reflect.Map:
+			walk(t.Key())
+			walk(t.Elem())
+		case reflect.Struct:
+			for i := 0; i < t.NumField(); i++ {
+				walk(t.Field(i).Type)
+			}
+		}
+	}
+	for _, t := range roots {
+		walk(t)
+	}
+
+	for _, ref := range g.typesByName {
+		if ref.pkgPath == "" {
+			ref.pkgPath = g.pkgPath
+		}
+		t, ok := found[ref]
+		if !ok {
+			return fmt.Errorf("type %v.%v added by name is not reachable from the types added by value", ref.pkgPath, ref.name)
+		}
+		g.addEncoderType(t)
+		g.marshalers[t] = true
+		g.addDecoderType(t)
+		g.unmarshalers[t] = true
+	}
+	g.typesByName = nil
+	return nil
+}
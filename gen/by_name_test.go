@@ -0,0 +1,110 @@
+package gen
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type byNameShape interface {
+	Area() float64
+}
+
+type byNameSquare struct {
+	Side  float64
+	Color byNameColor
+}
+
+func (s byNameSquare) Area() float64 { return s.Side * s.Side }
+
+type byNameColor struct {
+	Name string
+}
+
+type byNameHolder struct {
+	Shapes []byNameShape
+}
+
+func TestAddByName(t *testing.T) {
+	g := NewGenerator("shapes.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.RegisterInterfaceImpl(reflect.TypeOf((*byNameShape)(nil)).Elem(), "kind", map[string]reflect.Type{
+		"square": reflect.TypeOf(byNameSquare{}),
+	})
+	g.AddByName("github.com/mailru/easyjson/gen", "byNameSquare")
+	g.AddByName("", "byNameColor")
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	code := out.String()
+	if _, err := parser.ParseFile(token.NewFileSet(), "shapes_easyjson.go", code, 0); err != nil {
+		t.Fatalf("Run() output does not parse: %v\n%s", err, code)
+	}
+	for _, want := range []string{
+		"func (v byNameSquare) MarshalJSON() ([]byte, error)",
+		"func (v *byNameSquare) UnmarshalJSON(data []byte) error",
+		"func (v byNameColor) MarshalJSON() ([]byte, error)",
+		"func (v *byNameColor) UnmarshalJSON(data []byte) error",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Run() output does not contain %q:\n%s", want, code)
+		}
+	}
+}
+
+func TestRunSplitAddByName(t *testing.T) {
+	g := NewGenerator("shapes.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.RegisterInterfaceImpl(reflect.TypeOf((*byNameShape)(nil)).Elem(), "kind", map[string]reflect.Type{
+		"square": reflect.TypeOf(byNameSquare{}),
+	})
+	g.AddByName("", "byNameSquare")
+	g.AddByName("", "byNameColor")
+
+	files, err := g.RunSplit()
+	if err != nil {
+		t.Fatalf("RunSplit() error: %v", err)
+	}
+	for name, want := range map[string]string{
+		"by_name_square_easyjson.go": "func (v byNameSquare) MarshalJSON() ([]byte, error)",
+		"by_name_color_easyjson.go":  "func (v byNameColor) MarshalJSON() ([]byte, error)",
+	} {
+		if code := string(files[name]); !strings.Contains(code, want) {
+			t.Errorf("RunSplit() file %v does not contain %q:\n%s", name, want, code)
+		}
+	}
+}
+
+func TestAddByNameUnreachable(t *testing.T) {
+	for _, test := range []struct {
+		pkgPath, name string
+	}{
+		{"github.com/mailru/easyjson/gen", "byNameSquare"},
+		{"github.com/mailru/easyjson/other", "byNameColor"},
+		{"", "byNameMissing"},
+	} {
+		g := NewGenerator("shapes.go")
+		g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+		g.Add(byNameColor{})
+		g.AddByName(test.pkgPath, test.name)
+
+		var out bytes.Buffer
+		err := g.Run(&out)
+		if err == nil || !strings.Contains(err.Error(), test.name) {
+			t.Errorf("Run() with %v.%v added by name error = %v; want an error naming the type", test.pkgPath, test.name, err)
+		}
+	}
+
+	g := NewGenerator("holder.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.Add(byNameHolder{})
+	g.AddByName("", "byNameShape")
+	if err := g.Run(&bytes.Buffer{}); err == nil {
+		t.Errorf("Run() with byNameShape added by name ok; want error for an interface type")
+	}
+}
@@ -0,0 +1,43 @@
+package gen
+
+import (
+	"reflect"
+	"strings"
+)
+
+// customCodec holds the references to the hand-written funcs registered for a
+// type with RegisterCustomCodec.
+type customCodec struct {
+	encode string
+	decode string
+}
+
+// RegisterCustomCodec makes the generated code encode and decode values of type t
+// by calling the given funcs instead of generating code for t, e.g. for types of
+// other packages which cannot be annotated. Like the funcs generated for types,
+// the encode func is called as encode(out *jwriter.Writer, v T) and the decode
+// func as decode(in *jlexer.Lexer, v *T), which has to consume the value, a null
+// included.
+//
+// The func references are spliced into the generated code as they are if they
+// name a func of the output package. A reference qualified with an import path,
+// like "example.com/uuidjson.Encode", refers to a func of that package, which is
+// imported as needed. An empty reference keeps the code generated for t in that
+// direction.
+func (g *Generator) RegisterCustomCodec(t reflect.Type, encodeFuncExpr, decodeFuncExpr string) {
+	g.customCodecs[t] = customCodec{encode: encodeFuncExpr, decode: decodeFuncExpr}
+}
+
+// funcRef returns the expression referring to the func ref registered with
+// RegisterCustomCodec in the generated code.
+func (g *Generator) funcRef(ref string) string {
+	i := strings.LastIndex(ref, ".")
+	if i == -1 {
+		return ref
+	}
+	pkgPath, name := ref[:i], ref[i+1:]
+	if g.isOwnPkg(pkgPath) {
+		return name
+	}
+	return g.pkgAlias(pkgPath) + "." + name
+}
@@ -0,0 +1,75 @@
+package gen
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// UUID stands for a third-party type like github.com/google/uuid.UUID, which
+// implements encoding.TextMarshaler.
+type UUID [16]byte
+
+func (u UUID) MarshalText() ([]byte, error) { return nil, nil }
+
+func (u *UUID) UnmarshalText(data []byte) error { return nil }
+
+type customCodecRecord struct {
+	ID      UUID
+	Parent  *UUID
+	Aliases []UUID
+}
+
+func TestRegisterCustomCodec(t *testing.T) {
+	g := NewGenerator("record.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.RegisterCustomCodec(reflect.TypeOf(UUID{}), "example.com/uuidjson.Encode", "example.com/uuidjson.Decode")
+	g.Add(customCodecRecord{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	code := out.String()
+	if _, err := parser.ParseFile(token.NewFileSet(), "record_easyjson.go", code, 0); err != nil {
+		t.Fatalf("Run() output does not parse: %v\n%s", err, code)
+	}
+	for _, want := range []string{
+		`uuidjson "example.com/uuidjson"`,
+		"uuidjson.Encode(out, in.ID)",
+		"uuidjson.Decode(in, &out.ID)",
+		"uuidjson.Encode(out, *in.Parent)",
+		"uuidjson.Decode(in, &*out.Parent)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Run() output does not contain %q:\n%s", want, code)
+		}
+	}
+	for _, unwanted := range []string{"MarshalText", "UnmarshalText"} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("Run() output contains %q:\n%s", unwanted, code)
+		}
+	}
+}
+
+func TestRegisterCustomCodecOwnPkg(t *testing.T) {
+	g := NewGenerator("record.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.RegisterCustomCodec(reflect.TypeOf(UUID{}), "github.com/mailru/easyjson/gen.encodeUUID", "")
+	g.Add(customCodecRecord{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	code := out.String()
+	if !strings.Contains(code, "encodeUUID(out, in.ID)") || strings.Contains(code, "gen.encodeUUID") {
+		t.Errorf("Run() output does not call encodeUUID unqualified:\n%s", code)
+	}
+	if !strings.Contains(code, "UnmarshalText") {
+		t.Errorf("Run() output does not decode UUID with its UnmarshalText method:\n%s", code)
+	}
+}
@@ -1,11 +1,14 @@
 package gen
 
 import (
+	"bytes"
 	"encoding"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -32,12 +35,14 @@ var primitiveDecoders = map[reflect.Kind]string{
 	reflect.Uint16:  "in.Uint16()",
 	reflect.Uint32:  "in.Uint32()",
 	reflect.Uint64:  "in.Uint64()",
+	reflect.Uintptr: "in.Uintptr()",
 	reflect.Float32: "in.Float32()",
 	reflect.Float64: "in.Float64()",
 }
 
 var primitiveStringDecoders = map[reflect.Kind]string{
 	reflect.String:  "in.String()",
+	reflect.Bool:    "in.BoolStr()",
 	reflect.Int:     "in.IntStr()",
 	reflect.Int8:    "in.Int8Str()",
 	reflect.Int16:   "in.Int16Str()",
@@ -53,14 +58,96 @@ var primitiveStringDecoders = map[reflect.Kind]string{
 	reflect.Float64: "in.Float64Str()",
 }
 
-var customDecoders = map[string]string{
-	"json.Number": "in.JsonNumber()",
+// genEnterElement generates the call of the lexer method entering an element of
+// the JSON path if error paths are tracked.
+func (g *Generator) genEnterElement(ws, call string) {
+	if g.errorPaths {
+		fmt.Fprintln(g.out, ws+"in."+call)
+	}
+}
+
+// genLeaveElement generates the call of the lexer method leaving the innermost
+// element of the JSON path if error paths are tracked.
+func (g *Generator) genLeaveElement(ws string) {
+	if g.errorPaths {
+		fmt.Fprintln(g.out, ws+"in.LeaveElement()")
+	}
+}
+
+// mapKeyString returns the expression converting the decoded map key of type t
+// to the string shown in the JSON path.
+func (g *Generator) mapKeyString(t reflect.Type, key string) string {
+	if t.Kind() == reflect.String {
+		return "string(" + key + ")"
+	}
+	if g.errorPaths {
+		g.useImport("fmt", "fmt")
+	}
+	return "fmt.Sprint(" + key + ")"
 }
 
 // genTypeDecoder generates decoding code for the type t, but uses unmarshaler interface if implemented by t.
 func (g *Generator) genTypeDecoder(t reflect.Type, out string, tags fieldTags, indent int) error {
 	ws := strings.Repeat("  ", indent)
 
+	if c, ok := g.customCodecs[t]; ok && c.decode != "" {
+		fmt.Fprintln(g.out, ws+g.funcRef(c.decode)+"(in, &"+out+")")
+		return nil
+	}
+	if t == timeType {
+		fmt.Fprintln(g.out, ws+"if in.IsNull() {")
+		fmt.Fprintln(g.out, ws+"  in.Skip()")
+		fmt.Fprintln(g.out, ws+"} else {")
+		switch tags.timeFormat {
+		case timeFormatUnix:
+			fmt.Fprintln(g.out, ws+"  "+out+" = in.UnixTime()")
+		case timeFormatUnixMilli:
+			fmt.Fprintln(g.out, ws+"  "+out+" = in.UnixMilliTime()")
+		default:
+			fmt.Fprintln(g.out, ws+"  "+out+" = in.Time("+g.timeLayout(tags, "RFC3339")+")")
+		}
+		fmt.Fprintln(g.out, ws+"}")
+		return nil
+	}
+	if t == durationType && g.durationAsString {
+		fmt.Fprintln(g.out, ws+"if in.IsNull() {")
+		fmt.Fprintln(g.out, ws+"  in.Skip()")
+		fmt.Fprintln(g.out, ws+"} else {")
+		fmt.Fprintln(g.out, ws+"  "+out+" = in.Duration()")
+		fmt.Fprintln(g.out, ws+"}")
+		return nil
+	}
+	if t == bigIntType || t == bigFloatType {
+		fmt.Fprintln(g.out, ws+"if in.IsNull() {")
+		fmt.Fprintln(g.out, ws+"  in.Skip()")
+		fmt.Fprintln(g.out, ws+"} else {")
+		fmt.Fprintln(g.out, ws+"  in.Big"+t.Name()+"(&("+out+"))")
+		fmt.Fprintln(g.out, ws+"}")
+		return nil
+	}
+	if f, ok := sqlNullValue(t); ok {
+		fmt.Fprintln(g.out, ws+"if in.IsNull() {")
+		fmt.Fprintln(g.out, ws+"  in.Skip()")
+		fmt.Fprintln(g.out, ws+"  "+out+" = "+g.nullValue(t))
+		fmt.Fprintln(g.out, ws+"} else {")
+		if err := g.genTypeDecoder(f.Type, "("+out+")."+f.Name, tags, indent+1); err != nil {
+			return err
+		}
+		fmt.Fprintln(g.out, ws+"  ("+out+").Valid = true")
+		fmt.Fprintln(g.out, ws+"}")
+		return nil
+	}
+	if t == rawMessageType {
+		fmt.Fprintln(g.out, ws+"if data := in.Raw(); in.Ok() {")
+		if tags.noCopy {
+			fmt.Fprintln(g.out, ws+"  "+out+" = data")
+		} else {
+			fmt.Fprintln(g.out, ws+"  "+out+" = append(("+out+")[:0], data...)")
+		}
+		fmt.Fprintln(g.out, ws+"}")
+		return nil
+	}
+
 	unmarshalerIface := reflect.TypeOf((*easyjson.Unmarshaler)(nil)).Elem()
 	if reflect.PtrTo(t).Implements(unmarshalerIface) {
 		fmt.Fprintln(g.out, ws+"("+out+").UnmarshalEasyJSON(in)")
@@ -100,17 +187,44 @@ func hasUnknownsUnmarshaler(t reflect.Type) bool {
 	return t.Implements(reflect.TypeOf((*easyjson.UnknownsUnmarshaler)(nil)).Elem())
 }
 
+// unknownFieldCall returns the call passing the current key matching no field of
+// the struct type t and its value to the method of t handling them, if t has one.
+func unknownFieldCall(t reflect.Type) string {
+	pt := reflect.PtrTo(t)
+	switch {
+	case pt.Implements(reflect.TypeOf((*easyjson.UnknownFieldHandler)(nil)).Elem()):
+		return "out.UnknownField(key, in)"
+	case hasUnknownsUnmarshaler(t):
+		return "out.UnmarshalUnknown(in, key)"
+	}
+	return ""
+}
+
+func hasAfterUnmarshaler(t reflect.Type) bool {
+	t = reflect.PtrTo(t)
+	return t.Implements(reflect.TypeOf((*easyjson.AfterUnmarshaler)(nil)).Elem())
+}
+
 func hasUnknownsMarshaler(t reflect.Type) bool {
 	t = reflect.PtrTo(t)
 	return t.Implements(reflect.TypeOf((*easyjson.UnknownsMarshaler)(nil)).Elem())
 }
 
+// appendsTo returns whether the decoders append to values of type t, leaving them
+// unchanged for null, as requested with AppendSlices.
+func (g *Generator) appendsTo(t reflect.Type) bool {
+	if !g.appendSlices || t.Kind() != reflect.Slice {
+		return false
+	}
+	return t.Elem().Kind() != reflect.Uint8 || t.Elem().Name() != "uint8"
+}
+
 // genTypeDecoderNoCheck generates decoding code for the type t.
 func (g *Generator) genTypeDecoderNoCheck(t reflect.Type, out string, tags fieldTags, indent int) error {
 	ws := strings.Repeat("  ", indent)
 	// Check whether type is primitive, needs to be done after interface check.
-	if dec := customDecoders[t.String()]; dec != "" {
-		fmt.Fprintln(g.out, ws+out+" = "+dec)
+	if t == jsonNumberType {
+		fmt.Fprintln(g.out, ws+out+" = in.JsonNumber()")
 		return nil
 	} else if dec := primitiveStringDecoders[t.Kind()]; dec != "" && tags.asString {
 		if tags.intern && t.Kind() == reflect.String {
@@ -122,6 +236,9 @@ func (g *Generator) genTypeDecoderNoCheck(t reflect.Type, out string, tags field
 		if tags.intern && t.Kind() == reflect.String {
 			dec = "in.StringIntern()"
 		}
+		if g.boolDecodeMap != nil && t.Kind() == reflect.Bool {
+			dec = "in.BoolMapped(" + g.boolDecodeMapVar() + ")"
+		}
 		if tags.noCopy && t.Kind() == reflect.String {
 			dec = "in.UnsafeString()"
 		}
@@ -129,6 +246,23 @@ func (g *Generator) genTypeDecoderNoCheck(t reflect.Type, out string, tags field
 		return nil
 	}
 
+	leave, ok := g.enterInline(t)
+	if ok && g.reusesFunc(t, tags) {
+		leave()
+		ok = false
+	}
+	if !ok {
+		dec := g.getDecoderName(t)
+		g.addDecoderType(t)
+		if len(out) > 0 && out[0] == '*' {
+			fmt.Fprintln(g.out, ws+dec+"(in, "+out[1:]+")")
+		} else {
+			fmt.Fprintln(g.out, ws+dec+"(in, &"+out+")")
+		}
+		return nil
+	}
+	defer leave()
+
 	switch t.Kind() {
 	case reflect.Slice:
 		tmpVar := g.uniqueVarName()
@@ -156,7 +290,9 @@ func (g *Generator) genTypeDecoderNoCheck(t reflect.Type, out string, tags field
 
 			fmt.Fprintln(g.out, ws+"if in.IsNull() {")
 			fmt.Fprintln(g.out, ws+"  in.Skip()")
-			fmt.Fprintln(g.out, ws+"  "+out+" = nil")
+			if !g.appendsTo(t) {
+				fmt.Fprintln(g.out, ws+"  "+out+" = nil")
+			}
 			fmt.Fprintln(g.out, ws+"} else {")
 			fmt.Fprintln(g.out, ws+"  in.Delim('[')")
 			fmt.Fprintln(g.out, ws+"  if "+out+" == nil {")
@@ -165,17 +301,27 @@ func (g *Generator) genTypeDecoderNoCheck(t reflect.Type, out string, tags field
 			fmt.Fprintln(g.out, ws+"    } else {")
 			fmt.Fprintln(g.out, ws+"      "+out+" = "+g.getType(t)+"{}")
 			fmt.Fprintln(g.out, ws+"    }")
-			fmt.Fprintln(g.out, ws+"  } else { ")
-			fmt.Fprintln(g.out, ws+"    "+out+" = ("+out+")[:0]")
+			if !g.appendsTo(t) {
+				fmt.Fprintln(g.out, ws+"  } else { ")
+				fmt.Fprintln(g.out, ws+"    "+out+" = ("+out+")[:0]")
+			}
 			fmt.Fprintln(g.out, ws+"  }")
+			index := "len(" + out + ")"
+			if g.appendsTo(t) && g.errorPaths {
+				// Paths index the elements of the array, not of the slice.
+				fmt.Fprintln(g.out, ws+"  "+tmpVar+"Start := len("+out+")")
+				index += " - " + tmpVar + "Start"
+			}
 			fmt.Fprintln(g.out, ws+"  for !in.IsDelim(']') {")
 			fmt.Fprintln(g.out, ws+"    var "+tmpVar+" "+g.getType(elem))
+			g.genEnterElement(ws+"    ", "EnterIndex("+index+")")
 
-			if err := g.genTypeDecoder(elem, tmpVar, tags, indent+2); err != nil {
+			if err := g.genElemDecoder(elem, tmpVar, tags, indent+2); err != nil {
 				return err
 			}
 
 			fmt.Fprintln(g.out, ws+"    "+out+" = append("+out+", "+tmpVar+")")
+			g.genLeaveElement(ws + "    ")
 			fmt.Fprintln(g.out, ws+"    in.WantComma()")
 			fmt.Fprintln(g.out, ws+"  }")
 			fmt.Fprintln(g.out, ws+"  in.Delim(']')")
@@ -186,42 +332,62 @@ func (g *Generator) genTypeDecoderNoCheck(t reflect.Type, out string, tags field
 		iterVar := g.uniqueVarName()
 		elem := t.Elem()
 
+		length := t.Len()
+
 		if elem.Kind() == reflect.Uint8 && elem.Name() == "uint8" {
 			fmt.Fprintln(g.out, ws+"if in.IsNull() {")
 			fmt.Fprintln(g.out, ws+"  in.Skip()")
 			fmt.Fprintln(g.out, ws+"} else {")
-			fmt.Fprintln(g.out, ws+"  copy("+out+"[:], in.Bytes())")
+			if g.strictArrays {
+				g.useImport("fmt", "fmt")
+				fmt.Fprintln(g.out, ws+"  if data := in.Bytes(); in.Ok() && len(data) != "+fmt.Sprint(length)+" {")
+				fmt.Fprintf(g.out, ws+"    in.AddError(fmt.Errorf(\"array of length %d expected, got %%d bytes\", len(data)))\n", length)
+				fmt.Fprintln(g.out, ws+"  } else {")
+				fmt.Fprintln(g.out, ws+"    copy("+out+"[:], data)")
+				fmt.Fprintln(g.out, ws+"  }")
+			} else {
+				fmt.Fprintln(g.out, ws+"  copy("+out+"[:], in.Bytes())")
+			}
 			fmt.Fprintln(g.out, ws+"}")
 
 		} else {
 
-			length := t.Len()
-
 			fmt.Fprintln(g.out, ws+"if in.IsNull() {")
 			fmt.Fprintln(g.out, ws+"  in.Skip()")
 			fmt.Fprintln(g.out, ws+"} else {")
 			fmt.Fprintln(g.out, ws+"  in.Delim('[')")
 			fmt.Fprintln(g.out, ws+"  "+iterVar+" := 0")
 			fmt.Fprintln(g.out, ws+"  for !in.IsDelim(']') {")
+			g.genEnterElement(ws+"    ", "EnterIndex("+iterVar+")")
 			fmt.Fprintln(g.out, ws+"    if "+iterVar+" < "+fmt.Sprint(length)+" {")
 
-			if err := g.genTypeDecoder(elem, "("+out+")["+iterVar+"]", tags, indent+3); err != nil {
+			if err := g.genElemDecoder(elem, "("+out+")["+iterVar+"]", tags, indent+3); err != nil {
 				return err
 			}
 
 			fmt.Fprintln(g.out, ws+"      "+iterVar+"++")
 			fmt.Fprintln(g.out, ws+"    } else {")
 			fmt.Fprintln(g.out, ws+"      in.SkipRecursive()")
+			if g.strictArrays {
+				fmt.Fprintln(g.out, ws+"      "+iterVar+"++")
+			}
 			fmt.Fprintln(g.out, ws+"    }")
+			g.genLeaveElement(ws + "    ")
 			fmt.Fprintln(g.out, ws+"    in.WantComma()")
 			fmt.Fprintln(g.out, ws+"  }")
 			fmt.Fprintln(g.out, ws+"  in.Delim(']')")
+			if g.strictArrays {
+				g.useImport("fmt", "fmt")
+				fmt.Fprintln(g.out, ws+"  if "+iterVar+" != "+fmt.Sprint(length)+" {")
+				fmt.Fprintf(g.out, ws+"    in.AddError(fmt.Errorf(\"array of length %d expected, got %%d elements\", %s))\n", length, iterVar)
+				fmt.Fprintln(g.out, ws+"  }")
+			}
 			fmt.Fprintln(g.out, ws+"}")
 		}
 
 	case reflect.Struct:
 		dec := g.getDecoderName(t)
-		g.addType(t)
+		g.addDecoderType(t)
 
 		if len(out) > 0 && out[0] == '*' {
 			// NOTE: In order to remove an extra reference to a pointer
@@ -246,10 +412,16 @@ func (g *Generator) genTypeDecoderNoCheck(t reflect.Type, out string, tags field
 		fmt.Fprintln(g.out, ws+"}")
 
 	case reflect.Map:
+		if tags.set && isSetType(t) {
+			return g.genSetDecoder(t, out, tags, indent)
+		}
 		key := t.Key()
 		keyDec, ok := primitiveStringDecoders[key.Kind()]
+		if key.Kind() == reflect.Bool {
+			ok = false
+		}
 		if !ok && !hasCustomUnmarshaler(key) {
-			return fmt.Errorf("map type %v not supported: only string and integer keys and types implementing json.Unmarshaler are allowed", key)
+			return fmt.Errorf("map type %v not supported: only string and integer keys and types implementing json.Unmarshaler or encoding.TextUnmarshaler are allowed", key)
 		} // else assume the caller knows what they are doing and that the custom unmarshaler performs the translation from string or integer keys to the key type
 		elem := t.Elem()
 		tmpVar := g.uniqueVarName()
@@ -257,61 +429,76 @@ func (g *Generator) genTypeDecoderNoCheck(t reflect.Type, out string, tags field
 
 		fmt.Fprintln(g.out, ws+"if in.IsNull() {")
 		fmt.Fprintln(g.out, ws+"  in.Skip()")
+		fmt.Fprintln(g.out, ws+"  "+out+" = nil")
 		fmt.Fprintln(g.out, ws+"} else {")
 		fmt.Fprintln(g.out, ws+"  in.Delim('{')")
-		if !keepEmpty {
-			fmt.Fprintln(g.out, ws+"  if !in.IsDelim('}') {")
-		}
-		fmt.Fprintln(g.out, ws+"  "+out+" = make("+g.getType(t)+")")
-		if !keepEmpty {
-			fmt.Fprintln(g.out, ws+"  } else {")
-			fmt.Fprintln(g.out, ws+"  "+out+" = nil")
-			fmt.Fprintln(g.out, ws+"  }")
+		// The entries are merged into an existing map like encoding/json does.
+		if keepEmpty {
+			fmt.Fprintln(g.out, ws+"  if "+out+" == nil {")
+		} else {
+			fmt.Fprintln(g.out, ws+"  if "+out+" == nil && !in.IsDelim('}') {")
 		}
+		fmt.Fprintln(g.out, ws+"    "+out+" = make("+g.getType(t)+")")
+		fmt.Fprintln(g.out, ws+"  }")
 
 		fmt.Fprintln(g.out, ws+"  for !in.IsDelim('}') {")
 		// NOTE: extra check for TextUnmarshaler. It overrides default methods.
 		if reflect.PtrTo(key).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()) {
 			fmt.Fprintln(g.out, ws+"    var key "+g.getType(key))
 			fmt.Fprintln(g.out, ws+"if data := in.UnsafeBytes(); in.Ok() {")
+			g.genEnterElement(ws+"  ", "EnterKey(string(data))")
 			fmt.Fprintln(g.out, ws+"  in.AddError(key.UnmarshalText(data) )")
 			fmt.Fprintln(g.out, ws+"}")
 		} else if keyDec != "" {
+			if g.internMapKeys && key.Kind() == reflect.String {
+				keyDec = "in.StringIntern()"
+			}
 			fmt.Fprintln(g.out, ws+"    key := "+g.getType(key)+"("+keyDec+")")
+			g.genEnterElement(ws+"    ", "EnterKey("+g.mapKeyString(key, "key")+")")
 		} else {
 			fmt.Fprintln(g.out, ws+"    var key "+g.getType(key))
 			if err := g.genTypeDecoder(key, "key", tags, indent+2); err != nil {
 				return err
 			}
+			g.genEnterElement(ws+"    ", "EnterKey("+g.mapKeyString(key, "key")+")")
 		}
 
 		fmt.Fprintln(g.out, ws+"    in.WantColon()")
 		fmt.Fprintln(g.out, ws+"    var "+tmpVar+" "+g.getType(elem))
 
-		if err := g.genTypeDecoder(elem, tmpVar, tags, indent+2); err != nil {
+		if err := g.genElemDecoder(elem, tmpVar, tags, indent+2); err != nil {
 			return err
 		}
 
 		fmt.Fprintln(g.out, ws+"    ("+out+")[key] = "+tmpVar)
+		g.genLeaveElement(ws + "    ")
 		fmt.Fprintln(g.out, ws+"    in.WantComma()")
 		fmt.Fprintln(g.out, ws+"  }")
 		fmt.Fprintln(g.out, ws+"  in.Delim('}')")
 		fmt.Fprintln(g.out, ws+"}")
 
 	case reflect.Interface:
+		if impl, ok := g.interfaceImpls[t]; ok {
+			return g.genInterfaceImplDecoder(t, impl, out, tags, indent)
+		}
 		if t.NumMethod() != 0 {
 			if g.interfaceIsEasyjsonUnmarshaller(t) {
 				fmt.Fprintln(g.out, ws+out+".UnmarshalEasyJSON(in)")
 			} else if g.interfaceIsJsonUnmarshaller(t) {
-				fmt.Fprintln(g.out, ws+out+".UnmarshalJSON(in.Raw())")
+				fmt.Fprintln(g.out, ws+"if data := in.Raw(); in.Ok() {")
+				fmt.Fprintln(g.out, ws+"  in.AddError( "+out+".UnmarshalJSON(data) )")
+				fmt.Fprintln(g.out, ws+"}")
 			} else {
 				return fmt.Errorf("interface type %v not supported: only interface{} and easyjson/json Unmarshaler are allowed", t)
 			}
 		} else {
+			g.useImport(pkgJSON, "json")
 			fmt.Fprintln(g.out, ws+"if m, ok := "+out+".(easyjson.Unmarshaler); ok {")
 			fmt.Fprintln(g.out, ws+"m.UnmarshalEasyJSON(in)")
 			fmt.Fprintln(g.out, ws+"} else if m, ok := "+out+".(json.Unmarshaler); ok {")
-			fmt.Fprintln(g.out, ws+"_ = m.UnmarshalJSON(in.Raw())")
+			fmt.Fprintln(g.out, ws+"  if data := in.Raw(); in.Ok() {")
+			fmt.Fprintln(g.out, ws+"    in.AddError( m.UnmarshalJSON(data) )")
+			fmt.Fprintln(g.out, ws+"  }")
 			fmt.Fprintln(g.out, ws+"} else {")
 			fmt.Fprintln(g.out, ws+"  "+out+" = in.Interface()")
 			fmt.Fprintln(g.out, ws+"}")
@@ -323,6 +510,78 @@ func (g *Generator) genTypeDecoderNoCheck(t reflect.Type, out string, tags field
 
 }
 
+// genElemDecoder generates decoding code for an element of a slice, array or
+// map. Unlike struct fields, whose nulls are skipped by genNullFieldSwitch, the
+// elements are decoded as they come, so a null is skipped here for primitive
+// types, leaving the element as is like encoding/json does.
+func (g *Generator) genElemDecoder(t reflect.Type, out string, tags fieldTags, indent int) error {
+	if !isPrimitive(t) || hasCustomUnmarshaler(t) || g.customCodecs[t].decode != "" {
+		return g.genTypeDecoder(t, out, tags, indent)
+	}
+	ws := strings.Repeat("  ", indent)
+
+	fmt.Fprintln(g.out, ws+"if in.IsNull() {")
+	fmt.Fprintln(g.out, ws+"  in.Skip()")
+	fmt.Fprintln(g.out, ws+"} else {")
+	if err := g.genTypeDecoder(t, out, tags, indent+1); err != nil {
+		return err
+	}
+	fmt.Fprintln(g.out, ws+"}")
+	return nil
+}
+
+// genInterfaceImplDecoder generates decoding code for an interface type with
+// registered concrete types: the raw object is scanned for the tag member first,
+// then decoded again into the concrete type registered for the tag value.
+func (g *Generator) genInterfaceImplDecoder(t reflect.Type, impl interfaceImpl, out string, tags fieldTags, indent int) error {
+	ws := strings.Repeat("  ", indent)
+	parentVar := g.uniqueVarName()
+	tagVar := g.uniqueVarName()
+
+	fmt.Fprintln(g.out, ws+"if in.IsNull() {")
+	fmt.Fprintln(g.out, ws+"  in.Skip()")
+	fmt.Fprintln(g.out, ws+"  "+out+" = nil")
+	fmt.Fprintln(g.out, ws+"} else if data := in.Raw(); in.Ok() {")
+	fmt.Fprintln(g.out, ws+"  "+parentVar+" := in")
+	fmt.Fprintln(g.out, ws+"  in := "+parentVar+".RawLexer(data)")
+	fmt.Fprintln(g.out, ws+"  var "+tagVar+" string")
+	fmt.Fprintln(g.out, ws+"  in.Delim('{')")
+	fmt.Fprintln(g.out, ws+"  for !in.IsDelim('}') {")
+	fmt.Fprintf(g.out, ws+"    if in.UnsafeFieldName(false) == %q {\n", impl.tagField)
+	fmt.Fprintln(g.out, ws+"      in.WantColon()")
+	fmt.Fprintln(g.out, ws+"      "+tagVar+" = in.String()")
+	fmt.Fprintln(g.out, ws+"    } else {")
+	fmt.Fprintln(g.out, ws+"      in.WantColon()")
+	fmt.Fprintln(g.out, ws+"      in.SkipRecursive()")
+	fmt.Fprintln(g.out, ws+"    }")
+	fmt.Fprintln(g.out, ws+"    in.WantComma()")
+	fmt.Fprintln(g.out, ws+"  }")
+	fmt.Fprintln(g.out, ws+"  in.Delim('}')")
+	fmt.Fprintln(g.out, ws+"  if in.Ok() {")
+	fmt.Fprintln(g.out, ws+"    in = "+parentVar+".RawLexer(data)")
+	fmt.Fprintln(g.out, ws+"    switch "+tagVar+" {")
+	for _, name := range impl.tags {
+		implType := impl.impls[name]
+		if !implType.Implements(t) {
+			return fmt.Errorf("type %v registered for %v tag %q does not implement it", implType, t, name)
+		}
+		v := g.uniqueVarName()
+		fmt.Fprintf(g.out, ws+"    case %q:\n", name)
+		fmt.Fprintln(g.out, ws+"      var "+v+" "+g.getType(implType))
+		if err := g.genTypeDecoder(implType, v, tags, indent+3); err != nil {
+			return err
+		}
+		fmt.Fprintln(g.out, ws+"      "+out+" = "+v)
+	}
+	fmt.Fprintln(g.out, ws+"    default:")
+	fmt.Fprintf(g.out, ws+"      in.AddError(&jlexer.LexerError{Reason: %q, Offset: in.GetPos(), Data: %s})\n", "unknown "+impl.tagField+" of "+t.String(), tagVar)
+	fmt.Fprintln(g.out, ws+"    }")
+	fmt.Fprintln(g.out, ws+"  }")
+	fmt.Fprintln(g.out, ws+"  "+parentVar+".AddError(in.Error())")
+	fmt.Fprintln(g.out, ws+"}")
+	return nil
+}
+
 func (g *Generator) interfaceIsEasyjsonUnmarshaller(t reflect.Type) bool {
 	return t.Implements(reflect.TypeOf((*easyjson.Unmarshaler)(nil)).Elem())
 }
@@ -332,8 +591,8 @@ func (g *Generator) interfaceIsJsonUnmarshaller(t reflect.Type) bool {
 }
 
 func (g *Generator) genStructFieldDecoder(t reflect.Type, f reflect.StructField) error {
-	jsonName := g.fieldNamer.GetJSONFieldName(t, f)
-	tags := parseFieldTags(f)
+	jsonName := g.jsonFieldName(t, f)
+	tags := parseFieldTags(f, g.tagKey)
 
 	if tags.omit {
 		return nil
@@ -341,70 +600,135 @@ func (g *Generator) genStructFieldDecoder(t reflect.Type, f reflect.StructField)
 	if tags.intern && tags.noCopy {
 		return errors.New("Mutually exclusive tags are specified: 'intern' and 'nocopy'")
 	}
+	if tags.readOnly && tags.computed {
+		return errors.New("Mutually exclusive tags are specified: 'readonly' and 'computed'")
+	}
+	if tags.computed {
+		if tags.required {
+			return errors.New("Mutually exclusive tags are specified: 'computed' and 'required'")
+		}
+		// The key is known, but its value is dropped.
+		fmt.Fprintf(g.out, "    case %q:\n", jsonName)
+		fmt.Fprintln(g.out, "      in.SkipRecursive()")
+		return nil
+	}
+	if err := checkStringTag(t, f, tags); err != nil {
+		return err
+	}
+	if err := checkSetTag(t, f, tags); err != nil {
+		return err
+	}
 
 	fmt.Fprintf(g.out, "    case %q:\n", jsonName)
-	if err := g.genTypeDecoder(f.Type, "out."+f.Name, tags, 3); err != nil {
+	// Embedded pointers are allocated once one of their fields is present.
+	for _, p := range embeddedPointers(t, f) {
+		fmt.Fprintln(g.out, "      if out."+p.path+" == nil {")
+		fmt.Fprintln(g.out, "        out."+p.path+" = new("+g.getType(p.typ.Elem())+")")
+		fmt.Fprintln(g.out, "      }")
+	}
+	if a, ok := g.fieldAccessor(t, f); ok {
+		if a.setter == "" {
+			fmt.Fprintln(g.out, "      in.SkipRecursive()")
+			return nil
+		}
+		// The value is decoded into a copy, merged like the field would be.
+		tmpVar := g.uniqueVarName()
+		if a.getter != "" {
+			fmt.Fprintln(g.out, "      "+tmpVar+" := out."+a.getter+"()")
+		} else {
+			fmt.Fprintln(g.out, "      var "+tmpVar+" "+g.getType(f.Type))
+		}
+		if err := g.genTypeDecoder(f.Type, tmpVar, tags, 3); err != nil {
+			return err
+		}
+		fmt.Fprintln(g.out, "      "+g.fieldAssign(t, f, tmpVar))
+	} else if err := g.genTypeDecoder(f.Type, "out."+fieldSelector(t, f), tags, 3); err != nil {
 		return err
 	}
 
 	if tags.required {
-		fmt.Fprintf(g.out, "%sSet = true\n", f.Name)
+		fmt.Fprintf(g.out, "%s = true\n", requiredVarName(f))
 	}
 
 	return nil
 }
 
 func (g *Generator) genRequiredFieldSet(t reflect.Type, f reflect.StructField) {
-	tags := parseFieldTags(f)
+	tags := parseFieldTags(f, g.tagKey)
 
 	if !tags.required {
 		return
 	}
 
-	fmt.Fprintf(g.out, "var %sSet bool\n", f.Name)
+	fmt.Fprintf(g.out, "var %s bool\n", requiredVarName(f))
 }
 
-func (g *Generator) genRequiredFieldCheck(t reflect.Type, f reflect.StructField) {
-	jsonName := g.fieldNamer.GetJSONFieldName(t, f)
-	tags := parseFieldTags(f)
-
-	if !tags.required {
-		return
+// requiredVarName returns the name of the variable recording whether the
+// required field f was set. Promoted fields have their index path in the name,
+// as several of them can have the same Go name.
+func requiredVarName(f reflect.StructField) string {
+	name := f.Name
+	if len(f.Index) > 1 {
+		for _, i := range f.Index {
+			name += "_" + strconv.Itoa(i)
+		}
 	}
-
-	g.imports["fmt"] = "fmt"
-
-	fmt.Fprintf(g.out, "if !%sSet {\n", f.Name)
-	fmt.Fprintf(g.out, "    in.AddError(fmt.Errorf(\"key '%s' is required\"))\n", jsonName)
-	fmt.Fprintf(g.out, "}\n")
+	return name + "Set"
 }
 
-func mergeStructFields(fields1, fields2 []reflect.StructField) (fields []reflect.StructField) {
-	used := map[string]bool{}
-	for _, f := range fields2 {
-		used[f.Name] = true
-		fields = append(fields, f)
+// genRequiredFieldsCheck generates code that reports all required fields that
+// were absent from the input in a single error.
+func (g *Generator) genRequiredFieldsCheck(t reflect.Type, fs []reflect.StructField) {
+	var required []reflect.StructField
+	for _, f := range fs {
+		if tags := parseFieldTags(f, g.tagKey); tags.required && !tags.omit {
+			required = append(required, f)
+		}
+	}
+	if len(required) == 0 {
+		return
 	}
 
-	for _, f := range fields1 {
-		if !used[f.Name] {
-			fields = append(fields, f)
-		}
+	g.useImport("fmt", "fmt")
+	g.useImport("strings", "strings")
+
+	fmt.Fprintln(g.out, "  var missingKeys []string")
+	for _, f := range required {
+		fmt.Fprintf(g.out, "  if !%s {\n", requiredVarName(f))
+		fmt.Fprintf(g.out, "    missingKeys = append(missingKeys, %q)\n", g.jsonFieldName(t, f))
+		fmt.Fprintln(g.out, "  }")
 	}
-	return
+	fmt.Fprintln(g.out, "  if len(missingKeys) == 1 {")
+	fmt.Fprintf(g.out, "    in.AddError(fmt.Errorf(\"key '%%s' is required\", missingKeys[0]))\n")
+	fmt.Fprintln(g.out, "  } else if len(missingKeys) > 1 {")
+	fmt.Fprintf(g.out, "    in.AddError(fmt.Errorf(\"keys '%%s' are required\", strings.Join(missingKeys, \"', '\")))\n")
+	fmt.Fprintln(g.out, "  }")
 }
 
-func getStructFields(t reflect.Type) ([]reflect.StructField, error) {
+// getStructFields returns the fields of the struct t along with all the fields
+// promoted from its embedded structs. Fields are only resolved by their JSON
+// names, see structFields, so promoted fields of the same Go name are all kept.
+func getStructFields(t reflect.Type, tagKey string) ([]reflect.StructField, error) {
 	if t.Kind() != reflect.Struct {
 		return nil, fmt.Errorf("got %v; expected a struct", t)
 	}
+	return collectStructFields(t, tagKey, map[reflect.Type]bool{}), nil
+}
+
+// collectStructFields returns the fields of the struct t followed by all the
+// fields promoted from its embedded structs, with full index paths so that the
+// depth and the embedded pointers on the way can be found. Structs which are
+// already being visited higher up the path are not entered again.
+func collectStructFields(t reflect.Type, tagKey string, visiting map[reflect.Type]bool) []reflect.StructField {
+	visiting[t] = true
+	defer delete(visiting, t)
 
 	var efields []reflect.StructField
 	var fields []reflect.StructField
 	for i := 0; i < t.NumField(); i++ {
 		f := t.Field(i)
-		tags := parseFieldTags(f)
-		if !f.Anonymous || tags.name != "" {
+		tags := parseFieldTags(f, tagKey)
+		if tags.omit || !isFlattened(f, tags) {
 			continue
 		}
 
@@ -414,11 +738,14 @@ func getStructFields(t reflect.Type) ([]reflect.StructField, error) {
 		}
 
 		if t1.Kind() == reflect.Struct {
-			fs, err := getStructFields(t1)
-			if err != nil {
-				return nil, fmt.Errorf("error processing embedded field: %v", err)
+			if visiting[t1] {
+				continue
+			}
+			fs := collectStructFields(t1, tagKey, visiting)
+			for j := range fs {
+				fs[j].Index = append([]int{i}, fs[j].Index...)
 			}
-			efields = mergeStructFields(efields, fs)
+			efields = append(fs, efields...)
 		} else if (t1.Kind() >= reflect.Bool && t1.Kind() < reflect.Complex128) || t1.Kind() == reflect.String {
 			if strings.Contains(f.Name, ".") || unicode.IsUpper([]rune(f.Name)[0]) {
 				fields = append(fields, f)
@@ -428,8 +755,8 @@ func getStructFields(t reflect.Type) ([]reflect.StructField, error) {
 
 	for i := 0; i < t.NumField(); i++ {
 		f := t.Field(i)
-		tags := parseFieldTags(f)
-		if f.Anonymous && tags.name == "" {
+		tags := parseFieldTags(f, tagKey)
+		if f.Anonymous && tags.name == "" || isFlattened(f, tags) {
 			continue
 		}
 
@@ -438,12 +765,292 @@ func getStructFields(t reflect.Type) ([]reflect.StructField, error) {
 			fields = append(fields, f)
 		}
 	}
-	return mergeStructFields(efields, fields), nil
+	return append(fields, efields...)
 }
 
-func (g *Generator) genDecoder(t reflect.Type) error {
+// isFlattened returns whether the fields of the struct field f are promoted into
+// the enclosing object: f is embedded and not named by its tag, or it is a struct
+// tagged with the inline option.
+func isFlattened(f reflect.StructField, tags fieldTags) bool {
+	if tags.inline {
+		t := f.Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		return t.Kind() == reflect.Struct
+	}
+	return f.Anonymous && tags.name == ""
+}
+
+// fieldSelector returns the selector of the field f returned by getStructFields
+// on a value of the struct t. The fields of inline structs are not promoted like
+// those of embedded ones, and promoted fields can be shadowed by or ambiguous
+// with other fields of the same Go name, so the full path is used to reach them.
+func fieldSelector(t reflect.Type, f reflect.StructField) string {
+	path := make([]string, 0, len(f.Index))
+	promoted := true
+	for i := 1; i <= len(f.Index); i++ {
+		ef := t.FieldByIndex(f.Index[:i])
+		path = append(path, ef.Name)
+		if i < len(f.Index) && !ef.Anonymous {
+			promoted = false
+		}
+	}
+	if promoted {
+		if sf, ok := t.FieldByName(f.Name); ok && reflect.DeepEqual(sf.Index, f.Index) {
+			return f.Name
+		}
+	}
+	return strings.Join(path, ".")
+}
+
+// inlineMapField returns the map field of the struct t tagged with the inline
+// or the extra option, whose entries are written into the object along with the
+// fields and which takes the keys matching no field when decoding, or nil if
+// there is none. Extra fields are inline maps of raw values.
+func (g *Generator) inlineMapField(t reflect.Type) (*reflect.StructField, error) {
+	fs, err := getStructFields(t, g.tagKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *reflect.StructField
+	for i, f := range fs {
+		tags := parseFieldTags(f, g.tagKey)
+		switch {
+		case tags.omit:
+			continue
+		case tags.extra:
+			if f.Type.Kind() != reflect.Map || f.Type.Key().Kind() != reflect.String || f.Type.Elem() != rawMessageType {
+				return nil, fmt.Errorf("field %v of type %v: 'extra' option is only supported for map[string]json.RawMessage", f.Name, f.Type)
+			}
+		case !tags.inline:
+			continue
+		case f.Type.Kind() != reflect.Map || f.Type.Key().Kind() != reflect.String:
+			return nil, fmt.Errorf("field %v of type %v: 'inline' option is only supported for structs and maps with string keys", f.Name, f.Type)
+		}
+		if ret != nil {
+			return nil, fmt.Errorf("fields %v and %v both take the keys matching no field", ret.Name, f.Name)
+		}
+		if len(embeddedPointers(t, f)) > 0 {
+			return nil, fmt.Errorf("inline map field %v is promoted through an embedded pointer", f.Name)
+		}
+		ret = &fs[i]
+	}
+	if ret != nil && (hasUnknownsMarshaler(t) || unknownFieldCall(t) != "") {
+		return nil, fmt.Errorf("inline map field %v cannot be used along with the unknown fields interfaces", ret.Name)
+	}
+	return ret, nil
+}
+
+// structFields returns the fields of the struct t like getStructFields, checking
+// that their types can be represented in JSON. Fields of unsupported types are
+// reported as an error, or left out if the generator skips unsupported fields.
+//
+// Fields with the same JSON name are resolved like encoding/json does: the one
+// at the smallest depth wins, a tagged one winning over untagged ones at the
+// same depth, and none is used if that leaves several. Fields of the struct
+// itself are not allowed to have the same JSON name.
+//
+// The order of the fields is kept, so the encoders write the keys in the
+// order of declaration.
+func (g *Generator) structFields(t reflect.Type) ([]reflect.StructField, error) {
+	fs, err := getStructFields(t, g.tagKey)
+	if err != nil {
+		return nil, err
+	}
+	if fs, err = g.addAccessorFields(t, fs); err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		field  reflect.StructField
+		tagged bool
+	}
+	var names []string
+	byName := make(map[string][]candidate, len(fs))
+	ret := fs[:0]
+	for _, f := range fs {
+		tags := parseFieldTags(f, g.tagKey)
+		if tags.omit {
+			ret = append(ret, f)
+			continue
+		}
+		if tags.inline || tags.extra {
+			// Inline maps are handled apart, see inlineMapField.
+			continue
+		}
+		if k := unsupportedKind(f.Type); k != reflect.Invalid {
+			if g.skipUnsupportedFields {
+				continue
+			}
+			return nil, fmt.Errorf("field %v of type %v has unsupported kind %v", f.Name, f.Type, k)
+		}
+
+		name := g.jsonFieldName(t, f)
+		if byName[name] == nil {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], candidate{field: f, tagged: tags.name != ""})
+		ret = append(ret, f)
+	}
+
+	// Fields are told apart by their index paths, as promoted ones can have the
+	// same Go name.
+	dominant := make(map[string]bool, len(names))
+	for _, name := range names {
+		var top []candidate
+		for _, c := range byName[name] {
+			switch {
+			case len(top) == 0 || len(c.field.Index) < len(top[0].field.Index):
+				top = []candidate{c}
+			case len(c.field.Index) == len(top[0].field.Index):
+				top = append(top, c)
+			}
+		}
+		if len(top) > 1 && len(top[0].field.Index) == 1 {
+			return nil, fmt.Errorf("fields %v and %v have the same JSON name %q", top[0].field.Name, top[1].field.Name, name)
+		}
+
+		var winner *candidate
+		for i := range top {
+			if top[i].tagged || len(top) == 1 {
+				if winner != nil {
+					winner = nil
+					break
+				}
+				winner = &top[i]
+			}
+		}
+		if winner != nil {
+			dominant[fmt.Sprint(winner.field.Index)] = true
+		}
+	}
+
+	fs = ret
+	ret = fs[:0]
+	for _, f := range fs {
+		if dominant[fmt.Sprint(f.Index)] || parseFieldTags(f, g.tagKey).omit {
+			ret = append(ret, f)
+		}
+	}
+	return ret, nil
+}
+
+// unsupportedKind returns the kind of t or its element types which cannot be
+// represented in JSON, such as chan or func, and reflect.Invalid if there is none.
+// Types with custom marshalers or unmarshalers are assumed to be supported.
+func unsupportedKind(t reflect.Type) reflect.Kind {
+	return unsupportedElemKind(t, make(map[reflect.Type]bool))
+}
+
+// unsupportedElemKind is unsupportedKind not looking into the types in seen
+// again, which named types like "type T []T" recur to.
+func unsupportedElemKind(t reflect.Type, seen map[reflect.Type]bool) reflect.Kind {
+	if seen[t] || hasCustomMarshaler(t) || hasCustomUnmarshaler(t) {
+		return reflect.Invalid
+	}
+	seen[t] = true
 	switch t.Kind() {
-	case reflect.Slice, reflect.Array, reflect.Map:
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return t.Kind()
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return unsupportedElemKind(t.Elem(), seen)
+	case reflect.Map:
+		if k := unsupportedElemKind(t.Key(), seen); k != reflect.Invalid {
+			return k
+		}
+		return unsupportedElemKind(t.Elem(), seen)
+	}
+	return reflect.Invalid
+}
+
+// defaultValueTagKey is the struct tag key holding the value a field is set to
+// when its key is absent from the input.
+const defaultValueTagKey = "default"
+
+// defaultValueLiteral parses the default value def of a field of type t and
+// returns it as a Go literal.
+func defaultValueLiteral(t reflect.Type, def string) (string, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return strconv.Quote(def), nil
+	case reflect.Bool:
+		v, err := strconv.ParseBool(def)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(v), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(def, 10, t.Bits())
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(v, 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v, err := strconv.ParseUint(def, 10, t.Bits())
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatUint(v, 10), nil
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(def, t.Bits())
+		if err != nil {
+			return "", err
+		}
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return "", fmt.Errorf("%q is not a finite number", def)
+		}
+		return strconv.FormatFloat(v, 'g', -1, t.Bits()), nil
+	}
+	return "", fmt.Errorf("default values are not supported for kind %v", t.Kind())
+}
+
+// genFieldDefaults generates code that sets the fields with default values to
+// them, before the keys present in the input are decoded.
+func (g *Generator) genFieldDefaults(t reflect.Type, fs []reflect.StructField) error {
+	for _, f := range fs {
+		def, ok := f.Tag.Lookup(defaultValueTagKey)
+		if !ok || parseFieldTags(f, g.tagKey).omit {
+			continue
+		}
+		if len(embeddedPointers(t, f)) > 0 {
+			return fmt.Errorf("field %v has a default value but is promoted through an embedded pointer", f.Name)
+		}
+		lit, err := defaultValueLiteral(f.Type, def)
+		if err != nil {
+			return fmt.Errorf("invalid default value of field %v of type %v: %v", f.Name, f.Type, err)
+		}
+		fmt.Fprintln(g.out, "  "+g.fieldAssign(t, f, lit))
+	}
+	return nil
+}
+
+// embeddedPointer is an embedded pointer field that has to be dereferenced to
+// access a promoted field.
+type embeddedPointer struct {
+	path string // selector of the field, e.g. "Base" or "Base.Inner"
+	typ  reflect.Type
+}
+
+// embeddedPointers returns the embedded pointer fields of t on the way to the
+// promoted field f returned by getStructFields.
+func embeddedPointers(t reflect.Type, f reflect.StructField) []embeddedPointer {
+	var ptrs []embeddedPointer
+	var path []string
+	for i := 1; i < len(f.Index); i++ {
+		ef := t.FieldByIndex(f.Index[:i])
+		path = append(path, ef.Name)
+		if ef.Type.Kind() == reflect.Ptr {
+			ptrs = append(ptrs, embeddedPointer{path: strings.Join(path, "."), typ: ef.Type})
+		}
+	}
+	return ptrs
+}
+
+func (g *Generator) genDecoder(t reflect.Type) error {
+	switch {
+	case t.Kind() == reflect.Slice, t.Kind() == reflect.Array, t.Kind() == reflect.Map, isPrimitive(t):
 		return g.genSliceArrayDecoder(t)
 	default:
 		return g.genStructDecoder(t)
@@ -454,7 +1061,9 @@ func (g *Generator) genSliceArrayDecoder(t reflect.Type) error {
 	switch t.Kind() {
 	case reflect.Slice, reflect.Array, reflect.Map:
 	default:
-		return fmt.Errorf("cannot generate encoder/decoder for %v, not a slice/array/map type", t)
+		if !isPrimitive(t) {
+			return fmt.Errorf("cannot generate encoder/decoder for %v, not a slice/array/map/primitive type", t)
+		}
 	}
 
 	fname := g.getDecoderName(t)
@@ -462,9 +1071,21 @@ func (g *Generator) genSliceArrayDecoder(t reflect.Type) error {
 
 	fmt.Fprintln(g.out, "func "+fname+"(in *jlexer.Lexer, out *"+typ+") {")
 	fmt.Fprintln(g.out, " isTopLevel := in.IsStart()")
-	err := g.genTypeDecoderNoCheck(t, "*out", fieldTags{}, 1)
-	if err != nil {
-		return err
+	g.funcType = t
+	if isPrimitive(t) {
+		// Primitives are left unchanged by null like encoding/json does.
+		fmt.Fprintln(g.out, "  if in.IsNull() {")
+		fmt.Fprintln(g.out, "    in.Skip()")
+		fmt.Fprintln(g.out, "  } else {")
+		if err := g.genTypeDecoderNoCheck(t, "*out", fieldTags{}, 2); err != nil {
+			return err
+		}
+		fmt.Fprintln(g.out, "  }")
+	} else {
+		g.genEnterNested()
+		if err := g.genTypeDecoderNoCheck(t, "*out", fieldTags{}, 1); err != nil {
+			return err
+		}
 	}
 	fmt.Fprintln(g.out, "  if isTopLevel {")
 	fmt.Fprintln(g.out, "    in.Consumed()")
@@ -474,6 +1095,18 @@ func (g *Generator) genSliceArrayDecoder(t reflect.Type) error {
 	return nil
 }
 
+// genEnterNested generates the check of the nesting depth limit set with
+// SetMaxDepth at the start of a decoder func.
+func (g *Generator) genEnterNested() {
+	if g.maxDepth <= 0 {
+		return
+	}
+	fmt.Fprintf(g.out, "  if !in.EnterNested(%d) {\n", g.maxDepth)
+	fmt.Fprintln(g.out, "    return")
+	fmt.Fprintln(g.out, "  }")
+	fmt.Fprintln(g.out, "  defer in.LeaveNested()")
+}
+
 func (g *Generator) genStructDecoder(t reflect.Type) error {
 	if t.Kind() != reflect.Struct {
 		return fmt.Errorf("cannot generate encoder/decoder for %v, not a struct type", t)
@@ -491,17 +1124,9 @@ func (g *Generator) genStructDecoder(t reflect.Type) error {
 	fmt.Fprintln(g.out, "    in.Skip()")
 	fmt.Fprintln(g.out, "    return")
 	fmt.Fprintln(g.out, "  }")
+	g.genEnterNested()
 
-	// Init embedded pointer fields.
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-		if !f.Anonymous || f.Type.Kind() != reflect.Ptr {
-			continue
-		}
-		fmt.Fprintln(g.out, "  out."+f.Name+" = new("+g.getType(f.Type.Elem())+")")
-	}
-
-	fs, err := getStructFields(t)
+	fs, err := g.structFields(t)
 	if err != nil {
 		return fmt.Errorf("cannot generate decoder for %v: %v", t, err)
 	}
@@ -509,13 +1134,38 @@ func (g *Generator) genStructDecoder(t reflect.Type) error {
 	for _, f := range fs {
 		g.genRequiredFieldSet(t, f)
 	}
+	if err := g.genFieldDefaults(t, fs); err != nil {
+		return fmt.Errorf("cannot generate decoder for %v: %v", t, err)
+	}
+
+	var knownKeys []string
+	if g.rejectDuplicateKeys {
+		knownKeys = g.knownKeys(t, fs)
+	}
+	if len(knownKeys) > 0 {
+		fmt.Fprintf(g.out, "  var seenKeys [%d]uint64\n", (len(knownKeys)+63)/64)
+	}
 
 	fmt.Fprintln(g.out, "  in.Delim('{')")
 	fmt.Fprintln(g.out, "  for !in.IsDelim('}') {")
 	fmt.Fprintf(g.out, "    key := in.UnsafeFieldName(%v)\n", g.skipMemberNameUnescaping)
 	fmt.Fprintln(g.out, "    in.WantColon()")
+	g.genEnterElement("    ", "EnterKey(key)")
+	if g.caseInsensitive {
+		g.genKeyFolding(t, fs)
+	}
+	if len(knownKeys) > 0 {
+		g.genDuplicateKeyCheck(knownKeys)
+	}
+	inlineMap, err := g.inlineMapField(t)
+	if err != nil {
+		return fmt.Errorf("cannot generate decoder for %v: %v", t, err)
+	}
+
 	fmt.Fprintln(g.out, "    if in.IsNull() {")
+	g.genNullFieldSwitch(t, fs, inlineMap)
 	fmt.Fprintln(g.out, "       in.Skip()")
+	g.genLeaveElement("       ")
 	fmt.Fprintln(g.out, "       in.WantComma()")
 	fmt.Fprintln(g.out, "       continue")
 	fmt.Fprintln(g.out, "    }")
@@ -528,18 +1178,19 @@ func (g *Generator) genStructDecoder(t reflect.Type) error {
 	}
 
 	fmt.Fprintln(g.out, "    default:")
-	if g.disallowUnknownFields {
-		fmt.Fprintln(g.out, `      in.AddError(&jlexer.LexerError{
-          Offset: in.GetPos(),
-          Reason: "unknown field",
-          Data: key,
-      })`)
-	} else if hasUnknownsUnmarshaler(t) {
-		fmt.Fprintln(g.out, "      out.UnmarshalUnknown(in, key)")
+	if inlineMap != nil {
+		if err := g.genInlineMapEntryDecoder(t, *inlineMap, false); err != nil {
+			return err
+		}
+	} else if g.disallowUnknownFields {
+		g.genUnknownFieldError()
+	} else if call := unknownFieldCall(t); call != "" {
+		fmt.Fprintln(g.out, "      "+call)
 	} else {
 		fmt.Fprintln(g.out, "      in.SkipRecursive()")
 	}
 	fmt.Fprintln(g.out, "    }")
+	g.genLeaveElement("    ")
 	fmt.Fprintln(g.out, "    in.WantComma()")
 	fmt.Fprintln(g.out, "  }")
 	fmt.Fprintln(g.out, "  in.Delim('}')")
@@ -547,8 +1198,14 @@ func (g *Generator) genStructDecoder(t reflect.Type) error {
 	fmt.Fprintln(g.out, "    in.Consumed()")
 	fmt.Fprintln(g.out, "  }")
 
-	for _, f := range fs {
-		g.genRequiredFieldCheck(t, f)
+	g.genRequiredFieldsCheck(t, fs)
+
+	if hasAfterUnmarshaler(t) {
+		fmt.Fprintln(g.out, "  if in.Ok() {")
+		fmt.Fprintln(g.out, "    if err := out.AfterUnmarshal(); err != nil {")
+		fmt.Fprintln(g.out, "      in.AddError(err)")
+		fmt.Fprintln(g.out, "    }")
+		fmt.Fprintln(g.out, "  }")
 	}
 
 	fmt.Fprintln(g.out, "}")
@@ -556,11 +1213,199 @@ func (g *Generator) genStructDecoder(t reflect.Type) error {
 	return nil
 }
 
+// genKeyFolding generates code that replaces a key matching none of the JSON
+// field names exactly with the first field name equal to it ignoring case.
+func (g *Generator) genKeyFolding(t reflect.Type, fs []reflect.StructField) {
+	var names []string
+	for _, f := range fs {
+		if !parseFieldTags(f, g.tagKey).omit {
+			names = append(names, fmt.Sprintf("%q", g.jsonFieldName(t, f)))
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	stringsPkg := g.pkgAlias("strings")
+	fmt.Fprintln(g.out, "    switch key {")
+	fmt.Fprintln(g.out, "    case "+strings.Join(names, ", ")+":")
+	fmt.Fprintln(g.out, "    default:")
+	fmt.Fprintln(g.out, "      switch {")
+	for _, name := range names {
+		fmt.Fprintln(g.out, "      case "+stringsPkg+".EqualFold(key, "+name+"):")
+		fmt.Fprintln(g.out, "        key = "+name)
+	}
+	fmt.Fprintln(g.out, "      }")
+	fmt.Fprintln(g.out, "    }")
+}
+
+// genNullFieldSwitch generates the key checks needed for null values, which are
+// skipped before the main switch: nullable fields are set to nil as in
+// encoding/json, required fields given as null are marked as present, and
+// unknown keys are reported if they are disallowed, or passed with the null to
+// the method of t handling them.
+func (g *Generator) genNullFieldSwitch(t reflect.Type, fs []reflect.StructField, inlineMap *reflect.StructField) {
+	var names []string
+	var cases bytes.Buffer
+	for _, f := range fs {
+		tags := parseFieldTags(f, g.tagKey)
+		if tags.omit {
+			continue
+		}
+		jsonName := fmt.Sprintf("%q", g.jsonFieldName(t, f))
+		nullable := isNullable(f.Type) && !tags.computed && g.customCodecs[f.Type].decode == "" && !g.appendsTo(f.Type)
+		if a, ok := g.fieldAccessor(t, f); ok && a.setter == "" {
+			nullable = false
+		}
+		if !nullable && !tags.required {
+			names = append(names, jsonName)
+			continue
+		}
+
+		fmt.Fprintln(&cases, "       case "+jsonName+":")
+		if nullable {
+			var checks []string
+			for _, p := range embeddedPointers(t, f) {
+				checks = append(checks, "out."+p.path+" != nil")
+			}
+			if len(checks) > 0 {
+				fmt.Fprintln(&cases, "         if "+strings.Join(checks, " && ")+" {")
+				fmt.Fprintln(&cases, "           "+g.fieldAssign(t, f, g.nullValue(f.Type)))
+				fmt.Fprintln(&cases, "         }")
+			} else {
+				fmt.Fprintln(&cases, "         "+g.fieldAssign(t, f, g.nullValue(f.Type)))
+			}
+		}
+		if tags.required {
+			fmt.Fprintln(&cases, "         "+requiredVarName(f)+" = true")
+		}
+	}
+	unknownCall := unknownFieldCall(t)
+	if cases.Len() == 0 && !g.disallowUnknownFields && inlineMap == nil && unknownCall == "" {
+		return
+	}
+
+	fmt.Fprintln(g.out, "       switch key {")
+	g.out.Write(cases.Bytes())
+	if g.disallowUnknownFields || inlineMap != nil || unknownCall != "" {
+		if len(names) > 0 {
+			fmt.Fprintln(g.out, "       case "+strings.Join(names, ", ")+":")
+		}
+		fmt.Fprintln(g.out, "       default:")
+		if inlineMap != nil {
+			// Nothing is decoded for a null, so there is no error to check.
+			g.genInlineMapEntryDecoder(t, *inlineMap, true)
+		} else if g.disallowUnknownFields {
+			g.genUnknownFieldError()
+		} else {
+			// The method consumes the null itself rather than it being skipped.
+			fmt.Fprintln(g.out, "         "+unknownCall)
+			g.genLeaveElement("         ")
+			fmt.Fprintln(g.out, "         in.WantComma()")
+			fmt.Fprintln(g.out, "         continue")
+		}
+	}
+	fmt.Fprintln(g.out, "       }")
+}
+
+// genInlineMapEntryDecoder generates code that decodes the value of the current
+// key into an entry of the inline map field f, or sets the entry to the zero
+// value if the value is a null that has already been skipped.
+func (g *Generator) genInlineMapEntryDecoder(t reflect.Type, f reflect.StructField, null bool) error {
+	m := "out." + fieldSelector(t, f)
+	tmpVar := g.uniqueVarName()
+
+	fmt.Fprintln(g.out, "      if "+m+" == nil {")
+	fmt.Fprintln(g.out, "        "+m+" = make("+g.getType(f.Type)+")")
+	fmt.Fprintln(g.out, "      }")
+	fmt.Fprintln(g.out, "      var "+tmpVar+" "+g.getType(f.Type.Elem()))
+	if !null {
+		if err := g.genTypeDecoder(f.Type.Elem(), tmpVar, parseFieldTags(f, g.tagKey), 3); err != nil {
+			return err
+		}
+	}
+	// The key refers to the input, so it is copied.
+	fmt.Fprintln(g.out, "      "+m+"["+g.getType(f.Type.Key())+"([]byte(key))] = "+tmpVar)
+	return nil
+}
+
+// isNullable returns whether a JSON null is decoded into type t by resetting it,
+// as encoding/json does: pointers, slices, maps and interfaces are set to nil and
+// database/sql null types to invalid. A null leaves values of the other kinds,
+// like structs, arrays, strings, numbers and bools, as they are. Types with custom
+// unmarshalers other than pointers are left as is.
+func isNullable(t reflect.Type) bool {
+	if _, ok := sqlNullValue(t); ok {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return true
+	case reflect.Map, reflect.Slice, reflect.Interface:
+		return !hasCustomUnmarshaler(t)
+	}
+	return false
+}
+
+// nullValue returns the expression of the value a null decodes to for the
+// nullable type t.
+func (g *Generator) nullValue(t reflect.Type) string {
+	if _, ok := sqlNullValue(t); ok {
+		return g.getType(t) + "{}"
+	}
+	return "nil"
+}
+
+// knownKeys returns the JSON names of the fields fs of the struct type t.
+func (g *Generator) knownKeys(t reflect.Type, fs []reflect.StructField) []string {
+	var keys []string
+	for _, f := range fs {
+		if !parseFieldTags(f, g.tagKey).omit {
+			keys = append(keys, g.jsonFieldName(t, f))
+		}
+	}
+	return keys
+}
+
+// genDuplicateKeyCheck generates code that fails on a key which was already seen
+// in the object, marking the keys seen in the seenKeys bitset.
+func (g *Generator) genDuplicateKeyCheck(keys []string) {
+	fmt.Fprintln(g.out, "    switch key {")
+	for i, key := range keys {
+		word, bit := i/64, uint64(1)<<uint(i%64)
+		fmt.Fprintf(g.out, "    case %q:\n", key)
+		fmt.Fprintf(g.out, "      if seenKeys[%d]&%#x != 0 {\n", word, bit)
+		fmt.Fprintln(g.out, `        in.AddError(&jlexer.LexerError{
+              Offset: in.GetPos(),
+              Reason: "duplicate field",
+              Data: key,
+          })`)
+		fmt.Fprintln(g.out, "      }")
+		fmt.Fprintf(g.out, "      seenKeys[%d] |= %#x\n", word, bit)
+	}
+	fmt.Fprintln(g.out, "    }")
+}
+
+// genUnknownFieldError generates code that reports the current key as an unknown field.
+func (g *Generator) genUnknownFieldError() {
+	fmt.Fprintln(g.out, `      in.AddError(&jlexer.LexerError{
+          Offset: in.GetPos(),
+          Reason: "unknown field",
+          Data: key,
+      })`)
+}
+
 func (g *Generator) genStructUnmarshaler(t reflect.Type) error {
 	switch t.Kind() {
 	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
 	default:
-		return fmt.Errorf("cannot generate encoder/decoder for %v, not a struct/slice/array/map type", t)
+		if !isPrimitive(t) {
+			return fmt.Errorf("cannot generate encoder/decoder for %v, not a struct/slice/array/map/primitive type", t)
+		}
+	}
+	if !canDeclareMethods(t) {
+		// Only the funcs are generated for such types.
+		return nil
 	}
 
 	fname := g.getDecoderName(t)
@@ -569,7 +1414,23 @@ func (g *Generator) genStructUnmarshaler(t reflect.Type) error {
 	if !g.noStdMarshalers {
 		fmt.Fprintln(g.out, "// UnmarshalJSON supports json.Unmarshaler interface")
 		fmt.Fprintln(g.out, "func (v *"+typ+") UnmarshalJSON(data []byte) error {")
-		fmt.Fprintln(g.out, "  r := jlexer.Lexer{Data: data}")
+		opts := "Data: data"
+		if g.interfaceNumberMode == NumberJSONNumber {
+			opts += ", UseNumber: true"
+		}
+		if g.lenientTypes {
+			opts += ", LenientTypes: true"
+		}
+		if g.acceptQuotedNumbers {
+			opts += ", AcceptQuotedNumbers: true"
+		}
+		if g.json5 {
+			opts += ", JSON5: true"
+		}
+		if g.maxInputBytes > 0 {
+			opts += fmt.Sprintf(", MaxInputBytes: %d", g.maxInputBytes)
+		}
+		fmt.Fprintln(g.out, "  r := jlexer.Lexer{"+opts+"}")
 		fmt.Fprintln(g.out, "  "+fname+"(&r, v)")
 		fmt.Fprintln(g.out, "  return r.Error()")
 		fmt.Fprintln(g.out, "}")
@@ -580,5 +1441,58 @@ func (g *Generator) genStructUnmarshaler(t reflect.Type) error {
 	fmt.Fprintln(g.out, "  "+fname+"(l, v)")
 	fmt.Fprintln(g.out, "}")
 
+	// Make the generated code fail to compile if the methods drift.
+	if !g.noStdMarshalers {
+		g.useImport(pkgJSON, "json")
+		fmt.Fprintln(g.out, "var _ json.Unmarshaler = (*"+typ+")(nil)")
+	}
+	fmt.Fprintln(g.out, "var _ easyjson.Unmarshaler = (*"+typ+")(nil)")
+
+	if g.reset {
+		g.genReset(t)
+	}
+
+	return nil
+}
+
+func (g *Generator) genStructDecodeStreamer(t reflect.Type) error {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+	default:
+		if !isPrimitive(t) {
+			return fmt.Errorf("cannot generate encoder/decoder for %v, not a struct/slice/array/map/primitive type", t)
+		}
+	}
+	if !canDeclareMethods(t) {
+		return nil
+	}
+
+	g.useImport("io", "io")
+
+	fname := g.getDecoderName(t)
+	typ := g.getType(t)
+
+	fmt.Fprintln(g.out, "// DecodeJSON reads the JSON encoding of v from r while decoding it")
+	fmt.Fprintln(g.out, "func (v *"+typ+") DecodeJSON(r io.Reader) error {")
+	fmt.Fprintln(g.out, "  in := jlexer.NewStreamingLexer(r)")
+	if g.interfaceNumberMode == NumberJSONNumber {
+		fmt.Fprintln(g.out, "  in.UseNumber = true")
+	}
+	if g.lenientTypes {
+		fmt.Fprintln(g.out, "  in.LenientTypes = true")
+	}
+	if g.acceptQuotedNumbers {
+		fmt.Fprintln(g.out, "  in.AcceptQuotedNumbers = true")
+	}
+	if g.json5 {
+		fmt.Fprintln(g.out, "  in.JSON5 = true")
+	}
+	if g.maxInputBytes > 0 {
+		fmt.Fprintf(g.out, "  in.MaxInputBytes = %d\n", g.maxInputBytes)
+	}
+	fmt.Fprintln(g.out, "  "+fname+"(in, v)")
+	fmt.Fprintln(g.out, "  return in.Error()")
+	fmt.Fprintln(g.out, "}")
+
 	return nil
 }
@@ -4,11 +4,14 @@ import (
 	"encoding"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jwriter"
 )
 
 func (g *Generator) getEncoderName(t reflect.Type) string {
@@ -28,12 +31,14 @@ var primitiveEncoders = map[reflect.Kind]string{
 	reflect.Uint16:  "out.Uint16(uint16(%v))",
 	reflect.Uint32:  "out.Uint32(uint32(%v))",
 	reflect.Uint64:  "out.Uint64(uint64(%v))",
+	reflect.Uintptr: "out.Uintptr(uintptr(%v))",
 	reflect.Float32: "out.Float32(float32(%v))",
 	reflect.Float64: "out.Float64(float64(%v))",
 }
 
 var primitiveStringEncoders = map[reflect.Kind]string{
 	reflect.String:  "out.String(string(%v))",
+	reflect.Bool:    "out.BoolStr(bool(%v))",
 	reflect.Int:     "out.IntStr(int(%v))",
 	reflect.Int8:    "out.Int8Str(int8(%v))",
 	reflect.Int16:   "out.Int16Str(int16(%v))",
@@ -56,26 +61,84 @@ type fieldTags struct {
 	omit        bool
 	omitEmpty   bool
 	noOmitEmpty bool
+	omitZero    bool
 	asString    bool
 	required    bool
 	intern      bool
 	noCopy      bool
+	inline      bool
+	extra       bool
+	readOnly    bool // decoded, but never encoded
+	computed    bool // encoded, but ignored when decoding
+	set         bool // map[K]struct{} encoded as an array of the keys
+
+	timeFormat string
+}
+
+// timeType is handled natively rather than through its json.Marshaler implementation.
+var timeType = reflect.TypeOf(time.Time{})
+
+// tformat values encoding time.Time as integer numbers of seconds or milliseconds
+// since the Unix epoch rather than strings in a layout.
+const (
+	timeFormatUnix      = "unix"
+	timeFormatUnixMilli = "unixmilli"
+)
+
+// durationType is encoded as a string rather than a number of nanoseconds if
+// requested with DurationAsString.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// sqlNullValue returns the field holding the value of t if it is one of the
+// database/sql Null types, like sql.NullString or sql.Null[T], which are encoded
+// as the value if it is valid and as null otherwise.
+func sqlNullValue(t reflect.Type) (reflect.StructField, bool) {
+	if t.PkgPath() != "database/sql" || !strings.HasPrefix(t.Name(), "Null") || t.Kind() != reflect.Struct || t.NumField() != 2 {
+		return reflect.StructField{}, false
+	}
+	if valid := t.Field(1); valid.Name != "Valid" || valid.Type.Kind() != reflect.Bool {
+		return reflect.StructField{}, false
+	}
+	return t.Field(0), true
 }
 
-// parseFieldTags parses the json field tag into a structure.
-func parseFieldTags(f reflect.StructField) fieldTags {
+// jsonNumberType is written verbatim to keep the exact numeric representation.
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+// bigIntType and bigFloatType are encoded as JSON numbers with all of their
+// digits, and decoded from the text of numbers rather than through a float64.
+var (
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+)
+
+// rawMessageType is passed through as is rather than through its json.Marshaler
+// and json.Unmarshaler implementations.
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// parseFieldTags parses the json field tag stored under tagKey into a structure.
+func parseFieldTags(f reflect.StructField, tagKey string) fieldTags {
 	var ret fieldTags
 
-	for i, s := range strings.Split(f.Tag.Get("json"), ",") {
+	tag := f.Tag.Get(tagKey)
+	if tag == "-" {
+		// "-," names the field "-" rather than omitting it, as in encoding/json.
+		ret.omit = true
+		return ret
+	}
+
+	ret.timeFormat = f.Tag.Get("tformat")
+
+	for i, s := range strings.Split(tag, ",") {
 		switch {
-		case i == 0 && s == "-":
-			ret.omit = true
 		case i == 0:
 			ret.name = s
 		case s == "omitempty":
 			ret.omitEmpty = true
 		case s == "!omitempty":
 			ret.noOmitEmpty = true
+		case s == "omitzero":
+			ret.omitZero = true
 		case s == "string":
 			ret.asString = true
 		case s == "required":
@@ -84,16 +147,89 @@ func parseFieldTags(f reflect.StructField) fieldTags {
 			ret.intern = true
 		case s == "nocopy":
 			ret.noCopy = true
+		case s == "inline":
+			ret.inline = true
+		case s == "extra":
+			ret.extra = true
+		case s == "readonly":
+			ret.readOnly = true
+		case s == "computed":
+			ret.computed = true
+		case s == "set":
+			ret.set = true
 		}
 	}
 
 	return ret
 }
 
+// checkStringTag returns an error if the 'string' tag option is set for a field
+// type that cannot be represented as a quoted value.
+func checkStringTag(t reflect.Type, f reflect.StructField, tags fieldTags) error {
+	if !tags.asString {
+		return nil
+	}
+
+	ft := f.Type
+	if ft.Kind() == reflect.Ptr {
+		ft = ft.Elem()
+	}
+	if hasCustomMarshaler(ft) || hasCustomUnmarshaler(ft) || primitiveStringEncoders[ft.Kind()] != "" {
+		return nil
+	}
+	return fmt.Errorf("field %v of %v: 'string' option is not supported for type %v: only string, numeric and bool fields are allowed", f.Name, t, f.Type)
+}
+
 // genTypeEncoder generates code that encodes in of type t into the writer, but uses marshaler interface if implemented by t.
 func (g *Generator) genTypeEncoder(t reflect.Type, in string, tags fieldTags, indent int, assumeNonEmpty bool) error {
 	ws := strings.Repeat("  ", indent)
 
+	if c, ok := g.customCodecs[t]; ok && c.encode != "" {
+		fmt.Fprintln(g.out, ws+g.funcRef(c.encode)+"(out, "+in+")")
+		return nil
+	}
+	if t == timeType {
+		switch tags.timeFormat {
+		case timeFormatUnix:
+			fmt.Fprintln(g.out, ws+"out.UnixTime("+in+")")
+		case timeFormatUnixMilli:
+			fmt.Fprintln(g.out, ws+"out.UnixMilliTime("+in+")")
+		default:
+			fmt.Fprintln(g.out, ws+"out.Time("+in+", "+g.timeLayout(tags, "RFC3339Nano")+")")
+		}
+		return nil
+	}
+	if t == durationType && g.durationAsString {
+		fmt.Fprintln(g.out, ws+"out.Duration("+in+")")
+		return nil
+	}
+	if t == rawMessageType {
+		fmt.Fprintln(g.out, ws+"out.Raw("+in+", nil)")
+		return nil
+	}
+	if t == bigIntType {
+		fmt.Fprintln(g.out, ws+"out.BigInt(&("+in+"))")
+		return nil
+	}
+	if t == bigFloatType {
+		fmt.Fprintln(g.out, ws+"out.BigFloat(&("+in+"))")
+		return nil
+	}
+	if f, ok := sqlNullValue(t); ok {
+		fmt.Fprintln(g.out, ws+"if ("+in+").Valid {")
+		if err := g.genTypeEncoder(f.Type, "("+in+")."+f.Name, tags, indent+1, false); err != nil {
+			return err
+		}
+		fmt.Fprintln(g.out, ws+"} else {")
+		fmt.Fprintln(g.out, ws+`  out.RawString("null")`)
+		fmt.Fprintln(g.out, ws+"}")
+		return nil
+	}
+	if t == jsonNumberType && !tags.asString {
+		fmt.Fprintln(g.out, ws+"out.JsonNumber("+in+")")
+		return nil
+	}
+
 	marshalerIface := reflect.TypeOf((*easyjson.Marshaler)(nil)).Elem()
 	if reflect.PtrTo(t).Implements(marshalerIface) {
 		fmt.Fprintln(g.out, ws+"("+in+").MarshalEasyJSON(out)")
@@ -116,7 +252,21 @@ func (g *Generator) genTypeEncoder(t reflect.Type, in string, tags fieldTags, in
 	return err
 }
 
+// timeLayout returns the layout expression for time.Time values: the field's
+// tformat tag if present, or the given constant from the time package.
+func (g *Generator) timeLayout(tags fieldTags, def string) string {
+	if tags.timeFormat != "" {
+		return strconv.Quote(tags.timeFormat)
+	}
+	return g.pkgAlias("time") + "." + def
+}
+
 // returns true if the type t implements one of the custom marshaler interfaces
+func hasBeforeMarshaler(t reflect.Type) bool {
+	t = reflect.PtrTo(t)
+	return t.Implements(reflect.TypeOf((*easyjson.BeforeMarshaler)(nil)).Elem())
+}
+
 func hasCustomMarshaler(t reflect.Type) bool {
 	t = reflect.PtrTo(t)
 	return t.Implements(reflect.TypeOf((*easyjson.Marshaler)(nil)).Elem()) ||
@@ -139,6 +289,19 @@ func (g *Generator) genTypeEncoderNoCheck(t reflect.Type, in string, tags fieldT
 		return nil
 	}
 
+	leave, ok := g.enterInline(t)
+	if ok && g.reusesFunc(t, tags) {
+		leave()
+		ok = false
+	}
+	if !ok {
+		enc := g.getEncoderName(t)
+		g.addEncoderType(t)
+		fmt.Fprintln(g.out, ws+enc+"(out, "+in+")")
+		return nil
+	}
+	defer leave()
+
 	switch t.Kind() {
 	case reflect.Slice:
 		elem := t.Elem()
@@ -201,7 +364,7 @@ func (g *Generator) genTypeEncoderNoCheck(t reflect.Type, in string, tags fieldT
 
 	case reflect.Struct:
 		enc := g.getEncoderName(t)
-		g.addType(t)
+		g.addEncoderType(t)
 
 		fmt.Fprintln(g.out, ws+enc+"(out, "+in+")")
 
@@ -221,11 +384,9 @@ func (g *Generator) genTypeEncoderNoCheck(t reflect.Type, in string, tags fieldT
 		}
 
 	case reflect.Map:
-		key := t.Key()
-		keyEnc, ok := primitiveStringEncoders[key.Kind()]
-		if !ok && !hasCustomMarshaler(key) {
-			return fmt.Errorf("map key type %v not supported: only string and integer keys and types implementing Marshaler interfaces are allowed", key)
-		} // else assume the caller knows what they are doing and that the custom marshaler performs the translation from the key type to a string or integer
+		if tags.set && isSetType(t) {
+			return g.genSetEncoder(t, in, tags, indent, assumeNonEmpty)
+		}
 		tmpVar := g.uniqueVarName()
 
 		if !assumeNonEmpty {
@@ -237,44 +398,38 @@ func (g *Generator) genTypeEncoderNoCheck(t reflect.Type, in string, tags fieldT
 		}
 		fmt.Fprintln(g.out, ws+"  out.RawByte('{')")
 		fmt.Fprintln(g.out, ws+"  "+tmpVar+"First := true")
-		fmt.Fprintln(g.out, ws+"  for "+tmpVar+"Name, "+tmpVar+"Value := range "+in+" {")
-		fmt.Fprintln(g.out, ws+"    if "+tmpVar+"First { "+tmpVar+"First = false } else { out.RawByte(',') }")
-
-		// NOTE: extra check for TextMarshaler. It overrides default methods.
-		if reflect.PtrTo(key).Implements(reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()) {
-			fmt.Fprintln(g.out, ws+"    "+fmt.Sprintf("out.RawText(("+tmpVar+"Name).MarshalText()"+")"))
-		} else if keyEnc != "" {
-			fmt.Fprintln(g.out, ws+"    "+fmt.Sprintf(keyEnc, tmpVar+"Name"))
-		} else {
-			if err := g.genTypeEncoder(key, tmpVar+"Name", tags, indent+2, false); err != nil {
-				return err
-			}
-		}
-
-		fmt.Fprintln(g.out, ws+"    out.RawByte(':')")
-
-		if err := g.genTypeEncoder(t.Elem(), tmpVar+"Value", tags, indent+2, false); err != nil {
+		if err := g.genMapEntriesEncoder(t, in, tmpVar, tmpVar+"First", tags, indent+1); err != nil {
 			return err
 		}
-
-		fmt.Fprintln(g.out, ws+"  }")
 		fmt.Fprintln(g.out, ws+"  out.RawByte('}')")
 		fmt.Fprintln(g.out, ws+"}")
 
 	case reflect.Interface:
+		if impl, ok := g.interfaceImpls[t]; ok {
+			return g.genInterfaceImplEncoder(t, impl, in, tags, indent)
+		}
 		if t.NumMethod() != 0 {
 			if g.interfaceIsEasyjsonMarshaller(t) {
-				fmt.Fprintln(g.out, ws+in+".MarshalEasyJSON(out)")
+				fmt.Fprintln(g.out, ws+"if "+in+" == nil {")
+				fmt.Fprintln(g.out, ws+`  out.RawString("null")`)
+				fmt.Fprintln(g.out, ws+"} else {")
+				fmt.Fprintln(g.out, ws+"  "+in+".MarshalEasyJSON(out)")
+				fmt.Fprintln(g.out, ws+"}")
 			} else if g.interfaceIsJSONMarshaller(t) {
-				fmt.Fprintln(g.out, ws+"if m, ok := "+in+".(easyjson.Marshaler); ok {")
+				fmt.Fprintln(g.out, ws+"if "+in+" == nil {")
+				fmt.Fprintln(g.out, ws+`  out.RawString("null")`)
+				fmt.Fprintln(g.out, ws+"} else if m, ok := "+in+".(easyjson.Marshaler); ok {")
 				fmt.Fprintln(g.out, ws+"  m.MarshalEasyJSON(out)")
 				fmt.Fprintln(g.out, ws+"} else {")
-				fmt.Fprintln(g.out, ws+in+".MarshalJSON()")
+				fmt.Fprintln(g.out, ws+"  out.Raw("+in+".MarshalJSON())")
 				fmt.Fprintln(g.out, ws+"}")
 			} else {
 				return fmt.Errorf("interface type %v not supported: only interface{} and interfaces that implement json or easyjson Marshaling are allowed", t)
 			}
+		} else if len(g.marshallerTypes) > 0 {
+			return g.genInterfaceDispatchEncoder(in, tags, indent)
 		} else {
+			g.useImport(pkgJSON, "json")
 			fmt.Fprintln(g.out, ws+"if m, ok := "+in+".(easyjson.Marshaler); ok {")
 			fmt.Fprintln(g.out, ws+"  m.MarshalEasyJSON(out)")
 			fmt.Fprintln(g.out, ws+"} else if m, ok := "+in+".(json.Marshaler); ok {")
@@ -289,6 +444,54 @@ func (g *Generator) genTypeEncoderNoCheck(t reflect.Type, in string, tags fieldT
 	return nil
 }
 
+// genInterfaceDispatchEncoder generates encoding code for an interface{} value
+// which encodes the types registered with RegisterMarshallerType and pointers to
+// them with their generated encoders, and other ones with jwriter.Interface.
+func (g *Generator) genInterfaceDispatchEncoder(in string, tags fieldTags, indent int) error {
+	ws := strings.Repeat("  ", indent)
+	vVar := g.uniqueVarName()
+
+	fmt.Fprintln(g.out, ws+"switch "+vVar+" := "+in+".(type) {")
+	for _, t := range g.marshallerTypes {
+		for _, t := range []reflect.Type{t, reflect.PtrTo(t)} {
+			fmt.Fprintln(g.out, ws+"case "+g.getType(t)+":")
+			if err := g.genTypeEncoder(t, vVar, tags, indent+1, false); err != nil {
+				return err
+			}
+		}
+	}
+	fmt.Fprintln(g.out, ws+"default:")
+	fmt.Fprintln(g.out, ws+"  out.Interface("+vVar+")")
+	fmt.Fprintln(g.out, ws+"}")
+	return nil
+}
+
+// genInterfaceImplEncoder generates encoding code for an interface type with
+// registered concrete types, calling the encoder of the concrete type.
+func (g *Generator) genInterfaceImplEncoder(t reflect.Type, impl interfaceImpl, in string, tags fieldTags, indent int) error {
+	ws := strings.Repeat("  ", indent)
+	v := g.uniqueVarName()
+
+	fmt.Fprintln(g.out, ws+"switch "+v+" := "+in+".(type) {")
+	fmt.Fprintln(g.out, ws+"case nil:")
+	fmt.Fprintln(g.out, ws+`  out.RawString("null")`)
+	for _, name := range impl.tags {
+		implType := impl.impls[name]
+		if !implType.Implements(t) {
+			return fmt.Errorf("type %v registered for %v tag %q does not implement it", implType, t, name)
+		}
+		fmt.Fprintln(g.out, ws+"case "+g.getType(implType)+":")
+		if err := g.genTypeEncoder(implType, v, tags, indent+1, false); err != nil {
+			return err
+		}
+	}
+	g.useImport(pkgJSON, "json")
+	fmt.Fprintln(g.out, ws+"default:")
+	fmt.Fprintln(g.out, ws+"  out.Raw(json.Marshal("+v+"))")
+	fmt.Fprintln(g.out, ws+"}")
+	return nil
+}
+
 func (g *Generator) interfaceIsEasyjsonMarshaller(t reflect.Type) bool {
 	return t.Implements(reflect.TypeOf((*easyjson.Marshaler)(nil)).Elem())
 }
@@ -297,11 +500,28 @@ func (g *Generator) interfaceIsJSONMarshaller(t reflect.Type) bool {
 	return t.Implements(reflect.TypeOf((*json.Marshaler)(nil)).Elem())
 }
 
+// isZeroerType is the type of the values with an IsZero method.
+var isZeroerType = reflect.TypeOf((*interface{ IsZero() bool })(nil)).Elem()
+
+// isEmptierType is the type of the values with an IsEmpty method, which decides
+// whether omitempty fields of their types are omitted.
+var isEmptierType = reflect.TypeOf((*interface{ IsEmpty() bool })(nil)).Elem()
+
 func (g *Generator) notEmptyCheck(t reflect.Type, v string) string {
+	if t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface && reflect.PtrTo(t).Implements(isEmptierType) {
+		return "!(" + v + ").IsEmpty()"
+	}
 	optionalIface := reflect.TypeOf((*easyjson.Optional)(nil)).Elem()
 	if reflect.PtrTo(t).Implements(optionalIface) {
 		return "(" + v + ").IsDefined()"
 	}
+	if _, ok := sqlNullValue(t); ok {
+		return "(" + v + ").Valid"
+	}
+	if g.omitEmptyIsZero && t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface &&
+		reflect.PtrTo(t).Implements(isZeroerType) {
+		return "!(" + v + ").IsZero()"
+	}
 
 	switch t.Kind() {
 	case reflect.Slice, reflect.Map:
@@ -324,29 +544,245 @@ func (g *Generator) notEmptyCheck(t reflect.Type, v string) string {
 	}
 }
 
+// notZeroCheck returns the expression checking that v of type t is not its zero
+// value, which fields with the omitzero option are omitted for like in
+// encoding/json: the IsZero method decides for types which have one, and
+// otherwise all the fields or elements of structs and arrays have to be zero.
+func (g *Generator) notZeroCheck(t reflect.Type, v string) string {
+	switch {
+	case t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface:
+		if t.Implements(isZeroerType) {
+			return v + " != nil && !(" + v + ").IsZero()"
+		}
+		return v + " != nil"
+	case reflect.PtrTo(t).Implements(isZeroerType):
+		return "!(" + v + ").IsZero()"
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+		return v + " != nil"
+	case reflect.Struct, reflect.Array:
+		if t.Comparable() && !hasInterface(t) {
+			return v + " != (" + g.getType(t) + "{})"
+		}
+		// Values with interfaces may hold incomparable types, making == panic.
+		var checks []string
+		if t.Kind() == reflect.Array {
+			for i := 0; i < t.Len(); i++ {
+				checks = append(checks, g.notZeroCheck(t.Elem(), fmt.Sprintf("(%v)[%d]", v, i)))
+			}
+		} else {
+			for i := 0; i < t.NumField(); i++ {
+				f := t.Field(i)
+				if f.Name == "_" {
+					continue
+				}
+				if f.PkgPath != "" && !g.isOwnPkg(f.PkgPath) {
+					// The field is out of reach, so the value is never zero.
+					return "true"
+				}
+				checks = append(checks, g.notZeroCheck(f.Type, v+"."+f.Name))
+			}
+		}
+		if len(checks) == 0 {
+			return "false"
+		}
+		return "(" + strings.Join(checks, ") || (") + ")"
+	}
+	return g.notEmptyCheck(t, v)
+}
+
+// hasInterface returns whether values of type t have interface values in them,
+// not counting the ones behind pointers, slices or maps.
+func hasInterface(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Array:
+		return hasInterface(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasInterface(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// genMapEntriesEncoder generates code writing the entries of the map in of type
+// t, separated by commas, with a comma before the first one too unless the bool
+// variable firstVar is true, which is then cleared. The names of the variables
+// used start with tmpVar.
+func (g *Generator) genMapEntriesEncoder(t reflect.Type, in, tmpVar, firstVar string, tags fieldTags, indent int) error {
+	ws := strings.Repeat("  ", indent)
+
+	key := t.Key()
+	keyEnc, ok := primitiveStringEncoders[key.Kind()]
+	if key.Kind() == reflect.Bool {
+		ok = false
+	}
+	if !ok && !hasCustomMarshaler(key) {
+		return fmt.Errorf("map key type %v not supported: only string and integer keys and types implementing Marshaler interfaces are allowed", key)
+	} // else assume the caller knows what they are doing and that the custom marshaler performs the translation from the key type to a string or integer
+
+	textKey := reflect.PtrTo(key).Implements(reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem())
+	if !g.noSortMapKeys && key.Kind() == reflect.String && !textKey {
+		// String keys are written in sorted order like encoding/json does.
+		sortPkg := g.pkgAlias("sort")
+		fmt.Fprintln(g.out, ws+tmpVar+"Keys := make([]string, 0, len("+in+"))")
+		fmt.Fprintln(g.out, ws+"for "+tmpVar+"Name := range "+in+" {")
+		fmt.Fprintln(g.out, ws+"  "+tmpVar+"Keys = append("+tmpVar+"Keys, string("+tmpVar+"Name))")
+		fmt.Fprintln(g.out, ws+"}")
+		fmt.Fprintln(g.out, ws+sortPkg+".Strings("+tmpVar+"Keys)")
+		fmt.Fprintln(g.out, ws+"for _, "+tmpVar+"Name := range "+tmpVar+"Keys {")
+		fmt.Fprintln(g.out, ws+"  "+tmpVar+"Value := ("+in+")["+g.getType(key)+"("+tmpVar+"Name)]")
+	} else if !g.noSortMapKeys && textKey {
+		// Keys marshaled to text are sorted by the text like encoding/json does.
+		sortPkg := g.pkgAlias("sort")
+		fmt.Fprintln(g.out, ws+tmpVar+"Keys := make([]struct{ text string; key "+g.getType(key)+" }, 0, len("+in+"))")
+		fmt.Fprintln(g.out, ws+"for "+tmpVar+"Name := range "+in+" {")
+		fmt.Fprintln(g.out, ws+"  "+tmpVar+"Text, err := "+tmpVar+"Name.MarshalText()")
+		fmt.Fprintln(g.out, ws+"  if err != nil {")
+		fmt.Fprintln(g.out, ws+"    out.Raw(nil, err)")
+		fmt.Fprintln(g.out, ws+"  }")
+		fmt.Fprintln(g.out, ws+"  "+tmpVar+"Keys = append("+tmpVar+"Keys, struct{ text string; key "+g.getType(key)+" }{string("+tmpVar+"Text), "+tmpVar+"Name})")
+		fmt.Fprintln(g.out, ws+"}")
+		fmt.Fprintln(g.out, ws+sortPkg+".Slice("+tmpVar+"Keys, func(i, j int) bool { return "+tmpVar+"Keys[i].text < "+tmpVar+"Keys[j].text })")
+		fmt.Fprintln(g.out, ws+"for _, "+tmpVar+"Key := range "+tmpVar+"Keys {")
+		fmt.Fprintln(g.out, ws+"  "+tmpVar+"Value := ("+in+")["+tmpVar+"Key.key]")
+	} else if !g.noSortMapKeys && !textKey && isNumberKind(key.Kind()) {
+		// Number keys are sorted by their decimal form like encoding/json does.
+		sortPkg := g.pkgAlias("sort")
+		text := g.numberKeyText(key, tmpVar+"Name")
+		fmt.Fprintln(g.out, ws+tmpVar+"Keys := make([]struct{ text string; key "+g.getType(key)+" }, 0, len("+in+"))")
+		fmt.Fprintln(g.out, ws+"for "+tmpVar+"Name := range "+in+" {")
+		fmt.Fprintln(g.out, ws+"  "+tmpVar+"Keys = append("+tmpVar+"Keys, struct{ text string; key "+g.getType(key)+" }{"+text+", "+tmpVar+"Name})")
+		fmt.Fprintln(g.out, ws+"}")
+		fmt.Fprintln(g.out, ws+sortPkg+".Slice("+tmpVar+"Keys, func(i, j int) bool { return "+tmpVar+"Keys[i].text < "+tmpVar+"Keys[j].text })")
+		fmt.Fprintln(g.out, ws+"for _, "+tmpVar+"Key := range "+tmpVar+"Keys {")
+		fmt.Fprintln(g.out, ws+"  "+tmpVar+"Name := "+tmpVar+"Key.key")
+		fmt.Fprintln(g.out, ws+"  "+tmpVar+"Value := ("+in+")["+tmpVar+"Name]")
+	} else {
+		if g.fuzzHarness {
+			return errUnsortedFuzzKeys(key)
+		}
+		fmt.Fprintln(g.out, ws+"for "+tmpVar+"Name, "+tmpVar+"Value := range "+in+" {")
+	}
+	fmt.Fprintln(g.out, ws+"  if "+firstVar+" { "+firstVar+" = false } else { out.RawByte(',') }")
+
+	// NOTE: extra check for TextMarshaler. It overrides default methods.
+	if !g.noSortMapKeys && textKey {
+		fmt.Fprintln(g.out, ws+"  out.String("+tmpVar+"Key.text)")
+	} else if textKey {
+		fmt.Fprintln(g.out, ws+"  "+fmt.Sprintf("out.RawText(("+tmpVar+"Name).MarshalText()"+")"))
+	} else if keyEnc != "" {
+		fmt.Fprintln(g.out, ws+"  "+fmt.Sprintf(keyEnc, tmpVar+"Name"))
+	} else {
+		if err := g.genTypeEncoder(key, tmpVar+"Name", tags, indent+1, false); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(g.out, ws+"  out.RawByte(':')")
+
+	if err := g.genTypeEncoder(t.Elem(), tmpVar+"Value", tags, indent+1, false); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(g.out, ws+"}")
+	return nil
+}
+
+// isNumberKind returns whether k is the kind of an integer or a float type.
+func isNumberKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
+
+// numberKeyText returns the expression formatting the map key v of the number
+// type t in decimal, to sort the keys by.
+func (g *Generator) numberKeyText(t reflect.Type, v string) string {
+	strconvPkg := g.pkgAlias("strconv")
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconvPkg + ".FormatInt(int64(" + v + "), 10)"
+	case reflect.Float32:
+		return strconvPkg + ".FormatFloat(float64(" + v + "), 'g', -1, 32)"
+	case reflect.Float64:
+		return strconvPkg + ".FormatFloat(float64(" + v + "), 'g', -1, 64)"
+	default:
+		return strconvPkg + ".FormatUint(uint64(" + v + "), 10)"
+	}
+}
+
+// quoteKey returns the object key name as a JSON string, escaped once at
+// generation time the way the writer escapes strings, so that the generated code
+// writes the key with its comma and colon as a single constant. Unlike Go string
+// literals, it escapes control characters as \u00XX rather than \xXX.
+func quoteKey(name string) string {
+	w := jwriter.Writer{NoEscapeHTML: true}
+	w.String(name)
+	return string(w.Buffer.BuildBytes())
+}
+
 func (g *Generator) genStructFieldEncoder(t reflect.Type, f reflect.StructField, first, firstCondition bool) (bool, error) {
-	jsonName := g.fieldNamer.GetJSONFieldName(t, f)
-	tags := parseFieldTags(f)
+	jsonName := g.jsonFieldName(t, f)
+	tags := parseFieldTags(f, g.tagKey)
 
-	if tags.omit {
+	if tags.omit || tags.readOnly {
 		return firstCondition, nil
 	}
+	if err := checkStringTag(t, f, tags); err != nil {
+		return firstCondition, err
+	}
+	if err := checkSetTag(t, f, tags); err != nil {
+		return firstCondition, err
+	}
+
+	in := "in." + fieldSelector(t, f)
+	if a, ok := g.fieldAccessor(t, f); ok {
+		if a.getter == "" {
+			return firstCondition, nil
+		}
+		in = g.uniqueVarName()
+		fmt.Fprintln(g.out, "  "+in+" := in."+a.getter+"()")
+	}
 
 	toggleFirstCondition := firstCondition
 
-	noOmitEmpty := (!tags.omitEmpty && !g.omitEmpty) || tags.noOmitEmpty
+	// Fields promoted through nil embedded pointers are skipped.
+	var nilChecks []string
+	for _, p := range embeddedPointers(t, f) {
+		nilChecks = append(nilChecks, "in."+p.path+" != nil")
+	}
+	if len(nilChecks) > 0 {
+		fmt.Fprintln(g.out, "  if", strings.Join(nilChecks, " && "), "{")
+	}
+
+	omitEmpty := (tags.omitEmpty || g.omitEmpty) && !tags.noOmitEmpty
+	noOmitEmpty := !omitEmpty && !tags.omitZero
 	if noOmitEmpty {
 		fmt.Fprintln(g.out, "  {")
-		toggleFirstCondition = false
+		if len(nilChecks) == 0 {
+			toggleFirstCondition = false
+		}
 	} else {
-		fmt.Fprintln(g.out, "  if", g.notEmptyCheck(f.Type, "in."+f.Name), "{")
+		var checks []string
+		if omitEmpty {
+			checks = append(checks, g.notEmptyCheck(f.Type, in))
+		}
+		if tags.omitZero {
+			checks = append(checks, g.notZeroCheck(f.Type, in))
+		}
+		fmt.Fprintln(g.out, "  if", strings.Join(checks, " && "), "{")
 		// can be any in runtime, so toggleFirstCondition stay as is
 	}
 
 	if firstCondition {
-		fmt.Fprintf(g.out, "    const prefix string = %q\n", ","+strconv.Quote(jsonName)+":")
+		fmt.Fprintf(g.out, "    const prefix string = %q\n", ","+quoteKey(jsonName)+":")
 		if first {
-			if !noOmitEmpty {
+			if !noOmitEmpty || len(nilChecks) > 0 {
 				fmt.Fprintln(g.out, "      first = false")
 			}
 			fmt.Fprintln(g.out, "      out.RawString(prefix[1:])")
@@ -359,37 +795,49 @@ func (g *Generator) genStructFieldEncoder(t reflect.Type, f reflect.StructField,
 			fmt.Fprintln(g.out, "    }")
 		}
 	} else {
-		fmt.Fprintf(g.out, "    const prefix string = %q\n", ","+strconv.Quote(jsonName)+":")
+		fmt.Fprintf(g.out, "    const prefix string = %q\n", ","+quoteKey(jsonName)+":")
 		fmt.Fprintln(g.out, "    out.RawString(prefix)")
 	}
 
-	if err := g.genTypeEncoder(f.Type, "in."+f.Name, tags, 2, !noOmitEmpty); err != nil {
+	if err := g.genTypeEncoder(f.Type, in, tags, 2, !noOmitEmpty); err != nil {
 		return toggleFirstCondition, err
 	}
 	fmt.Fprintln(g.out, "  }")
+	if len(nilChecks) > 0 {
+		fmt.Fprintln(g.out, "  }")
+	}
 	return toggleFirstCondition, nil
 }
 
 func (g *Generator) genEncoder(t reflect.Type) error {
-	switch t.Kind() {
-	case reflect.Slice, reflect.Array, reflect.Map:
+	switch {
+	case t.Kind() == reflect.Slice, t.Kind() == reflect.Array, t.Kind() == reflect.Map, isPrimitive(t):
 		return g.genSliceArrayMapEncoder(t)
 	default:
 		return g.genStructEncoder(t)
 	}
 }
 
+// isPrimitive returns whether t is a string, bool or numeric type, which can
+// also be requested directly when named, like "type Status int".
+func isPrimitive(t reflect.Type) bool {
+	return primitiveEncoders[t.Kind()] != ""
+}
+
 func (g *Generator) genSliceArrayMapEncoder(t reflect.Type) error {
 	switch t.Kind() {
 	case reflect.Slice, reflect.Array, reflect.Map:
 	default:
-		return fmt.Errorf("cannot generate encoder/decoder for %v, not a slice/array/map type", t)
+		if !isPrimitive(t) {
+			return fmt.Errorf("cannot generate encoder/decoder for %v, not a slice/array/map/primitive type", t)
+		}
 	}
 
 	fname := g.getEncoderName(t)
 	typ := g.getType(t)
 
 	fmt.Fprintln(g.out, "func "+fname+"(out *jwriter.Writer, in "+typ+") {")
+	g.funcType = t
 	err := g.genTypeEncoderNoCheck(t, "in", fieldTags{}, 1, false)
 	if err != nil {
 		return err
@@ -403,18 +851,37 @@ func (g *Generator) genStructEncoder(t reflect.Type) error {
 		return fmt.Errorf("cannot generate encoder/decoder for %v, not a struct type", t)
 	}
 
-	fname := g.getEncoderName(t)
+	return g.genStructFieldsEncoder(t, g.getEncoderName(t), nil)
+}
+
+// genStructFieldsEncoder generates the encoder func fname of the struct type t,
+// writing only the fields in the view v unless it is nil.
+func (g *Generator) genStructFieldsEncoder(t reflect.Type, fname string, v *view) error {
 	typ := g.getType(t)
 
 	fmt.Fprintln(g.out, "func "+fname+"(out *jwriter.Writer, in "+typ+") {")
+	if hasBeforeMarshaler(t) {
+		fmt.Fprintln(g.out, "  if err := in.BeforeMarshal(); err != nil {")
+		fmt.Fprintln(g.out, "    if out.Error == nil {")
+		fmt.Fprintln(g.out, "      out.Error = err")
+		fmt.Fprintln(g.out, "    }")
+		fmt.Fprintln(g.out, "    return")
+		fmt.Fprintln(g.out, "  }")
+	}
 	fmt.Fprintln(g.out, "  out.RawByte('{')")
+	// Members are written with a leading comma unless none was written before,
+	// which is tracked at runtime by first where omitted members make it
+	// unknown statically, so no trailing comma is left by omitted ones.
 	fmt.Fprintln(g.out, "  first := true")
 	fmt.Fprintln(g.out, "  _ = first")
 
-	fs, err := getStructFields(t)
+	fs, err := g.structFields(t)
 	if err != nil {
 		return fmt.Errorf("cannot generate encoder for %v: %v", t, err)
 	}
+	if v != nil {
+		fs = v.fields(fs)
+	}
 
 	firstCondition := true
 	for i, f := range fs {
@@ -425,7 +892,21 @@ func (g *Generator) genStructEncoder(t reflect.Type) error {
 		}
 	}
 
-	if hasUnknownsMarshaler(t) {
+	inlineMap, err := g.inlineMapField(t)
+	if err != nil {
+		return fmt.Errorf("cannot generate encoder for %v: %v", t, err)
+	}
+	if inlineMap != nil && (v == nil || v.has(*inlineMap)) {
+		if !firstCondition {
+			fmt.Fprintln(g.out, "  first = false")
+		}
+		tags := parseFieldTags(*inlineMap, g.tagKey)
+		if err := g.genMapEntriesEncoder(inlineMap.Type, "in."+fieldSelector(t, *inlineMap), g.uniqueVarName(), "first", tags, 1); err != nil {
+			return err
+		}
+	}
+
+	if hasUnknownsMarshaler(t) && v == nil {
 		if !firstCondition {
 			fmt.Fprintln(g.out, "  in.MarshalUnknowns(out, false)")
 		} else {
@@ -439,11 +920,65 @@ func (g *Generator) genStructEncoder(t reflect.Type) error {
 	return nil
 }
 
+// maxEstimateDepth limits how deep nested structs are looked into by estimateSize.
+const maxEstimateDepth = 3
+
+// estimateSize returns a rough lower estimate of the encoded size of a value of
+// type t, based on the fields of structs only, which MarshalJSON uses as its
+// buffer size hint. Custom marshalers are not taken into account.
+func (g *Generator) estimateSize(t reflect.Type, depth int) int {
+	switch t.Kind() {
+	case reflect.Bool:
+		return 4
+	case reflect.String:
+		return 2
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return 2
+	case reflect.Interface:
+		return 4
+	case reflect.Ptr:
+		if depth >= maxEstimateDepth {
+			return 4
+		}
+		return g.estimateSize(t.Elem(), depth+1)
+	case reflect.Struct:
+		if t == timeType {
+			return len(`"2006-01-02T15:04:05Z"`)
+		}
+		if t == bigIntType || t == bigFloatType {
+			return 1
+		}
+		if depth >= maxEstimateDepth {
+			return 2
+		}
+		fs, err := g.structFields(t)
+		if err != nil {
+			return 2
+		}
+		size := 2
+		for _, f := range fs {
+			if tags := parseFieldTags(f, g.tagKey); tags.omit || tags.readOnly {
+				continue
+			}
+			// The quoted name, colon and comma.
+			size += len(g.jsonFieldName(t, f)) + 4 + g.estimateSize(f.Type, depth+1)
+		}
+		return size
+	}
+	return 1
+}
+
 func (g *Generator) genStructMarshaler(t reflect.Type) error {
 	switch t.Kind() {
 	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
 	default:
-		return fmt.Errorf("cannot generate encoder/decoder for %v, not a struct/slice/array/map type", t)
+		if !isPrimitive(t) {
+			return fmt.Errorf("cannot generate encoder/decoder for %v, not a struct/slice/array/map/primitive type", t)
+		}
+	}
+	if !canDeclareMethods(t) {
+		// Only the funcs are generated for such types.
+		return nil
 	}
 
 	fname := g.getEncoderName(t)
@@ -452,16 +987,191 @@ func (g *Generator) genStructMarshaler(t reflect.Type) error {
 	if !g.noStdMarshalers {
 		fmt.Fprintln(g.out, "// MarshalJSON supports json.Marshaler interface")
 		fmt.Fprintln(g.out, "func (v "+typ+") MarshalJSON() ([]byte, error) {")
-		fmt.Fprintln(g.out, "  w := jwriter.Writer{}")
-		fmt.Fprintln(g.out, "  "+fname+"(&w, v)")
-		fmt.Fprintln(g.out, "  return w.Buffer.BuildBytes(), w.Error")
+		if g.sizeEstimator {
+			fmt.Fprintln(g.out, "  return v.MarshalJSONSized(v.estimatedSize())")
+		} else {
+			fmt.Fprintf(g.out, "  return v.MarshalJSONSized(%d)\n", g.estimateSize(t, 0))
+		}
+		fmt.Fprintln(g.out, "}")
+		fmt.Fprintln(g.out)
+		fmt.Fprintln(g.out, "// MarshalJSONSized is like MarshalJSON, but makes room for hint bytes of")
+		fmt.Fprintln(g.out, "// output before encoding, avoiding regrowth of the buffer if it is large enough")
+		fmt.Fprintln(g.out, "func (v "+typ+") MarshalJSONSized(hint int) ([]byte, error) {")
+		opts := g.writerOpts()
+		indenting := g.indentPrefix != "" || g.indent != ""
+		if indenting {
+			opts = append(opts, fmt.Sprintf("Prefix: %q", g.indentPrefix), fmt.Sprintf("Indent: %q", g.indent))
+		}
+		if g.pooledWriter {
+			// The writer is put back to the pool even if encoding panics.
+			fmt.Fprintln(g.out, "  w := jwriter.GetWriter()")
+			fmt.Fprintln(g.out, "  defer jwriter.PutWriter(w)")
+			for _, opt := range opts {
+				fmt.Fprintln(g.out, "  w."+strings.Replace(opt, ": ", " = ", 1))
+			}
+		} else {
+			fmt.Fprintln(g.out, "  w := &jwriter.Writer{"+strings.Join(opts, ", ")+"}")
+		}
+		fmt.Fprintln(g.out, "  w.Buffer.EnsureSpace(hint)")
+		fmt.Fprintln(g.out, "  "+fname+"(w, v)")
+		if indenting || g.pooledWriter {
+			// A pooled writer keeps its buffer, BuildBytes copies the data out of it.
+			fmt.Fprintln(g.out, "  return w.BuildBytes()")
+		} else {
+			fmt.Fprintln(g.out, "  return w.Buffer.BuildBytes(), w.Error")
+		}
 		fmt.Fprintln(g.out, "}")
 	}
 
+	if !g.noStdMarshalers && g.sizeEstimator {
+		fmt.Fprintln(g.out)
+		g.genSizeEstimatorMethod(t)
+	}
+
 	fmt.Fprintln(g.out, "// MarshalEasyJSON supports easyjson.Marshaler interface")
 	fmt.Fprintln(g.out, "func (v "+typ+") MarshalEasyJSON(w *jwriter.Writer) {")
 	fmt.Fprintln(g.out, "  "+fname+"(w, v)")
 	fmt.Fprintln(g.out, "}")
 
+	// Make the generated code fail to compile if the methods drift.
+	if !g.noStdMarshalers {
+		g.useImport(pkgJSON, "json")
+		fmt.Fprintln(g.out, "var _ json.Marshaler = (*"+typ+")(nil)")
+	}
+	fmt.Fprintln(g.out, "var _ easyjson.Marshaler = (*"+typ+")(nil)")
+
+	if g.appendJSON {
+		g.genAppendJSON(t)
+	}
+	if g.marshalJSONString {
+		g.genMarshalJSONString(t)
+	}
+	if g.writerTo {
+		g.genWriterTo(t)
+	}
+
+	return nil
+}
+
+// writerOpts returns the options set on the writers of the generated methods,
+// as fields of a jwriter.Writer literal.
+func (g *Generator) writerOpts() []string {
+	var opts []string
+	if g.noEscapeHTML {
+		opts = append(opts, "NoEscapeHTML: true")
+	}
+	if name := nanPolicyNames[g.nanPolicy]; name != "" {
+		opts = append(opts, "NaNPolicy: "+name)
+	}
+	if g.floatFormat != 0 {
+		opts = append(opts, fmt.Sprintf("FloatFormat: %q", g.floatFormat), fmt.Sprintf("FloatPrecision: %d", g.floatPrecision))
+	}
+	return opts
+}
+
+// genAppendJSON generates the AppendJSON method of the type t.
+func (g *Generator) genAppendJSON(t reflect.Type) {
+	fname := g.getEncoderName(t)
+	typ := g.getType(t)
+
+	opts := g.writerOpts()
+
+	fmt.Fprintln(g.out)
+	fmt.Fprintln(g.out, "// AppendJSON appends the JSON encoding of v to dst and returns the extended buffer,")
+	fmt.Fprintln(g.out, "// or dst unchanged if v cannot be encoded")
+	fmt.Fprintln(g.out, "func (v "+typ+") AppendJSON(dst []byte) []byte {")
+	fmt.Fprintln(g.out, "  w := jwriter.Writer{"+strings.Join(opts, ", ")+"}")
+	fmt.Fprintln(g.out, "  w.Buffer.UseBuf(dst)")
+	fmt.Fprintln(g.out, "  "+fname+"(&w, v)")
+	fmt.Fprintln(g.out, "  if w.Error != nil {")
+	fmt.Fprintln(g.out, "    return dst")
+	fmt.Fprintln(g.out, "  }")
+	fmt.Fprintln(g.out, "  return w.Buffer.BuildBytes()")
+	fmt.Fprintln(g.out, "}")
+}
+
+// genMarshalJSONString generates the MarshalJSONString method of the type t.
+func (g *Generator) genMarshalJSONString(t reflect.Type) {
+	fname := g.getEncoderName(t)
+	typ := g.getType(t)
+
+	opts := g.writerOpts()
+
+	fmt.Fprintln(g.out)
+	fmt.Fprintln(g.out, "// MarshalJSONString returns the JSON encoding of v quoted and escaped as a JSON")
+	fmt.Fprintln(g.out, "// string, for embedding it as a string value into another JSON document")
+	fmt.Fprintln(g.out, "func (v "+typ+") MarshalJSONString() (string, error) {")
+	fmt.Fprintln(g.out, "  w := jwriter.Writer{"+strings.Join(opts, ", ")+"}")
+	fmt.Fprintln(g.out, "  "+fname+"(&w, v)")
+	fmt.Fprintln(g.out, "  data, err := w.BuildBytes()")
+	fmt.Fprintln(g.out, "  if err != nil {")
+	fmt.Fprintln(g.out, "    return \"\", err")
+	fmt.Fprintln(g.out, "  }")
+	fmt.Fprintln(g.out, "  w = jwriter.Writer{"+strings.Join(opts, ", ")+"}")
+	fmt.Fprintln(g.out, "  w.String(string(data))")
+	fmt.Fprintln(g.out, "  return string(w.Buffer.BuildBytes()), nil")
+	fmt.Fprintln(g.out, "}")
+}
+
+// genWriterTo generates the WriteTo method of the type t.
+func (g *Generator) genWriterTo(t reflect.Type) {
+	fname := g.getEncoderName(t)
+	typ := g.getType(t)
+
+	g.useImport("io", "io")
+	opts := g.writerOpts()
+	if g.indentPrefix != "" || g.indent != "" {
+		opts = append(opts, fmt.Sprintf("Prefix: %q", g.indentPrefix), fmt.Sprintf("Indent: %q", g.indent))
+	}
+
+	fmt.Fprintln(g.out)
+	fmt.Fprintln(g.out, "// WriteTo writes the JSON encoding of v to w once it is complete and returns the")
+	fmt.Fprintln(g.out, "// number of bytes written, implementing io.WriterTo")
+	fmt.Fprintln(g.out, "func (v "+typ+") WriteTo(w io.Writer) (int64, error) {")
+	fmt.Fprintln(g.out, "  out := jwriter.Writer{"+strings.Join(opts, ", ")+"}")
+	fmt.Fprintln(g.out, "  "+fname+"(&out, v)")
+	fmt.Fprintln(g.out, "  if out.Error != nil {")
+	fmt.Fprintln(g.out, "    return 0, out.Error")
+	fmt.Fprintln(g.out, "  }")
+	fmt.Fprintln(g.out, "  n, err := out.DumpTo(w)")
+	fmt.Fprintln(g.out, "  return int64(n), err")
+	fmt.Fprintln(g.out, "}")
+	fmt.Fprintln(g.out, "var _ io.WriterTo = (*"+typ+")(nil)")
+}
+
+func (g *Generator) genStructStreamer(t reflect.Type) error {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+	default:
+		if !isPrimitive(t) {
+			return fmt.Errorf("cannot generate encoder/decoder for %v, not a struct/slice/array/map/primitive type", t)
+		}
+	}
+	if !canDeclareMethods(t) {
+		return nil
+	}
+
+	g.useImport("io", "io")
+
+	fname := g.getEncoderName(t)
+	typ := g.getType(t)
+
+	fmt.Fprintln(g.out, "// EncodeJSON writes the JSON encoding of v to w while encoding it")
+	fmt.Fprintln(g.out, "func (v "+typ+") EncodeJSON(w io.Writer) error {")
+	fmt.Fprintln(g.out, "  out := jwriter.NewStreamingWriter(w)")
+	if g.noEscapeHTML {
+		fmt.Fprintln(g.out, "  out.NoEscapeHTML = true")
+	}
+	if name := nanPolicyNames[g.nanPolicy]; name != "" {
+		fmt.Fprintln(g.out, "  out.NaNPolicy = "+name)
+	}
+	if g.floatFormat != 0 {
+		fmt.Fprintf(g.out, "  out.FloatFormat = %q\n", g.floatFormat)
+		fmt.Fprintf(g.out, "  out.FloatPrecision = %d\n", g.floatPrecision)
+	}
+	fmt.Fprintln(g.out, "  "+fname+"(out, v)")
+	fmt.Fprintln(g.out, "  return out.Flush()")
+	fmt.Fprintln(g.out, "}")
+
 	return nil
 }
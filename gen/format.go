@@ -0,0 +1,46 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/scanner"
+)
+
+// snippetLines is the number of lines shown around the line of a syntax error.
+const snippetLines = 3
+
+// SetFormat sets whether Run and RunSplit format the generated code like gofmt
+// does, which is the default. Generated code failing to parse is then reported
+// as an error quoting the offending lines rather than written out.
+func (g *Generator) SetFormat(on bool) {
+	g.format = on
+}
+
+// formatSource formats the generated code src if requested with SetFormat.
+func (g *Generator) formatSource(src []byte) ([]byte, error) {
+	if !g.format {
+		return src, nil
+	}
+	out, err := format.Source(src)
+	if err == nil {
+		return out, nil
+	}
+
+	list, ok := err.(scanner.ErrorList)
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+	return nil, fmt.Errorf("generated code does not parse: %v\n%s", err, sourceSnippet(src, list[0].Pos.Line))
+}
+
+// sourceSnippet returns the lines of src around line, numbered from 1.
+func sourceSnippet(src []byte, line int) []byte {
+	var ret bytes.Buffer
+	for i, l := range bytes.Split(src, []byte("\n")) {
+		if n := i + 1; n >= line-snippetLines && n <= line+snippetLines {
+			fmt.Fprintf(&ret, "%5d\t%s\n", n, l)
+		}
+	}
+	return ret.Bytes()
+}
@@ -0,0 +1,72 @@
+package gen
+
+import (
+	"bytes"
+	"go/format"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type formatItem struct {
+	Name string
+	Tags map[string][]int
+}
+
+type formatDoc struct {
+	Items []formatItem
+	Ptr   *formatItem
+}
+
+func TestRunFormat(t *testing.T) {
+	g := NewGenerator("format.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.Add(formatDoc{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		t.Fatalf("format.Source() error: %v", err)
+	}
+	if !bytes.Equal(formatted, out.Bytes()) {
+		t.Errorf("Run() output changes when formatted again:\n%s", out.Bytes())
+	}
+
+	g = NewGenerator("format.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.SetFormat(false)
+	g.Add(formatDoc{})
+
+	var raw bytes.Buffer
+	if err := g.Run(&raw); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if bytes.Equal(raw.Bytes(), out.Bytes()) {
+		t.Errorf("Run() output is formatted after SetFormat(false)")
+	}
+	if formatted, err := format.Source(raw.Bytes()); err != nil || !bytes.Equal(formatted, out.Bytes()) {
+		t.Errorf("Run() output after SetFormat(false) does not format to the default output: %v", err)
+	}
+}
+
+func TestRunFormatError(t *testing.T) {
+	g := NewGenerator("format.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.RegisterCustomCodec(reflect.TypeOf(formatItem{}), "encodeBroken(", "")
+	g.Add(formatDoc{})
+
+	var out bytes.Buffer
+	err := g.Run(&out)
+	if err == nil {
+		t.Fatalf("Run() with a broken codec reference succeeded:\n%s", out.Bytes())
+	}
+	if msg := err.Error(); !strings.Contains(msg, "does not parse") || !strings.Contains(msg, "encodeBroken((out") {
+		t.Errorf("Run() error = %q; want the offending line quoted in it", msg)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Run() wrote %d bytes of code which does not parse", out.Len())
+	}
+}
@@ -0,0 +1,68 @@
+package gen
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// GenerateFuzzHarness makes the generator add a FuzzT func for the types T with
+// both MarshalJSON and UnmarshalJSON methods, with the signature of go-fuzz
+// targets. It decodes its input into a T, encodes it, and decodes and encodes
+// the output once more, to panic if the two encodings differ. As the encodings
+// are compared byte for byte, map keys have to be sorted, so Run fails for maps
+// and sets with keys of other types than strings, numbers and TextMarshalers.
+func (g *Generator) GenerateFuzzHarness() {
+	g.fuzzHarness = true
+}
+
+// errUnsortedFuzzKeys returns the error for map keys of type key, which are not
+// sorted, with the fuzz harness on.
+func errUnsortedFuzzKeys(key reflect.Type) error {
+	return fmt.Errorf("the fuzz harness compares encodings, which needs sorted map keys, and keys of type %v are not sorted", key)
+}
+
+// fuzzName returns the name of the fuzz func of the type t.
+func (g *Generator) fuzzName(t reflect.Type) string {
+	return "Fuzz" + g.typeName(t)
+}
+
+// genFuzzHarness generates the fuzz func of the type t.
+func (g *Generator) genFuzzHarness(t reflect.Type) {
+	if !canDeclareMethods(t) {
+		return
+	}
+	typ := g.getType(t)
+	bytesPkg := g.pkgAlias("bytes")
+	fmtPkg := g.pkgAlias("fmt")
+
+	marshal, unmarshal := "%v.MarshalJSON()", "%v.UnmarshalJSON(%v)"
+	if g.noStdMarshalers {
+		marshal, unmarshal = "easyjson.Marshal(%v)", "easyjson.Unmarshal(%[2]v, &%[1]v)"
+	}
+
+	fmt.Fprintln(g.out)
+	fmt.Fprintf(g.out, "// %v round-trips data through the JSON encoding of %v, panicking if the\n", g.fuzzName(t), typ)
+	fmt.Fprintln(g.out, "// encoded value does not encode the same once decoded again")
+	fmt.Fprintln(g.out, "func "+g.fuzzName(t)+"(data []byte) int {")
+	fmt.Fprintln(g.out, "  var v1 "+typ)
+	fmt.Fprintf(g.out, "  if err := "+unmarshal+"; err != nil {\n", "v1", "data")
+	fmt.Fprintln(g.out, "    return 0")
+	fmt.Fprintln(g.out, "  }")
+	fmt.Fprintf(g.out, "  out1, err := "+marshal+"\n", "v1")
+	fmt.Fprintln(g.out, "  if err != nil {")
+	fmt.Fprintln(g.out, "    panic(err)")
+	fmt.Fprintln(g.out, "  }")
+	fmt.Fprintln(g.out, "  var v2 "+typ)
+	fmt.Fprintf(g.out, "  if err := "+unmarshal+"; err != nil {\n", "v2", "out1")
+	fmt.Fprintln(g.out, "    panic("+fmtPkg+`.Sprintf("cannot decode %s: %v", out1, err))`)
+	fmt.Fprintln(g.out, "  }")
+	fmt.Fprintf(g.out, "  out2, err := "+marshal+"\n", "v2")
+	fmt.Fprintln(g.out, "  if err != nil {")
+	fmt.Fprintln(g.out, "    panic(err)")
+	fmt.Fprintln(g.out, "  }")
+	fmt.Fprintln(g.out, "  if !"+bytesPkg+".Equal(out1, out2) {")
+	fmt.Fprintln(g.out, "    panic("+fmtPkg+`.Sprintf("round-trip mismatch: %s encodes as %s once decoded", out1, out2))`)
+	fmt.Fprintln(g.out, "  }")
+	fmt.Fprintln(g.out, "  return 1")
+	fmt.Fprintln(g.out, "}")
+}
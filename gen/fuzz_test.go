@@ -0,0 +1,100 @@
+package gen
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+type fuzzRecord struct {
+	Name string
+}
+
+type fuzzMaps struct {
+	Vals  map[int]string
+	Sizes map[uint16][]map[float64]bool
+}
+
+type fuzzKey struct {
+	ID int
+}
+
+func (k fuzzKey) MarshalJSON() ([]byte, error) { return []byte(`"k"`), nil }
+
+type fuzzKeyMap struct {
+	Vals map[fuzzKey]string
+}
+
+func TestGenerateFuzzHarnessNoStdMarshalers(t *testing.T) {
+	g := NewGenerator("record.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.NoStdMarshalers()
+	g.GenerateFuzzHarness()
+	g.Add(fuzzRecord{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	code := out.String()
+	if _, err := parser.ParseFile(token.NewFileSet(), "record_easyjson.go", code, 0); err != nil {
+		t.Fatalf("Run() output does not parse: %v\n%s", err, code)
+	}
+	for _, want := range []string{
+		"func FuzzfuzzRecord(data []byte) int {",
+		"easyjson.Unmarshal(data, &v1)",
+		"easyjson.Marshal(v2)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Run() output does not contain %q:\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerateFuzzHarnessUnsortedMapKeys(t *testing.T) {
+	g := NewGenerator("record.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.SetMapSortKeys(false)
+	g.GenerateFuzzHarness()
+	g.Add(fuzzRecord{})
+
+	if err := g.Run(&bytes.Buffer{}); err == nil {
+		t.Error("Run() succeeded with unsorted map keys; want an error")
+	}
+	if _, err := g.RunSplit(); err == nil {
+		t.Error("RunSplit() succeeded with unsorted map keys; want an error")
+	}
+}
+
+func TestGenerateFuzzHarnessNumberMapKeys(t *testing.T) {
+	g := NewGenerator("maps.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.GenerateFuzzHarness()
+	g.Add(fuzzMaps{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	code := out.String()
+	if n := strings.Count(code, "sort.Slice("); n != 3 {
+		t.Errorf("Run() output sorts the keys of %d maps; want 3:\n%s", n, code)
+	}
+	if strings.Contains(code, "Value := range") {
+		t.Errorf("Run() output ranges over a map unsorted:\n%s", code)
+	}
+}
+
+func TestGenerateFuzzHarnessUnsortableMapKeys(t *testing.T) {
+	g := NewGenerator("maps.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.GenerateFuzzHarness()
+	g.Add(fuzzKeyMap{})
+
+	err := g.Run(&bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "fuzzKey") {
+		t.Errorf("Run() with fuzzKey map keys error = %v; want an error naming the key type", err)
+	}
+}
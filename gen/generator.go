@@ -7,15 +7,25 @@ import (
 	"io"
 	"path"
 	"reflect"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"unicode"
+
+	"github.com/mailru/easyjson/jwriter"
 )
 
 const pkgWriter = "github.com/mailru/easyjson/jwriter"
 const pkgLexer = "github.com/mailru/easyjson/jlexer"
 const pkgEasyJSON = "github.com/mailru/easyjson"
+const pkgJSON = "encoding/json"
+
+// Version is the easyjson version reported in the header of generated files.
+const Version = "v0.7.7"
+
+// defaultTagKey is the struct tag key that field names and options are read from by default.
+const defaultTagKey = "json"
 
 // FieldNamer defines a policy for generating names for struct fields.
 type FieldNamer interface {
@@ -30,28 +40,123 @@ type Generator struct {
 	pkgPath    string
 	buildTags  string
 	hashString string
+	version    string
+
+	// import paths of the jwriter and jlexer packages of the generated code
+	writerPkg string
+	lexerPkg  string
 
 	varCounter int
 
+	tagKey                   string
 	noStdMarshalers          bool
 	omitEmpty                bool
+	omitEmptyIsZero          bool
 	disallowUnknownFields    bool
+	rejectDuplicateKeys      bool
+	caseInsensitive          bool
+	skipUnsupportedFields    bool
 	fieldNamer               FieldNamer
+	typeFieldNamers          map[reflect.Type]FieldNamer
 	simpleBytes              bool
 	skipMemberNameUnescaping bool
+	internMapKeys            bool
+	strictArrays             bool
+	noEscapeHTML             bool
+	nanPolicy                jwriter.NaNPolicy
+	floatFormat              byte
+	floatPrecision           int
+	interfaceNumberMode      InterfaceNumberMode
+	lenientTypes             bool
+	acceptQuotedNumbers      bool
+	json5                    bool
+	noSortMapKeys            bool
+	sizeEstimator            bool
+	reset                    bool
+	fuzzHarness              bool
+	appendJSON               bool
+	marshalJSONString        bool
+	writerTo                 bool
+	pooledWriter             bool
+	durationAsString         bool
+	appendSlices             bool
+	maxDepth                 int
+	maxInputBytes            int
+	boolDecodeMap            map[string]bool
+	boolDecodeMapUsed        bool
+	errorPaths               bool
+	format                   bool
+	indentPrefix             string
+	indent                   string
 
 	// package path to local alias map for tracking imports
 	imports map[string]string
 
-	// types that marshalers were requested for by user
-	marshalers map[reflect.Type]bool
+	// packages the generated code refers to
+	usedImports map[string]bool
+
+	// import aliases pinned by user, used once the packages are imported
+	importAliases map[string]string
+
+	// types that marshalers/unmarshalers were requested for by user
+	marshalers   map[reflect.Type]bool
+	unmarshalers map[reflect.Type]bool
+
+	// types that streaming EncodeJSON methods were requested for by user
+	streamers map[reflect.Type]bool
+
+	// types that streaming DecodeJSON methods were requested for by user
+	decodeStreamers map[reflect.Type]bool
+
+	// concrete types registered for interfaces by user
+	interfaceImpls map[reflect.Type]interfaceImpl
+
+	// concrete types of interface{} values encoded by the generated code,
+	// registered by user
+	marshallerTypes []reflect.Type
+
+	// hand-written encode/decode funcs registered for types by user
+	customCodecs map[reflect.Type]customCodec
+
+	// methods registered for accessing struct fields by user, by field name
+	fieldAccessors map[reflect.Type]map[string]fieldAccessor
+
+	// types requested by name by user, resolved by Run
+	typesByName []typeRef
+
+	// types that encoders/decoders were already generated for
+	encodersSeen map[reflect.Type]bool
+	decodersSeen map[reflect.Type]bool
+
+	// types that encoders/decoders were requested for (e.g. by encoders of
+	// other types), but not generated yet
+	encodersWanted map[reflect.Type]bool
+	decodersWanted map[reflect.Type]bool
+
+	// types that size estimator funcs were generated or requested for
+	sizersSeen   map[reflect.Type]bool
+	sizersWanted map[reflect.Type]bool
 
-	// types that encoders were already generated for
-	typesSeen map[reflect.Type]bool
+	// views requested for struct types by user, and the types they were
+	// generated for
+	views     map[reflect.Type][]view
+	viewsSeen map[reflect.Type]bool
 
-	// types that encoders were requested for (e.g. by encoders of other types)
+	// named slice, array and map types whose code is being generated inline
+	inlining map[reflect.Type]bool
+
+	// named slice, array or map type whose encoder or decoder func is being
+	// generated, until the code for it starts, see reusesFunc
+	funcType reflect.Type
+
+	// queue of types with pending encoder/decoder requests
 	typesUnseen []reflect.Type
 
+	// generated code per type, only tracked by RunSplit
+	typeCodes     map[reflect.Type]*typeCode
+	typeCodeOrder []reflect.Type
+	curTypeCode   *typeCode
+
 	// function name to relevant type maps to track names of de-/encoders in
 	// case of a name clash or unnamed structs
 	functionNames map[string]reflect.Type
@@ -61,15 +166,38 @@ type Generator struct {
 func NewGenerator(filename string) *Generator {
 	ret := &Generator{
 		imports: map[string]string{
-			pkgWriter:       "jwriter",
-			pkgLexer:        "jlexer",
-			pkgEasyJSON:     "easyjson",
-			"encoding/json": "json",
+			pkgWriter:   "jwriter",
+			pkgLexer:    "jlexer",
+			pkgEasyJSON: "easyjson",
+			// Reserved for the generated code, imported only once it is used.
+			pkgJSON: "json",
 		},
-		fieldNamer:    DefaultFieldNamer{},
-		marshalers:    make(map[reflect.Type]bool),
-		typesSeen:     make(map[reflect.Type]bool),
-		functionNames: make(map[string]reflect.Type),
+		usedImports:     make(map[string]bool),
+		importAliases:   make(map[string]string),
+		version:         Version,
+		writerPkg:       pkgWriter,
+		lexerPkg:        pkgLexer,
+		tagKey:          defaultTagKey,
+		format:          true,
+		fieldNamer:      DefaultFieldNamer{},
+		typeFieldNamers: make(map[reflect.Type]FieldNamer),
+		marshalers:      make(map[reflect.Type]bool),
+		unmarshalers:    make(map[reflect.Type]bool),
+		streamers:       make(map[reflect.Type]bool),
+		decodeStreamers: make(map[reflect.Type]bool),
+		interfaceImpls:  make(map[reflect.Type]interfaceImpl),
+		customCodecs:    make(map[reflect.Type]customCodec),
+		fieldAccessors:  make(map[reflect.Type]map[string]fieldAccessor),
+		encodersSeen:    make(map[reflect.Type]bool),
+		decodersSeen:    make(map[reflect.Type]bool),
+		encodersWanted:  make(map[reflect.Type]bool),
+		decodersWanted:  make(map[reflect.Type]bool),
+		sizersSeen:      make(map[reflect.Type]bool),
+		sizersWanted:    make(map[reflect.Type]bool),
+		views:           make(map[reflect.Type][]view),
+		viewsSeen:       make(map[reflect.Type]bool),
+		inlining:        make(map[reflect.Type]bool),
+		functionNames:   make(map[string]reflect.Type),
 	}
 
 	// Use a file-unique prefix on all auxiliary funcs to avoid
@@ -87,24 +215,143 @@ func (g *Generator) SetPkg(name, path string) {
 	g.pkgPath = path
 }
 
+// SetRuntimePackages makes the generated code import the jwriter and jlexer
+// packages from the given paths, like those of a fork of easyjson, rather than
+// from github.com/mailru/easyjson. The packages are still referred to as jwriter
+// and jlexer, so they must keep the API and names of the original ones.
+func (g *Generator) SetRuntimePackages(writerPath, lexerPath string) {
+	delete(g.imports, g.writerPkg)
+	delete(g.imports, g.lexerPkg)
+	g.writerPkg = fixPkgPathVendoring(writerPath)
+	g.lexerPkg = fixPkgPathVendoring(lexerPath)
+	g.imports[g.writerPkg] = "jwriter"
+	g.imports[g.lexerPkg] = "jlexer"
+}
+
+// SetImportAlias makes the generated code import the package pkgPath with the
+// given alias instead of one derived from the package path. Packages without
+// pinned aliases are still aliased automatically, avoiding the pinned ones.
+func (g *Generator) SetImportAlias(pkgPath, alias string) {
+	g.importAliases[fixPkgPathVendoring(pkgPath)] = alias
+}
+
 // SetBuildTags sets build tags for the output file.
 func (g *Generator) SetBuildTags(tags string) {
 	g.buildTags = tags
 }
 
+// SetVersion sets the version reported in the "Code generated" header of the
+// output file, Version by default. An empty version omits it.
+func (g *Generator) SetVersion(version string) {
+	g.version = version
+}
+
 // SetFieldNamer sets field naming strategy.
 func (g *Generator) SetFieldNamer(n FieldNamer) {
 	g.fieldNamer = n
 }
 
+// SetFieldNamerForType sets field naming strategy for fields of the given type,
+// taking precedence over the one set for all types.
+func (g *Generator) SetFieldNamerForType(t reflect.Type, n FieldNamer) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	g.typeFieldNamers[t] = n
+}
+
+// jsonFieldName returns the JSON name of field f of type t.
+func (g *Generator) jsonFieldName(t reflect.Type, f reflect.StructField) string {
+	if n, ok := g.typeFieldNamers[t]; ok {
+		return n.GetJSONFieldName(t, f)
+	}
+	return g.fieldNamer.GetJSONFieldName(t, f)
+}
+
+// interfaceImpl describes the concrete types registered for an interface type.
+type interfaceImpl struct {
+	tagField string
+	tags     []string // sorted keys of impls
+	impls    map[string]reflect.Type
+}
+
+// RegisterInterfaceImpl registers concrete types for values of interface type
+// iface. Decoding such a value reads member tagField of the JSON object first and
+// then decodes the object into the type registered for its value in impls.
+// Encoding uses the encoder of the concrete type, so the concrete types should
+// have a field encoded as tagField for the values to round-trip.
+func (g *Generator) RegisterInterfaceImpl(iface reflect.Type, tagField string, impls map[string]reflect.Type) {
+	tags := make([]string, 0, len(impls))
+	for tag := range impls {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	g.interfaceImpls[iface] = interfaceImpl{
+		tagField: tagField,
+		tags:     tags,
+		impls:    impls,
+	}
+}
+
+// RegisterMarshallerType makes the code generated for interface{} values encode
+// values of type t, or pointers to it, with the encoder generated for t, which
+// also covers types without MarshalEasyJSON methods, such as instantiations of
+// generic types. Values of other types are encoded with jwriter.Interface,
+// through their marshaler methods or encoding/json.
+func (g *Generator) RegisterMarshallerType(t reflect.Type) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for _, t1 := range g.marshallerTypes {
+		if t1 == t {
+			return
+		}
+	}
+	g.marshallerTypes = append(g.marshallerTypes, t)
+}
+
+// SetTagKey sets the struct tag key that field names and options are read from
+// instead of "json". The key is also passed to the built-in field namers.
+func (g *Generator) SetTagKey(key string) {
+	g.tagKey = key
+
+	switch n := g.fieldNamer.(type) {
+	case DefaultFieldNamer:
+		g.fieldNamer = DefaultFieldNamer{TagKey: key}
+	case SnakeCaseFieldNamer:
+		g.fieldNamer = SnakeCaseFieldNamer{TagKey: key}
+	case LowerCamelCaseFieldNamer:
+		g.fieldNamer = LowerCamelCaseFieldNamer{TagKey: key}
+	case KebabCaseFieldNamer:
+		g.fieldNamer = KebabCaseFieldNamer{TagKey: key}
+	case DelimiterFieldNamer:
+		n.TagKey = key
+		g.fieldNamer = n
+	case MappingFieldNamer:
+		g.fieldNamer = n.Fallback
+		if g.fieldNamer == nil {
+			g.fieldNamer = DefaultFieldNamer{}
+		}
+		g.SetTagKey(key)
+		n.Fallback = g.fieldNamer
+		g.fieldNamer = n
+	}
+}
+
 // UseSnakeCase sets snake_case field naming strategy.
 func (g *Generator) UseSnakeCase() {
-	g.fieldNamer = SnakeCaseFieldNamer{}
+	g.fieldNamer = SnakeCaseFieldNamer{TagKey: g.tagKey}
+}
+
+// UseKebabCase sets kebab-case field naming strategy.
+func (g *Generator) UseKebabCase() {
+	g.fieldNamer = KebabCaseFieldNamer{TagKey: g.tagKey}
 }
 
 // UseLowerCamelCase sets lowerCamelCase field naming strategy.
 func (g *Generator) UseLowerCamelCase() {
-	g.fieldNamer = LowerCamelCaseFieldNamer{}
+	g.fieldNamer = LowerCamelCaseFieldNamer{TagKey: g.tagKey}
 }
 
 // NoStdMarshalers instructs not to generate standard MarshalJSON/UnmarshalJSON
@@ -118,26 +365,215 @@ func (g *Generator) DisallowUnknownFields() {
 	g.disallowUnknownFields = true
 }
 
+// RejectDuplicateKeys instructs to return an error if the key of a field appears
+// more than once in an object, instead of keeping the last value as encoding/json
+// does.
+func (g *Generator) RejectDuplicateKeys() {
+	g.rejectDuplicateKeys = true
+}
+
+// SetCaseInsensitive sets whether the generated decoders match object keys to
+// struct fields ignoring case, like encoding/json does, when no field matches
+// the key exactly.
+func (g *Generator) SetCaseInsensitive(caseInsensitive bool) {
+	g.caseInsensitive = caseInsensitive
+}
+
+// SkipUnsupportedFields instructs to leave out struct fields of types that cannot
+// be represented in JSON, like chan, func and complex types, as if they were
+// tagged with `json:"-"`, instead of failing with an error.
+func (g *Generator) SkipUnsupportedFields() {
+	g.skipUnsupportedFields = true
+}
+
 // SkipMemberNameUnescaping instructs to skip member names unescaping to improve performance
 func (g *Generator) SkipMemberNameUnescaping() {
 	g.skipMemberNameUnescaping = true
 }
 
+// InternMapKeys instructs to intern string map keys when decoding, so that
+// repeated keys share the same string.
+func (g *Generator) InternMapKeys() {
+	g.internMapKeys = true
+}
+
+// SetErrorPaths instructs the decoders to track the JSON path of the value being
+// decoded, so that decoding errors report it, e.g. $.items[1].name.
+func (g *Generator) SetErrorPaths() {
+	g.errorPaths = true
+}
+
+// StrictArrays instructs to return an error when decoding a JSON array into a
+// Go array of a different length, instead of dropping extra elements or leaving
+// missing ones zero.
+func (g *Generator) StrictArrays() {
+	g.strictArrays = true
+}
+
 // OmitEmpty triggers `json=",omitempty"` behaviour by default.
 func (g *Generator) OmitEmpty() {
 	g.omitEmpty = true
 }
 
+// OmitEmptyIsZero makes omitempty fields of types with an IsZero() bool method,
+// like time.Time, be omitted when IsZero returns true. Structs are otherwise
+// never omitted, as in encoding/json.
+func (g *Generator) OmitEmptyIsZero() {
+	g.omitEmptyIsZero = true
+}
+
+// SetHTMLEscape sets whether the generated MarshalJSON methods escape '<', '>'
+// and '&' in strings. Escaping is on by default to match encoding/json.
+func (g *Generator) SetHTMLEscape(escape bool) {
+	g.noEscapeHTML = !escape
+}
+
+// SetMapSortKeys sets whether maps with string, number or TextMarshaler keys are
+// encoded with the keys in sorted order, like encoding/json does. Sorting is on
+// by default, turning it off saves the time and the allocation needed to sort
+// the keys.
+func (g *Generator) SetMapSortKeys(sort bool) {
+	g.noSortMapKeys = !sort
+}
+
+// SetFloatNaNPolicy sets how the generated MarshalJSON methods write NaN and
+// infinite float values, by default they fail with an error like encoding/json.
+func (g *Generator) SetFloatNaNPolicy(p jwriter.NaNPolicy) {
+	g.nanPolicy = p
+}
+
+// SetFloatFormat sets the format and precision the generated MarshalJSON methods
+// write floats with, as passed to strconv.AppendFloat, e.g. 'f', 2 for two
+// decimals, by default they are written in the shortest form reading back as the
+// same value, like encoding/json does. The format has to be 'e', 'E', 'f', 'g' or
+// 'G'.
+func (g *Generator) SetFloatFormat(format byte, prec int) {
+	g.floatFormat = format
+	g.floatPrecision = prec
+}
+
+// nanPolicyNames are the expressions for non-default NaN policies in generated code.
+var nanPolicyNames = map[jwriter.NaNPolicy]string{
+	jwriter.NaNNull:   "jwriter.NaNNull",
+	jwriter.NaNString: "jwriter.NaNString",
+}
+
+// InterfaceNumberMode is what JSON numbers decoded into interface{} values become.
+type InterfaceNumberMode int
+
+const (
+	NumberFloat64    InterfaceNumberMode = iota // float64, as encoding/json does by default.
+	NumberJSONNumber                            // json.Number, as encoding/json does with UseNumber.
+)
+
+// SetInterfaceNumberMode sets what the generated UnmarshalJSON methods decode
+// JSON numbers in interface{} values into, by default float64 like encoding/json.
+func (g *Generator) SetInterfaceNumberMode(m InterfaceNumberMode) {
+	g.interfaceNumberMode = m
+}
+
+// SetLenientTypes makes the generated UnmarshalJSON and DecodeJSON methods accept
+// quoted numbers for numeric values and the numbers 0 and 1 for bools, as sent
+// by some APIs, instead of failing on them. For UnmarshalEasyJSON set
+// LenientTypes on the jlexer.Lexer instead.
+func (g *Generator) SetLenientTypes() {
+	g.lenientTypes = true
+}
+
+// AcceptQuotedNumbers makes the generated UnmarshalJSON and DecodeJSON methods
+// accept numbers and bools enclosed in strings, like "5" and "true", for all
+// numeric and bool values, as if they had the 'string' tag option but without
+// requiring the quotes. For UnmarshalEasyJSON set AcceptQuotedNumbers on the
+// jlexer.Lexer instead.
+func (g *Generator) AcceptQuotedNumbers() {
+	g.acceptQuotedNumbers = true
+}
+
+// SetJSON5 makes the generated UnmarshalJSON and DecodeJSON methods accept the
+// unquoted identifier keys and the trailing commas in objects and arrays allowed
+// by JSON5, as in hand-written config files. For UnmarshalEasyJSON set JSON5 on
+// the jlexer.Lexer instead.
+func (g *Generator) SetJSON5() {
+	g.json5 = true
+}
+
+// Indent makes the generated MarshalJSON methods produce output indented like
+// json.MarshalIndent with the given prefix and indent. EncodeJSON output is not
+// indented. It cannot be combined with UsePooledWriter.
+func (g *Generator) Indent(prefix, indent string) {
+	g.indentPrefix = prefix
+	g.indent = indent
+}
+
+// GenerateAppendJSON makes the generator add an AppendJSON method to the types
+// with MarshalJSON methods, which appends the JSON encoding to a given slice like
+// strconv.AppendInt does, so that the caller can reuse its buffer between calls.
+func (g *Generator) GenerateAppendJSON() {
+	g.appendJSON = true
+}
+
+// GenerateMarshalJSONString makes the generator add a MarshalJSONString method to
+// the types with MarshalJSON methods, which returns the encoding quoted as a JSON
+// string, ready to be embedded into another document as a string value.
+func (g *Generator) GenerateMarshalJSONString() {
+	g.marshalJSONString = true
+}
+
+// GenerateWriterTo makes the generator add a WriteTo method to the types with
+// MarshalJSON methods, implementing io.WriterTo: it encodes into a buffer, like
+// MarshalJSON does, and writes the buffer out to the given writer.
+func (g *Generator) GenerateWriterTo() {
+	g.writerTo = true
+}
+
+// UsePooledWriter makes the generated MarshalJSON methods take their writer from
+// the pool of jwriter.GetWriter and put it back once done, even if encoding
+// panics, so that the buffers are reused rather than allocated for every call.
+func (g *Generator) UsePooledWriter() {
+	g.pooledWriter = true
+}
+
+// DurationAsString makes the generated code encode time.Duration values as strings
+// in the format of Duration.String, e.g. "1h30m0s", and decode them with
+// time.ParseDuration, instead of as numbers of nanoseconds.
+func (g *Generator) DurationAsString() {
+	g.durationAsString = true
+}
+
+// AppendSlices makes the generated decoders append the elements of arrays to the
+// slices they decode into, rather than replacing their contents as encoding/json
+// does, so that the values of several documents decoded into the same receiver
+// accumulate. null leaves the slices unchanged. Byte slices, which are decoded
+// from base64 strings, are still replaced.
+func (g *Generator) AppendSlices() {
+	g.appendSlices = true
+}
+
+// SetMaxDepth makes the generated decoders fail with an error on input nesting
+// objects and arrays more than n levels deep, so that input recursing through
+// types like "type T []T" cannot make them overflow the stack. The levels are
+// counted by the decoder funcs of structs and of named slice, array and map
+// types, and by interface{} values. A limit of 0, the default, means none.
+func (g *Generator) SetMaxDepth(n int) {
+	g.maxDepth = n
+}
+
+// SetMaxInputBytes makes the UnmarshalJSON and DecodeJSON methods fail with an
+// error on input longer than n bytes, before decoding it for UnmarshalJSON and
+// without reading much further than n bytes for DecodeJSON, so that a huge
+// string value or document cannot make them run out of memory. A limit of 0,
+// the default, means none.
+func (g *Generator) SetMaxInputBytes(n int) {
+	g.maxInputBytes = n
+}
+
 // SimpleBytes triggers generate output bytes as slice byte
 func (g *Generator) SimpleBytes() {
 	g.simpleBytes = true
 }
 
-// addTypes requests to generate encoding/decoding funcs for the given type.
+// addType queues the given type for generation of the requested funcs.
 func (g *Generator) addType(t reflect.Type) {
-	if g.typesSeen[t] {
-		return
-	}
 	for _, t1 := range g.typesUnseen {
 		if t1 == t {
 			return
@@ -146,85 +582,255 @@ func (g *Generator) addType(t reflect.Type) {
 	g.typesUnseen = append(g.typesUnseen, t)
 }
 
-// Add requests to generate marshaler/unmarshalers and encoding/decoding
-// funcs for the type of given object.
-func (g *Generator) Add(obj interface{}) {
+// addEncoderType requests to generate encoding funcs for the given type.
+func (g *Generator) addEncoderType(t reflect.Type) {
+	if g.curTypeCode != nil {
+		g.curTypeCode.deps[t] = true
+	}
+	if g.encodersSeen[t] || g.encodersWanted[t] {
+		return
+	}
+	g.encodersWanted[t] = true
+	g.addType(t)
+}
+
+// addDecoderType requests to generate decoding funcs for the given type.
+func (g *Generator) addDecoderType(t reflect.Type) {
+	if g.curTypeCode != nil {
+		g.curTypeCode.deps[t] = true
+	}
+	if g.decodersSeen[t] || g.decodersWanted[t] {
+		return
+	}
+	g.decodersWanted[t] = true
+	g.addType(t)
+}
+
+// objType returns the type of given object, dereferencing a pointer.
+func objType(obj interface{}) reflect.Type {
 	t := reflect.TypeOf(obj)
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
-	g.addType(t)
+	return t
+}
+
+// Add requests to generate marshaler/unmarshalers and encoding/decoding
+// funcs for the type of given object.
+func (g *Generator) Add(obj interface{}) {
+	g.AddEncoderOnly(obj)
+	g.AddDecoderOnly(obj)
+}
+
+// AddEncoderOnly requests to generate only marshalers and encoding funcs for
+// the type of given object. Types it depends on get only encoding funcs as
+// well, unless they are needed for decoding by other types.
+func (g *Generator) AddEncoderOnly(obj interface{}) {
+	t := objType(obj)
+	g.addEncoderType(t)
 	g.marshalers[t] = true
 }
 
-// printHeader prints package declaration and imports.
-func (g *Generator) printHeader() {
+// AddDecoderOnly requests to generate only unmarshalers and decoding funcs for
+// the type of given object. Types it depends on get only decoding funcs as
+// well, unless they are needed for encoding by other types.
+func (g *Generator) AddDecoderOnly(obj interface{}) {
+	t := objType(obj)
+	g.addDecoderType(t)
+	g.unmarshalers[t] = true
+}
+
+// AddStreaming requests to generate an EncodeJSON method that writes the type of
+// given object to an io.Writer while encoding it, along with the encoding funcs.
+func (g *Generator) AddStreaming(obj interface{}) {
+	t := objType(obj)
+	g.addEncoderType(t)
+	g.streamers[t] = true
+}
+
+// AddStreamingDecoder requests to generate a DecodeJSON method that reads the
+// type of given object from an io.Reader while decoding it, along with the
+// decoding funcs.
+func (g *Generator) AddStreamingDecoder(obj interface{}) {
+	t := objType(obj)
+	g.addDecoderType(t)
+	g.decodeStreamers[t] = true
+}
+
+// printHeader prints package declaration and given imports.
+func (g *Generator) printHeader(out io.Writer, imports map[string]string) {
 	if g.buildTags != "" {
-		fmt.Println("// +build ", g.buildTags)
-		fmt.Println()
+		fmt.Fprintln(out, "// +build ", g.buildTags)
+		fmt.Fprintln(out)
 	}
-	fmt.Println("// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.")
-	fmt.Println()
-	fmt.Println("package ", g.pkgName)
-	fmt.Println()
+	if g.version != "" {
+		fmt.Fprintf(out, "// Code generated by easyjson %s. DO NOT EDIT.\n", g.version)
+	} else {
+		fmt.Fprintln(out, "// Code generated by easyjson. DO NOT EDIT.")
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "package ", g.pkgName)
+	fmt.Fprintln(out)
 
-	byAlias := make(map[string]string, len(g.imports))
-	aliases := make([]string, 0, len(g.imports))
+	byAlias := make(map[string]string, len(imports))
+	aliases := make([]string, 0, len(imports))
 
-	for path, alias := range g.imports {
+	for path, alias := range imports {
 		aliases = append(aliases, alias)
 		byAlias[alias] = path
 	}
 
+	// Imports are emitted in alias order so that the output does not depend
+	// on map iteration order.
 	sort.Strings(aliases)
-	fmt.Println("import (")
+	fmt.Fprintln(out, "import (")
 	for _, alias := range aliases {
-		fmt.Printf("  %s %q\n", alias, byAlias[alias])
+		fmt.Fprintf(out, "  %s %q\n", alias, byAlias[alias])
 	}
 
-	fmt.Println(")")
-	fmt.Println("")
-	fmt.Println("// suppress unused package warning")
-	fmt.Println("var (")
-	fmt.Println("   _ *json.RawMessage")
-	fmt.Println("   _ *jlexer.Lexer")
-	fmt.Println("   _ *jwriter.Writer")
-	fmt.Println("   _ easyjson.Marshaler")
-	fmt.Println(")")
+	fmt.Fprintln(out, ")")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "// suppress unused package warning")
+	fmt.Fprintln(out, "var (")
+	if _, ok := imports[pkgJSON]; ok {
+		fmt.Fprintln(out, "   _ *json.RawMessage")
+	}
+	fmt.Fprintln(out, "   _ *jlexer.Lexer")
+	fmt.Fprintln(out, "   _ *jwriter.Writer")
+	fmt.Fprintln(out, "   _ easyjson.Marshaler")
+	fmt.Fprintln(out, ")")
 
-	fmt.Println()
+	fmt.Fprintln(out)
 }
 
 // Run runs the generator and outputs generated code to out.
 func (g *Generator) Run(out io.Writer) error {
+	if err := g.prepare(); err != nil {
+		return err
+	}
+
 	g.out = &bytes.Buffer{}
+	if err := g.genTypes(); err != nil {
+		return err
+	}
+	if g.boolDecodeMapUsed {
+		g.genBoolDecodeMap(g.out)
+	}
+
+	imports := make(map[string]string)
+	for _, pkg := range []string{g.writerPkg, g.lexerPkg, pkgEasyJSON} {
+		imports[pkg] = g.imports[pkg]
+	}
+	for pkg := range g.usedImports {
+		imports[pkg] = g.imports[pkg]
+	}
+
+	var src bytes.Buffer
+	g.printHeader(&src, imports)
+	src.Write(g.out.Bytes())
+
+	data, err := g.formatSource(src.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
 
+// prepare readies the generator for generating the code with Run or RunSplit.
+func (g *Generator) prepare() error {
+	if g.floatFormat != 0 && !strings.ContainsRune("eEfgG", rune(g.floatFormat)) {
+		return fmt.Errorf("invalid float format %q: must be 'e', 'E', 'f', 'g' or 'G'", g.floatFormat)
+	}
+	if g.fuzzHarness && g.noSortMapKeys {
+		return fmt.Errorf("the fuzz harness compares encodings, which needs sorted map keys")
+	}
+	if g.pooledWriter && (g.indentPrefix != "" || g.indent != "") {
+		return fmt.Errorf("indented output is not supported by pooled writers")
+	}
+	return g.resolveTypesByName()
+}
+
+// genTypes generates the code for all requested types and the types they depend on.
+func (g *Generator) genTypes() error {
 	for len(g.typesUnseen) > 0 {
 		t := g.typesUnseen[len(g.typesUnseen)-1]
 		g.typesUnseen = g.typesUnseen[:len(g.typesUnseen)-1]
-		g.typesSeen[t] = true
 
-		if err := g.genDecoder(t); err != nil {
-			return err
-		}
-		if err := g.genEncoder(t); err != nil {
-			return err
+		if g.typeCodes != nil {
+			g.switchTypeCode(t)
 		}
 
-		if !g.marshalers[t] {
-			continue
+		genDecoder, genEncoder := g.decodersWanted[t], g.encodersWanted[t]
+		delete(g.decodersWanted, t)
+		delete(g.encodersWanted, t)
+		g.decodersSeen[t] = g.decodersSeen[t] || genDecoder
+		g.encodersSeen[t] = g.encodersSeen[t] || genEncoder
+
+		if genDecoder {
+			if err := g.genDecoder(t); err != nil {
+				return err
+			}
+		}
+		if genEncoder {
+			if err := g.genEncoder(t); err != nil {
+				return err
+			}
+		}
+		if g.sizersWanted[t] {
+			delete(g.sizersWanted, t)
+			g.sizersSeen[t] = true
+			if err := g.genSizer(t); err != nil {
+				return err
+			}
 		}
 
-		if err := g.genStructMarshaler(t); err != nil {
-			return err
+		if genEncoder && g.marshalers[t] {
+			if err := g.genStructMarshaler(t); err != nil {
+				return err
+			}
+		}
+		if genDecoder && g.unmarshalers[t] {
+			if err := g.genStructUnmarshaler(t); err != nil {
+				return err
+			}
 		}
-		if err := g.genStructUnmarshaler(t); err != nil {
-			return err
+		if genEncoder && genDecoder && g.fuzzHarness && g.marshalers[t] && g.unmarshalers[t] {
+			g.genFuzzHarness(t)
+		}
+		if genEncoder && g.streamers[t] {
+			if err := g.genStructStreamer(t); err != nil {
+				return err
+			}
+		}
+		if genDecoder && g.decodeStreamers[t] {
+			if err := g.genStructDecodeStreamer(t); err != nil {
+				return err
+			}
+		}
+		if len(g.views[t]) > 0 && !g.viewsSeen[t] {
+			if err := g.genViews(t); err != nil {
+				return err
+			}
 		}
 	}
-	g.printHeader()
-	_, err := out.Write(g.out.Bytes())
-	return err
+	return g.checkImportAliases()
+}
+
+// checkImportAliases returns an error if a pinned import alias ended up being
+// used for several packages.
+func (g *Generator) checkImportAliases() error {
+	paths := make(map[string]string, len(g.imports))
+	for pkgPath, alias := range g.imports {
+		if other, ok := paths[alias]; ok {
+			if other > pkgPath {
+				other, pkgPath = pkgPath, other
+			}
+			return fmt.Errorf("import alias %v is used for both %v and %v", alias, other, pkgPath)
+		}
+		paths[alias] = pkgPath
+	}
+	return nil
 }
 
 // fixes vendored paths
@@ -250,12 +856,27 @@ func fixAliasName(alias string) string {
 	return alias
 }
 
+// useImport adds an import of the package with a fixed alias that the
+// generated code refers to.
+func (g *Generator) useImport(pkgPath, alias string) {
+	g.imports[pkgPath] = alias
+	g.pkgAlias(pkgPath)
+}
+
 // pkgAlias creates and returns and import alias for a given package.
 func (g *Generator) pkgAlias(pkgPath string) string {
 	pkgPath = fixPkgPathVendoring(pkgPath)
+	g.usedImports[pkgPath] = true
+	if g.curTypeCode != nil {
+		g.curTypeCode.imports[pkgPath] = true
+	}
 	if alias := g.imports[pkgPath]; alias != "" {
 		return alias
 	}
+	if alias := g.importAliases[pkgPath]; alias != "" {
+		g.imports[pkgPath] = alias
+		return alias
+	}
 
 	for i := 0; ; i++ {
 		alias := fixAliasName(path.Base(pkgPath))
@@ -270,6 +891,12 @@ func (g *Generator) pkgAlias(pkgPath string) string {
 				break
 			}
 		}
+		for _, v := range g.importAliases {
+			if v == alias {
+				exists = true
+				break
+			}
+		}
 
 		if !exists {
 			g.imports[pkgPath] = alias
@@ -317,10 +944,87 @@ func (g *Generator) getType(t reflect.Type) string {
 			return strings.Join([]string{"struct { ", strings.Join(lines, "; "), " }"}, "")
 		}
 		return t.String()
-	} else if t.PkgPath() == g.pkgPath {
-		return t.Name()
+	} else if g.isOwnPkg(t.PkgPath()) {
+		return g.typeName(t)
+	}
+	return g.pkgAlias(t.PkgPath()) + "." + g.typeName(t)
+}
+
+// canDeclareMethods returns whether methods can be generated for the type t. Go
+// does not allow declaring them on unnamed types, nor on a single instantiation
+// of a generic type, as methods are declared for all of its type arguments.
+func canDeclareMethods(t reflect.Type) bool {
+	return t.Name() != "" && !strings.Contains(t.Name(), "[")
+}
+
+// qualifiedNameRe matches the package path qualified type names reflect puts
+// into the names of generic type instantiations, like "a/b.T" in "Box[a/b.T]".
+var qualifiedNameRe = regexp.MustCompile(`[\w./~-]+\.\w+`)
+
+// typeName returns the name of the named type t without the package, with the
+// types in the type arguments of an instantiated generic type referred to as in
+// the generated code, e.g. "Box[jwriter.Writer]" for the reflect name
+// "Box[github.com/mailru/easyjson/jwriter.Writer]".
+func (g *Generator) typeName(t reflect.Type) string {
+	name := t.Name()
+	i := strings.IndexByte(name, '[')
+	if i == -1 {
+		return name
 	}
-	return g.pkgAlias(t.PkgPath()) + "." + t.Name()
+	return name[:i] + qualifiedNameRe.ReplaceAllStringFunc(name[i:], func(s string) string {
+		j := strings.LastIndexByte(s, '.')
+		if g.isOwnPkg(s[:j]) {
+			return s[j+1:]
+		}
+		return g.pkgAlias(s[:j]) + s[j:]
+	})
+}
+
+// isOwnPkg returns whether pkgPath is the package the code is generated for,
+// whose types are referred to without importing it.
+func (g *Generator) isOwnPkg(pkgPath string) bool {
+	return fixPkgPathVendoring(pkgPath) == fixPkgPathVendoring(g.pkgPath)
+}
+
+// enterInline marks the code for the type t as being generated inline, and
+// returns false if a named slice, array or map type is recurring in its own
+// code, like "type T []T" does, which must call the func generated for it
+// instead. The returned func unmarks t.
+func (g *Generator) enterInline(t reflect.Type) (func(), bool) {
+	if t.Name() == "" {
+		return func() {}, true
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+	default:
+		return func() {}, true
+	}
+	if g.inlining[t] {
+		return nil, false
+	}
+	g.inlining[t] = true
+	return func() { delete(g.inlining, t) }, true
+}
+
+// reusesFunc returns whether the code for a value of type t calls the encoder or
+// decoder func generated for it rather than being inlined, which is the case for
+// named slice, array and map types anywhere but in their own func, so that the
+// code for them is generated once however many fields and elements have them.
+// Fields with tags changing how the values nested in them are handled still get
+// the code inline.
+func (g *Generator) reusesFunc(t reflect.Type, tags fieldTags) bool {
+	if g.funcType == t {
+		g.funcType = nil
+		return false
+	}
+	if t.Name() == "" || tags.asString || tags.intern || tags.noCopy || tags.set || tags.timeFormat != "" {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
 }
 
 // escape a struct field tag string back to source code
@@ -344,7 +1048,11 @@ func (g *Generator) uniqueVarName() string {
 func (g *Generator) safeName(t reflect.Type) string {
 	name := t.PkgPath()
 	if t.Name() == "" {
-		name += "anonymous"
+		// Unnamed types are told apart by a hash of their definition, so that
+		// the names do not depend on the order the types are generated in.
+		hash := fnv.New32()
+		hash.Write([]byte(t.String()))
+		name += fmt.Sprintf(".anonymous.%x", hash.Sum32())
 	} else {
 		name += "." + t.Name()
 	}
@@ -359,6 +1067,9 @@ func (g *Generator) safeName(t reflect.Type) string {
 			part = []rune{}
 		}
 	}
+	if len(part) > 0 {
+		parts = append(parts, string(part))
+	}
 	return joinFunctionNameParts(false, parts...)
 }
 
@@ -394,11 +1105,23 @@ func (g *Generator) functionName(prefix string, t reflect.Type) string {
 	}
 }
 
+// tagFieldName returns the name part of the struct tag with the given key, using
+// "json" if the key is empty.
+func tagFieldName(f reflect.StructField, key string) string {
+	if key == "" {
+		key = defaultTagKey
+	}
+	return strings.Split(f.Tag.Get(key), ",")[0]
+}
+
 // DefaultFieldsNamer implements trivial naming policy equivalent to encoding/json.
-type DefaultFieldNamer struct{}
+type DefaultFieldNamer struct {
+	// TagKey is the struct tag key to read names from, "json" if empty.
+	TagKey string
+}
 
-func (DefaultFieldNamer) GetJSONFieldName(t reflect.Type, f reflect.StructField) string {
-	jsonName := strings.Split(f.Tag.Get("json"), ",")[0]
+func (n DefaultFieldNamer) GetJSONFieldName(t reflect.Type, f reflect.StructField) string {
+	jsonName := tagFieldName(f, n.TagKey)
 	if jsonName != "" {
 		return jsonName
 	}
@@ -406,8 +1129,13 @@ func (DefaultFieldNamer) GetJSONFieldName(t reflect.Type, f reflect.StructField)
 	return f.Name
 }
 
-// LowerCamelCaseFieldNamer
-type LowerCamelCaseFieldNamer struct{}
+// LowerCamelCaseFieldNamer implements CamelCase to lowerCamelCase conversion for
+// fields names, lowering the leading run of capitals except for the one starting
+// the next word, so that ID becomes "id" and HTTPServer becomes "httpServer".
+type LowerCamelCaseFieldNamer struct {
+	// TagKey is the struct tag key to read names from, "json" if empty.
+	TagKey string
+}
 
 func isLower(b byte) bool {
 	return b <= 122 && b >= 97
@@ -463,8 +1191,8 @@ func lowerFirst(s string) string {
 	return str
 }
 
-func (LowerCamelCaseFieldNamer) GetJSONFieldName(t reflect.Type, f reflect.StructField) string {
-	jsonName := strings.Split(f.Tag.Get("json"), ",")[0]
+func (n LowerCamelCaseFieldNamer) GetJSONFieldName(t reflect.Type, f reflect.StructField) string {
+	jsonName := tagFieldName(f, n.TagKey)
 	if jsonName != "" {
 		return jsonName
 	}
@@ -473,11 +1201,26 @@ func (LowerCamelCaseFieldNamer) GetJSONFieldName(t reflect.Type, f reflect.Struc
 }
 
 // SnakeCaseFieldNamer implements CamelCase to snake_case conversion for fields names.
-type SnakeCaseFieldNamer struct{}
+type SnakeCaseFieldNamer struct {
+	// TagKey is the struct tag key to read names from, "json" if empty.
+	TagKey string
+}
 
 func camelToSnake(name string) string {
+	return camelToDelimited(name, '_', false)
+}
+
+// camelToDelimited converts a CamelCase name to words separated by delim, in
+// upper case if upper is set and in lower case otherwise. Underscores in the name
+// are replaced with delim.
+func camelToDelimited(name string, delim rune, upper bool) string {
 	var ret bytes.Buffer
 
+	toCase := unicode.ToLower
+	if upper {
+		toCase = unicode.ToUpper
+	}
+
 	multipleUpper := false
 	var lastUpper rune
 	var beforeUpper rune
@@ -495,9 +1238,9 @@ func camelToSnake(name string) string {
 			lastInRow := !isUpper
 
 			if ret.Len() > 0 && (firstInRow || lastInRow) && beforeUpper != '_' {
-				ret.WriteByte('_')
+				ret.WriteRune(delim)
 			}
-			ret.WriteRune(unicode.ToLower(lastUpper))
+			ret.WriteRune(toCase(lastUpper))
 		}
 
 		// Buffer uppercase char, do not output it yet as a delimiter may be required if the
@@ -508,20 +1251,24 @@ func camelToSnake(name string) string {
 			continue
 		}
 
-		ret.WriteRune(c)
+		if c == '_' {
+			ret.WriteRune(delim)
+		} else {
+			ret.WriteRune(toCase(c))
+		}
 		lastUpper = 0
 		beforeUpper = c
 		multipleUpper = false
 	}
 
 	if lastUpper != 0 {
-		ret.WriteRune(unicode.ToLower(lastUpper))
+		ret.WriteRune(toCase(lastUpper))
 	}
 	return string(ret.Bytes())
 }
 
-func (SnakeCaseFieldNamer) GetJSONFieldName(t reflect.Type, f reflect.StructField) string {
-	jsonName := strings.Split(f.Tag.Get("json"), ",")[0]
+func (n SnakeCaseFieldNamer) GetJSONFieldName(t reflect.Type, f reflect.StructField) string {
+	jsonName := tagFieldName(f, n.TagKey)
 	if jsonName != "" {
 		return jsonName
 	}
@@ -529,6 +1276,53 @@ func (SnakeCaseFieldNamer) GetJSONFieldName(t reflect.Type, f reflect.StructFiel
 	return camelToSnake(f.Name)
 }
 
+// KebabCaseFieldNamer implements CamelCase to kebab-case conversion for fields names.
+type KebabCaseFieldNamer struct {
+	// TagKey is the struct tag key to read names from, "json" if empty.
+	TagKey string
+}
+
+func camelToKebab(name string) string {
+	return camelToDelimited(name, '-', false)
+}
+
+func (n KebabCaseFieldNamer) GetJSONFieldName(t reflect.Type, f reflect.StructField) string {
+	jsonName := tagFieldName(f, n.TagKey)
+	if jsonName != "" {
+		return jsonName
+	}
+
+	return camelToKebab(f.Name)
+}
+
+// DelimiterFieldNamer implements CamelCase conversion for fields names like
+// SnakeCaseFieldNamer does, but with a custom delimiter and letter case, e.g. to
+// SCREAMING_SNAKE_CASE or dot.case.
+type DelimiterFieldNamer struct {
+	// TagKey is the struct tag key to read names from, "json" if empty.
+	TagKey string
+
+	delim rune
+	upper bool
+}
+
+// NewDelimiterFieldNamer returns a field namer separating the words of field
+// names with delim, in upper case if upper is set and in lower case otherwise:
+// HTTPServerName becomes "HTTP_SERVER_NAME" with '_' and true, and
+// "http.server.name" with '.' and false.
+func NewDelimiterFieldNamer(delim rune, upper bool) DelimiterFieldNamer {
+	return DelimiterFieldNamer{delim: delim, upper: upper}
+}
+
+func (n DelimiterFieldNamer) GetJSONFieldName(t reflect.Type, f reflect.StructField) string {
+	jsonName := tagFieldName(f, n.TagKey)
+	if jsonName != "" {
+		return jsonName
+	}
+
+	return camelToDelimited(f.Name, n.delim, n.upper)
+}
+
 func joinFunctionNameParts(keepFirst bool, parts ...string) string {
 	buf := bytes.NewBufferString("")
 	for i, part := range parts {
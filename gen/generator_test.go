@@ -1,7 +1,22 @@
 package gen
 
 import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"html/template"
+	"net"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+	"unsafe"
 )
 
 func TestCamelToSnake(t *testing.T) {
@@ -28,6 +43,59 @@ func TestCamelToSnake(t *testing.T) {
 	}
 }
 
+func TestCamelToKebab(t *testing.T) {
+	for i, test := range []struct {
+		In, Out string
+	}{
+		{"", ""},
+		{"A", "a"},
+		{"ContentType", "content-type"},
+		{"HTTPServerName", "http-server-name"},
+		{"HTTP2Server", "http2-server"},
+		{"Some_Mixed_Case", "some-mixed-case"},
+	} {
+		got := camelToKebab(test.In)
+		if got != test.Out {
+			t.Errorf("[%d] camelToKebab(%s) = %s; want %s", i, test.In, got, test.Out)
+		}
+	}
+}
+
+func TestDelimiterFieldNamer(t *testing.T) {
+	for i, test := range []struct {
+		delim rune
+		upper bool
+		In    string
+		Out   string
+	}{
+		{'_', true, "HTTPServerName", "HTTP_SERVER_NAME"},
+		{'_', true, "userID", "USER_ID"},
+		{'_', true, "Some_Mixed_Case", "SOME_MIXED_CASE"},
+		{'.', false, "HTTPServerName", "http.server.name"},
+		{'.', false, "HTTP2Server", "http2.server"},
+		{'.', false, "Some_Mixed_Case", "some.mixed.case"},
+		{'_', false, "HTTPServerName", "http_server_name"},
+	} {
+		n := NewDelimiterFieldNamer(test.delim, test.upper)
+		f := reflect.StructField{Name: test.In}
+		if got := n.GetJSONFieldName(nil, f); got != test.Out {
+			t.Errorf("[%d] GetJSONFieldName(%s) = %s; want %s", i, test.In, got, test.Out)
+		}
+	}
+
+	g := NewGenerator("x.go")
+	g.SetFieldNamer(NewDelimiterFieldNamer('.', true))
+	g.SetTagKey("yaml")
+	f := reflect.StructField{Name: "ServerName", Tag: `yaml:"name" json:"other"`}
+	if got := g.fieldNamer.GetJSONFieldName(nil, f); got != "name" {
+		t.Errorf("GetJSONFieldName() after SetTagKey = %s; want name", got)
+	}
+	f.Tag = ""
+	if got := g.fieldNamer.GetJSONFieldName(nil, f); got != "SERVER.NAME" {
+		t.Errorf("GetJSONFieldName() after SetTagKey = %s; want SERVER.NAME", got)
+	}
+}
+
 func TestCamelToLowerCamel(t *testing.T) {
 	for i, test := range []struct {
 		In, Out string
@@ -41,6 +109,15 @@ func TestCamelToLowerCamel(t *testing.T) {
 		{"WriteJSON", "writeJSON"},
 		{"HTTP2Server", "http2Server"},
 
+		{"ID", "id"},
+		{"URL", "url"},
+		{"UserID", "userID"},
+		{"HTTPServer", "httpServer"},
+		{"XMLHttpRequest", "xmlHttpRequest"},
+		{"ID2", "id2"},
+		{"A1B", "a1B"},
+		{"UserName", "userName"},
+
 		{"JSONHTTPRPCServer", "jsonhttprpcServer"}, // nothing can be done here without a dictionary
 	} {
 		got := lowerFirst(test.In)
@@ -50,6 +127,36 @@ func TestCamelToLowerCamel(t *testing.T) {
 	}
 }
 
+func TestLowerCamelCaseFieldNamer(t *testing.T) {
+	type namedFields struct {
+		UserName string
+		ID       int
+		URLPath  string `json:"URLPath"`
+		Tagged   int    `json:"tagged_value,omitempty"`
+		APIKey   string `api:"key"`
+	}
+	typ := reflect.TypeOf(namedFields{})
+
+	for i, test := range []struct {
+		namer LowerCamelCaseFieldNamer
+		field string
+		want  string
+	}{
+		{field: "UserName", want: "userName"},
+		{field: "ID", want: "id"},
+		{field: "URLPath", want: "URLPath"},
+		{field: "Tagged", want: "tagged_value"},
+		{field: "APIKey", want: "apiKey"},
+		{namer: LowerCamelCaseFieldNamer{TagKey: "api"}, field: "APIKey", want: "key"},
+		{namer: LowerCamelCaseFieldNamer{TagKey: "api"}, field: "URLPath", want: "urlPath"},
+	} {
+		f, _ := typ.FieldByName(test.field)
+		if got := test.namer.GetJSONFieldName(typ, f); got != test.want {
+			t.Errorf("[%d] GetJSONFieldName(%s) = %s; want %s", i, test.field, got, test.want)
+		}
+	}
+}
+
 func TestJoinFunctionNameParts(t *testing.T) {
 	for i, test := range []struct {
 		keepFirst bool
@@ -85,3 +192,1156 @@ func TestFixVendorPath(t *testing.T) {
 	}
 
 }
+
+type importsStruct struct {
+	Time time.Time
+	IP   net.IP
+	HTML template.HTML
+}
+
+func TestRunDeterministic(t *testing.T) {
+	run := func() []byte {
+		g := NewGenerator("imports.go")
+		g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+		g.Add(importsStruct{})
+
+		var out bytes.Buffer
+		if err := g.Run(&out); err != nil {
+			t.Fatalf("Run() error: %v", err)
+		}
+		return out.Bytes()
+	}
+
+	want := run()
+	for i := 0; i < 10; i++ {
+		if got := run(); !bytes.Equal(got, want) {
+			t.Fatalf("[%d] Run() output differs between runs:\n%s\n\nwant:\n%s", i, got, want)
+		}
+	}
+}
+
+func TestStringTagUnsupported(t *testing.T) {
+	for i, obj := range []interface{}{
+		struct {
+			Slice []int `json:",string"`
+		}{},
+		struct {
+			Struct struct{ A int } `json:",string"`
+		}{},
+	} {
+		g := NewGenerator("string_tag.go")
+		g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+		g.Add(obj)
+
+		if err := g.Run(&bytes.Buffer{}); err == nil {
+			t.Errorf("[%d] Run() for %T ok; want error", i, obj)
+		}
+	}
+}
+
+func TestMapKeyUnsupported(t *testing.T) {
+	for i, obj := range []interface{}{
+		map[bool]string{},
+		map[struct{ A int }]string{},
+	} {
+		g := NewGenerator("map_key.go")
+		g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+		g.Add(obj)
+
+		if err := g.Run(&bytes.Buffer{}); err == nil {
+			t.Errorf("[%d] Run() for %T ok; want error", i, obj)
+		}
+	}
+}
+
+type unsupportedChan struct {
+	A  int
+	Ch chan int
+}
+
+type unsupportedFunc struct {
+	Funcs []func()
+}
+
+type unsupportedComplex struct {
+	C map[string]*complex128
+}
+
+type unsupportedPointer struct {
+	P unsafe.Pointer
+}
+
+type unsupportedOmitted struct {
+	A  int
+	Ch chan int `json:"-"`
+}
+
+func TestUnsupportedFieldKinds(t *testing.T) {
+	for i, test := range []struct {
+		obj     interface{}
+		wantErr string
+	}{
+		{obj: unsupportedChan{}, wantErr: "gen.unsupportedChan: field Ch of type chan int has unsupported kind chan"},
+		{obj: unsupportedFunc{}, wantErr: "gen.unsupportedFunc: field Funcs of type []func() has unsupported kind func"},
+		{obj: unsupportedComplex{}, wantErr: "gen.unsupportedComplex: field C of type map[string]*complex128 has unsupported kind complex128"},
+		{obj: unsupportedPointer{}, wantErr: "gen.unsupportedPointer: field P of type unsafe.Pointer has unsupported kind unsafe.Pointer"},
+		{obj: unsupportedOmitted{}},
+	} {
+		for _, skip := range []bool{false, true} {
+			g := NewGenerator("unsupported.go")
+			g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+			if skip {
+				g.SkipUnsupportedFields()
+			}
+			g.Add(test.obj)
+
+			var out bytes.Buffer
+			err := g.Run(&out)
+			switch {
+			case skip || test.wantErr == "":
+				if err != nil {
+					t.Errorf("[%d] Run() for %T with skip %v error: %v", i, test.obj, skip, err)
+				}
+			case err == nil:
+				t.Errorf("[%d] Run() for %T ok; want error %q", i, test.obj, test.wantErr)
+			case !strings.Contains(err.Error(), test.wantErr):
+				t.Errorf("[%d] Run() for %T error %q; want it to contain %q", i, test.obj, err, test.wantErr)
+			}
+			if err == nil && strings.Contains(out.String(), `case "Ch"`) {
+				t.Errorf("[%d] Run() for %T decodes the skipped field", i, test.obj)
+			}
+		}
+	}
+}
+
+type importAliasStruct struct {
+	IP    net.IP
+	IPs   []net.IP
+	Time  time.Time
+	Times map[string]*time.Time
+}
+
+func TestSetImportAlias(t *testing.T) {
+	g := NewGenerator("import_alias.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.SetImportAlias("net", "stdnet")
+	// Packages not pinned avoid the pinned aliases.
+	g.SetImportAlias("html/template", "time")
+	g.Add(importAliasStruct{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	code := out.String()
+	for _, want := range []string{
+		`stdnet "net"`,
+		"[]stdnet.IP",
+		`time1 "time"`,
+		"map[string]*time1.Time",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Run() output does not contain %q", want)
+		}
+	}
+	if strings.Contains(code, `"html/template"`) {
+		t.Errorf("Run() output imports a package that is not used")
+	}
+
+	g = NewGenerator("import_alias.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.SetImportAlias("net", "json")
+	g.Add(importAliasStruct{})
+	if err := g.Run(&bytes.Buffer{}); err == nil {
+		t.Errorf("Run() with colliding import aliases ok; want error")
+	}
+}
+
+func TestSetRuntimePackages(t *testing.T) {
+	g := NewGenerator("runtime.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.SetRuntimePackages("example.com/fork/jwriter", "example.com/fork/jlexer")
+	g.Add(importAliasStruct{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	code := out.String()
+	f, err := parser.ParseFile(token.NewFileSet(), "runtime_easyjson.go", code, parser.ImportsOnly)
+	if err != nil {
+		t.Fatalf("Run() output does not parse: %v\n%s", err, code)
+	}
+	imports := make(map[string]string)
+	for _, spec := range f.Imports {
+		imports[strings.Trim(spec.Path.Value, `"`)] = spec.Name.Name
+	}
+	for path, alias := range map[string]string{
+		"example.com/fork/jwriter":   "jwriter",
+		"example.com/fork/jlexer":    "jlexer",
+		"github.com/mailru/easyjson": "easyjson",
+	} {
+		if imports[path] != alias {
+			t.Errorf("Run() output imports %q as %q; want %q", path, imports[path], alias)
+		}
+	}
+	for _, path := range []string{"github.com/mailru/easyjson/jwriter", "github.com/mailru/easyjson/jlexer"} {
+		if _, ok := imports[path]; ok {
+			t.Errorf("Run() output imports %q", path)
+		}
+	}
+}
+
+// duplicateTags uses a custom tag key, as vet reports repeated json tags.
+type duplicateTags struct {
+	First  string `api:"name"`
+	Second string `api:"name,omitempty"`
+}
+
+type duplicateTagAndName struct {
+	Name  string
+	Other string `json:"Name"`
+}
+
+type duplicateEmbeddedInner struct {
+	ID int `json:"id"`
+}
+
+type duplicateEmbedded struct {
+	duplicateEmbeddedInner
+	Key string `json:"id"`
+}
+
+type duplicateOmitted struct {
+	Name  string
+	Other string `json:"-"`
+	Old   string `json:"-,"`
+}
+
+type duplicateSnakeCase struct {
+	HTTPVersion string
+	HttpVersion string
+}
+
+func TestDuplicateFieldNames(t *testing.T) {
+	for i, test := range []struct {
+		obj       interface{}
+		tagKey    string
+		snakeCase bool
+		wantErr   string
+	}{
+		{obj: duplicateTags{}, tagKey: "api", wantErr: `fields First and Second have the same JSON name "name"`},
+		{obj: duplicateTagAndName{}, wantErr: `fields Name and Other have the same JSON name "Name"`},
+		// The promoted field is shadowed rather than duplicated.
+		{obj: duplicateEmbedded{}},
+		{obj: duplicateOmitted{}},
+		{obj: duplicateSnakeCase{}},
+		{obj: duplicateSnakeCase{}, snakeCase: true, wantErr: `fields HTTPVersion and HttpVersion have the same JSON name "http_version"`},
+	} {
+		g := NewGenerator("duplicate.go")
+		g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+		if test.tagKey != "" {
+			g.SetTagKey(test.tagKey)
+		}
+		if test.snakeCase {
+			g.UseSnakeCase()
+		}
+		g.Add(test.obj)
+
+		var out bytes.Buffer
+		err := g.Run(&out)
+		switch {
+		case test.wantErr == "":
+			if err != nil {
+				t.Errorf("[%d] Run() for %T error: %v", i, test.obj, err)
+			}
+		case err == nil:
+			t.Errorf("[%d] Run() for %T ok; want error %q", i, test.obj, test.wantErr)
+		case !strings.Contains(err.Error(), test.wantErr):
+			t.Errorf("[%d] Run() for %T error %q; want it to contain %q", i, test.obj, err, test.wantErr)
+		case out.Len() != 0:
+			t.Errorf("[%d] Run() for %T wrote %d bytes of code along with the error", i, test.obj, out.Len())
+		}
+	}
+}
+
+type directionInner struct{ A int }
+type directionShared struct{ B int }
+
+type decodeOnlyStruct struct {
+	Inner  directionInner
+	Shared directionShared
+}
+
+type bothWaysStruct struct {
+	Shared directionShared
+}
+
+func TestAddOneDirection(t *testing.T) {
+	g := NewGenerator("direction.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.AddDecoderOnly(decodeOnlyStruct{})
+	g.Add(bothWaysStruct{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	code := out.String()
+
+	for i, test := range []struct {
+		name string
+		want bool
+	}{
+		{"func " + g.getDecoderName(reflect.TypeOf(decodeOnlyStruct{})) + "(", true},
+		{"func " + g.getEncoderName(reflect.TypeOf(decodeOnlyStruct{})) + "(", false},
+		{"func (v *decodeOnlyStruct) UnmarshalJSON(", true},
+		{"func (v decodeOnlyStruct) MarshalJSON(", false},
+		{"func " + g.getDecoderName(reflect.TypeOf(directionInner{})) + "(", true},
+		{"func " + g.getEncoderName(reflect.TypeOf(directionInner{})) + "(", false},
+		{"func " + g.getDecoderName(reflect.TypeOf(directionShared{})) + "(", true},
+		{"func " + g.getEncoderName(reflect.TypeOf(directionShared{})) + "(", true},
+		{"func (v bothWaysStruct) MarshalJSON(", true},
+		{"func (v *bothWaysStruct) UnmarshalJSON(", true},
+	} {
+		if got := strings.Contains(code, test.name); got != test.want {
+			t.Errorf("[%d] output contains %q = %v; want %v", i, test.name, got, test.want)
+		}
+	}
+}
+
+type assertedStruct struct{ A int }
+type assertedDecodeOnly struct{ B int }
+
+func TestInterfaceAssertions(t *testing.T) {
+	for i, test := range []struct {
+		noStdMarshalers bool
+		want            []string
+	}{
+		{want: []string{
+			"easyjson.Marshaler = (*assertedStruct)(nil)",
+			"easyjson.Unmarshaler = (*assertedDecodeOnly)(nil)",
+			"easyjson.Unmarshaler = (*assertedStruct)(nil)",
+			"json.Marshaler = (*assertedStruct)(nil)",
+			"json.Unmarshaler = (*assertedDecodeOnly)(nil)",
+			"json.Unmarshaler = (*assertedStruct)(nil)",
+		}},
+		{noStdMarshalers: true, want: []string{
+			"easyjson.Marshaler = (*assertedStruct)(nil)",
+			"easyjson.Unmarshaler = (*assertedDecodeOnly)(nil)",
+			"easyjson.Unmarshaler = (*assertedStruct)(nil)",
+		}},
+	} {
+		g := NewGenerator("asserted.go")
+		g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+		if test.noStdMarshalers {
+			g.NoStdMarshalers()
+		}
+		g.Add(assertedStruct{})
+		g.AddDecoderOnly(assertedDecodeOnly{})
+
+		var out bytes.Buffer
+		if err := g.Run(&out); err != nil {
+			t.Fatalf("[%d] Run() error: %v", i, err)
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), "asserted_easyjson.go", out.Bytes(), 0)
+		if err != nil {
+			t.Fatalf("[%d] output does not parse: %v\n%s", i, err, out.Bytes())
+		}
+
+		var got []string
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				vs := spec.(*ast.ValueSpec)
+				if len(vs.Names) == 1 && vs.Names[0].Name == "_" && vs.Type != nil && len(vs.Values) == 1 {
+					got = append(got, types.ExprString(vs.Type)+" = "+types.ExprString(vs.Values[0]))
+				}
+			}
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("[%d] output asserts %q; want %q", i, got, test.want)
+		}
+	}
+}
+
+type plainStruct struct {
+	A int
+	B []string
+}
+
+type interfaceFieldStruct struct{ Any interface{} }
+
+var jsonRefRe = regexp.MustCompile(`\bjson\.[A-Z]`)
+
+func TestJSONImport(t *testing.T) {
+	for i, test := range []struct {
+		obj             interface{}
+		noStdMarshalers bool
+		want            bool
+	}{
+		{obj: plainStruct{}, noStdMarshalers: true, want: false},
+		{obj: plainStruct{}, want: true},
+		{obj: interfaceFieldStruct{}, noStdMarshalers: true, want: true},
+	} {
+		g := NewGenerator("imports.go")
+		g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+		if test.noStdMarshalers {
+			g.NoStdMarshalers()
+		}
+		g.Add(test.obj)
+
+		var out bytes.Buffer
+		if err := g.Run(&out); err != nil {
+			t.Fatalf("[%d] Run() error: %v", i, err)
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), "imports_easyjson.go", out.Bytes(), parser.ImportsOnly)
+		if err != nil {
+			t.Fatalf("[%d] output does not parse: %v\n%s", i, err, out.Bytes())
+		}
+
+		got := false
+		for _, imp := range f.Imports {
+			if imp.Path.Value == `"encoding/json"` {
+				got = true
+			}
+		}
+		if got != test.want {
+			t.Errorf("[%d] encoding/json imported for %T: %v; want %v", i, test.obj, got, test.want)
+		}
+		if !got && jsonRefRe.Match(out.Bytes()) {
+			t.Errorf("[%d] output for %T refers to encoding/json without importing it:\n%s", i, test.obj, out.Bytes())
+		}
+	}
+}
+
+type setTaggedSlice struct {
+	Values []string `json:",set"`
+}
+
+func TestSetTagUnsupported(t *testing.T) {
+	g := NewGenerator("set.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.Add(setTaggedSlice{})
+
+	var out bytes.Buffer
+	err := g.Run(&out)
+	if err == nil || !strings.Contains(err.Error(), "'set' option") {
+		t.Errorf("Run() error = %v; want the 'set' option rejected", err)
+	}
+}
+
+type legacyNamedStruct struct{ LegacyField int }
+type defaultNamedStruct struct{ NewField int }
+
+func TestSetFieldNamerForType(t *testing.T) {
+	g := NewGenerator("namers.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.SetFieldNamerForType(reflect.TypeOf(&legacyNamedStruct{}), SnakeCaseFieldNamer{})
+	g.Add(legacyNamedStruct{})
+	g.Add(defaultNamedStruct{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	code := out.String()
+
+	for i, test := range []struct {
+		name string
+		want bool
+	}{
+		{`case "legacy_field":`, true},
+		{`case "LegacyField":`, false},
+		{`case "NewField":`, true},
+		{`case "new_field":`, false},
+	} {
+		if got := strings.Contains(code, test.name); got != test.want {
+			t.Errorf("[%d] output contains %q = %v; want %v", i, test.name, got, test.want)
+		}
+	}
+}
+
+type splitShared struct{ V int }
+type splitOwn struct{ T time.Time }
+
+type splitFirst struct {
+	Shared splitShared
+	Own    splitOwn
+}
+
+type splitSecond struct {
+	Shared []splitShared
+}
+
+type splitThird struct {
+	First *splitFirst
+}
+
+func TestRunSplit(t *testing.T) {
+	g := NewGenerator("split.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.Add(splitFirst{})
+	g.Add(splitSecond{})
+	g.Add(splitThird{})
+
+	files, err := g.RunSplit()
+	if err != nil {
+		t.Fatalf("RunSplit() error: %v", err)
+	}
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	wantNames := []string{SharedFileName, "split_first_easyjson.go", "split_second_easyjson.go", "split_third_easyjson.go"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("RunSplit() files = %v; want %v", names, wantNames)
+	}
+
+	funcs := map[string]string{}
+	for _, name := range names {
+		f, err := parser.ParseFile(token.NewFileSet(), name, files[name], 0)
+		if err != nil {
+			t.Fatalf("%v does not parse: %v\n%s", name, err, files[name])
+		}
+
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			id := fn.Name.Name
+			if fn.Recv != nil {
+				id = types.ExprString(fn.Recv.List[0].Type) + "." + id
+			}
+			if prev, ok := funcs[id]; ok {
+				t.Errorf("%v is defined in both %v and %v", id, prev, name)
+			}
+			funcs[id] = name
+		}
+
+		importsTime := false
+		for _, imp := range f.Imports {
+			importsTime = importsTime || imp.Path.Value == `"time"`
+		}
+		if wantTime := name == "split_first_easyjson.go"; importsTime != wantTime {
+			t.Errorf("%v imports time = %v; want %v", name, importsTime, wantTime)
+		}
+	}
+
+	for i, test := range []struct {
+		typ  interface{}
+		file string
+	}{
+		{splitShared{}, SharedFileName},
+		{splitOwn{}, "split_first_easyjson.go"},
+		{splitFirst{}, "split_first_easyjson.go"},
+		{splitSecond{}, "split_second_easyjson.go"},
+		{splitThird{}, "split_third_easyjson.go"},
+	} {
+		typ := reflect.TypeOf(test.typ)
+		for _, fn := range []string{g.getEncoderName(typ), g.getDecoderName(typ)} {
+			if got := funcs[fn]; got != test.file {
+				t.Errorf("[%d] %v is defined in %q; want %q", i, fn, got, test.file)
+			}
+		}
+	}
+}
+
+type boolValuesFlags struct {
+	On    bool
+	Flags []bool
+}
+
+func TestSetBoolDecodeMap(t *testing.T) {
+	values := map[string]bool{"Y": true, "N": false}
+	g := NewGenerator("bool_values.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.SetBoolDecodeMap(values)
+	g.Add(boolValuesFlags{})
+	values["X"] = true
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	code := out.String()
+	if _, err := parser.ParseFile(token.NewFileSet(), "bool_values_easyjson.go", code, 0); err != nil {
+		t.Fatalf("Run() output does not parse: %v\n%s", err, code)
+	}
+	name := g.boolDecodeMapVar()
+	for want, n := range map[string]int{
+		"var " + name + " = map[string]bool{": 1,
+		"in.BoolMapped(" + name + ")":         2,
+		`"X"`:                                 0,
+	} {
+		if got := strings.Count(code, want); got != n {
+			t.Errorf("Run() output contains %q %d times; want %d", want, got, n)
+		}
+	}
+
+	// Split output declares the map once, along with the shared code.
+	g = NewGenerator("bool_values.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.SetBoolDecodeMap(values)
+	g.Add(splitFirst{})
+	g.Add(boolValuesFlags{})
+	files, err := g.RunSplit()
+	if err != nil {
+		t.Fatalf("RunSplit() error: %v", err)
+	}
+	for file, code := range files {
+		n := strings.Count(string(code), "var "+name+" = ")
+		if want := file == SharedFileName; (n == 1) != want || n > 1 {
+			t.Errorf("%v declares %v %d times", file, name, n)
+		}
+	}
+}
+
+func TestAddAnonymousStruct(t *testing.T) {
+	first := struct {
+		ID   int `json:"id"`
+		Tags []struct{ Name string }
+	}{}
+	second := struct{ Name string }{}
+
+	run := func(objs ...interface{}) (string, *Generator) {
+		g := NewGenerator("anonymous.go")
+		g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+		for _, obj := range objs {
+			g.Add(obj)
+		}
+
+		var out bytes.Buffer
+		if err := g.Run(&out); err != nil {
+			t.Fatalf("Run() error: %v", err)
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), "anonymous.go", out.Bytes(), 0); err != nil {
+			t.Fatalf("Run() output does not parse: %v\n%s", err, out.Bytes())
+		}
+		return out.String(), g
+	}
+
+	code, g := run(first, second)
+	if strings.Contains(code, ") MarshalJSON(") || strings.Contains(code, ") UnmarshalJSON(") {
+		t.Errorf("Run() output declares methods on an unnamed type:\n%s", code)
+	}
+
+	code2, g2 := run(second, first)
+	for _, typ := range []reflect.Type{reflect.TypeOf(first), reflect.TypeOf(second), reflect.TypeOf(first.Tags).Elem()} {
+		name := g.getDecoderName(typ)
+		if !strings.Contains(code, "func "+name+"(") {
+			t.Errorf("decoder %v for %v is not generated", name, typ)
+		}
+		if name2 := g2.getDecoderName(typ); name2 != name || !strings.Contains(code2, "func "+name2+"(") {
+			t.Errorf("decoder name for %v depends on Add order: %v != %v", typ, name, name2)
+		}
+	}
+}
+
+type addedItem struct {
+	Name string
+}
+
+type addedItems []addedItem
+
+type addedIndex map[string]addedItem
+
+func TestAddSliceAndMapTypes(t *testing.T) {
+	g := NewGenerator("items.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.Add(addedItems{})
+	g.Add(addedIndex{})
+	g.Add([]addedItem{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	code := out.String()
+	if _, err := parser.ParseFile(token.NewFileSet(), "items_easyjson.go", code, 0); err != nil {
+		t.Fatalf("Run() output does not parse: %v\n%s", err, code)
+	}
+	for _, want := range []string{
+		"func (v addedItems) MarshalJSON() ([]byte, error)",
+		"func (v *addedItems) UnmarshalJSON(data []byte) error",
+		"func (v addedIndex) MarshalJSON() ([]byte, error)",
+		"func (v *addedIndex) UnmarshalJSON(data []byte) error",
+		"func " + g.getDecoderName(reflect.TypeOf(addedItem{})) + "(",
+		"func " + g.getDecoderName(reflect.TypeOf([]addedItem{})) + "(",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Run() output does not contain %q:\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "func (v []addedItem)") || strings.Contains(code, "func (v addedItem)") {
+		t.Errorf("Run() output declares methods on a type not added by name:\n%s", code)
+	}
+}
+
+type recursiveNode struct {
+	Next     *recursiveNode
+	Children []recursiveNode
+	Other    *recursiveOther
+}
+
+type recursiveOther struct {
+	Nodes map[string]recursiveNode
+}
+
+func TestRunRecursive(t *testing.T) {
+	g := NewGenerator("recursive.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.Add(recursiveNode{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	code := out.String()
+
+	for _, typ := range []reflect.Type{reflect.TypeOf(recursiveNode{}), reflect.TypeOf(recursiveOther{})} {
+		for _, name := range []string{g.getEncoderName(typ), g.getDecoderName(typ)} {
+			if n := strings.Count(code, "func "+name+"("); n != 1 {
+				t.Errorf("%v is defined %d times; want 1", name, n)
+			}
+		}
+	}
+}
+
+type reusedItems []map[string]*recursiveOther
+
+type reusedHolder struct {
+	Items    reusedItems
+	ByName   map[string]reusedItems
+	Lists    [][]reusedItems
+	Interned reusedItems `json:",intern"`
+}
+
+func TestRunReusesNamedFuncs(t *testing.T) {
+	g := NewGenerator("reused.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.Add(reusedHolder{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	code := out.String()
+	if _, err := parser.ParseFile(token.NewFileSet(), "reused_easyjson.go", code, 0); err != nil {
+		t.Fatalf("Run() output does not parse: %v\n%s", err, code)
+	}
+
+	// The code for reusedItems is in its funcs and inline for the interned field.
+	typ := reflect.TypeOf(reusedItems{})
+	for _, name := range []string{g.getEncoderName(typ), g.getDecoderName(typ)} {
+		if n := strings.Count(code, name+"("); n != 4 {
+			t.Errorf("%v is referred to %d times; want 4", name, n)
+		}
+	}
+	if n := strings.Count(code, "make(reusedItems, "); n != 2 {
+		t.Errorf("reusedItems is decoded by %d copies of the code; want 2", n)
+	}
+}
+
+type implShape interface {
+	Area() float64
+}
+
+type implCircle struct{ R float64 }
+
+func (c implCircle) Area() float64 { return c.R * c.R }
+
+func TestRegisterInterfaceImplUnimplemented(t *testing.T) {
+	shapeType := reflect.TypeOf((*implShape)(nil)).Elem()
+	value := struct{ S implShape }{}
+
+	for i, test := range []struct {
+		impls     map[string]reflect.Type
+		wantError bool
+	}{
+		{impls: map[string]reflect.Type{"circle": reflect.TypeOf(implCircle{})}},
+		{impls: map[string]reflect.Type{"circle": reflect.TypeOf(implCircle{}), "point": reflect.TypeOf(struct{ X int }{})}, wantError: true},
+	} {
+		g := NewGenerator("impl.go")
+		g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+		g.RegisterInterfaceImpl(shapeType, "kind", test.impls)
+		g.Add(value)
+
+		err := g.Run(&bytes.Buffer{})
+		if !test.wantError && err != nil {
+			t.Errorf("[%d] Run() error: %v", i, err)
+		} else if test.wantError && err == nil {
+			t.Errorf("[%d] Run() with a type not implementing %v ok; want error", i, shapeType)
+		}
+	}
+}
+
+func TestGeneratedHeader(t *testing.T) {
+	marker := regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+	for i, test := range []struct {
+		version string
+		want    string
+	}{
+		{version: Version, want: "// Code generated by easyjson " + Version + ". DO NOT EDIT."},
+		{version: "v1.2.3-test", want: "// Code generated by easyjson v1.2.3-test. DO NOT EDIT."},
+		{version: "", want: "// Code generated by easyjson. DO NOT EDIT."},
+	} {
+		g := NewGenerator("header.go")
+		g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+		g.SetBuildTags("use_easyjson")
+		if test.version != Version {
+			g.SetVersion(test.version)
+		}
+		g.Add(struct{ A int }{})
+
+		var out bytes.Buffer
+		if err := g.Run(&out); err != nil {
+			t.Fatalf("[%d] Run() error: %v", i, err)
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), "header.go", out.Bytes(), parser.ParseComments)
+		if err != nil {
+			t.Fatalf("[%d] Run() output does not parse: %v\n%s", i, err, out.Bytes())
+		}
+
+		var found bool
+		for _, group := range f.Comments {
+			if group.Pos() > f.Package {
+				break
+			}
+			for _, c := range group.List {
+				if marker.MatchString(c.Text) {
+					found = true
+					if c.Text != test.want {
+						t.Errorf("[%d] header = %q; want %q", i, c.Text, test.want)
+					}
+				}
+			}
+		}
+		if !found {
+			t.Errorf("[%d] no generated code marker before the package clause:\n%s", i, out.Bytes())
+		}
+	}
+}
+
+func TestSetFloatFormat(t *testing.T) {
+	type floats struct {
+		A float64
+	}
+	for _, test := range []struct {
+		format  byte
+		prec    int
+		want    string
+		wantErr bool
+	}{
+		{format: 'f', prec: 2, want: "FloatFormat: 'f', FloatPrecision: 2"},
+		{format: 'e', prec: -1, want: "FloatFormat: 'e', FloatPrecision: -1"},
+		{format: 'b', prec: -1, wantErr: true},
+		{format: 'q', prec: 3, wantErr: true},
+	} {
+		g := NewGenerator("floats.go")
+		g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+		g.SetFloatFormat(test.format, test.prec)
+		g.Add(floats{})
+
+		var out bytes.Buffer
+		err := g.Run(&out)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("Run() with float format %q ok; want error", test.format)
+			}
+			if _, err := g.RunSplit(); err == nil {
+				t.Errorf("RunSplit() with float format %q ok; want error", test.format)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Run() with float format %q error: %v", test.format, err)
+		} else if !strings.Contains(out.String(), test.want) {
+			t.Errorf("Run() output does not contain %q:\n%s", test.want, out.String())
+		}
+	}
+}
+
+func TestRejectDuplicateKeys(t *testing.T) {
+	var fields []reflect.StructField
+	for i := 0; i < 65; i++ {
+		fields = append(fields, reflect.StructField{Name: "F" + strconv.Itoa(i), Type: reflect.TypeOf(0)})
+	}
+	obj := reflect.New(reflect.StructOf(fields)).Elem().Interface()
+
+	g := NewGenerator("wide.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.RejectDuplicateKeys()
+	g.Add(obj)
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	for _, want := range []string{
+		"var seenKeys [2]uint64",
+		"if seenKeys[0]&0x8000000000000000 != 0 {",
+		"seenKeys[1] |= 0x1\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Run() output does not contain %q:\n%s", want, out.String())
+		}
+	}
+}
+
+type defaultBadInt struct {
+	Retries int8 `default:"300"`
+}
+
+type defaultBadBool struct {
+	Enabled bool `default:"yes"`
+}
+
+type defaultBadFloat struct {
+	Ratio float64 `default:"NaN"`
+}
+
+type defaultUnsupported struct {
+	Tags []string `default:"a,b"`
+}
+
+type defaultOmitted struct {
+	Tags []string `json:"-" default:"a,b"`
+}
+
+func TestInvalidFieldDefaults(t *testing.T) {
+	for i, test := range []struct {
+		obj     interface{}
+		wantErr string
+	}{
+		{obj: defaultBadInt{}, wantErr: "invalid default value of field Retries of type int8"},
+		{obj: defaultBadBool{}, wantErr: "invalid default value of field Enabled of type bool"},
+		{obj: defaultBadFloat{}, wantErr: `"NaN" is not a finite number`},
+		{obj: defaultUnsupported{}, wantErr: "default values are not supported for kind slice"},
+		{obj: defaultOmitted{}},
+	} {
+		g := NewGenerator("defaults.go")
+		g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+		g.Add(test.obj)
+
+		var out bytes.Buffer
+		err := g.Run(&out)
+		switch {
+		case test.wantErr == "":
+			if err != nil {
+				t.Errorf("[%d] Run() for %T error: %v", i, test.obj, err)
+			}
+		case err == nil:
+			t.Errorf("[%d] Run() for %T ok; want error %q", i, test.obj, test.wantErr)
+		case !strings.Contains(err.Error(), test.wantErr):
+			t.Errorf("[%d] Run() for %T error %q; want it to contain %q", i, test.obj, err, test.wantErr)
+		}
+	}
+}
+
+type selfRefSlice []selfRefSlice
+
+type selfRefTree struct {
+	Name     string
+	Children []selfRefTree
+	Nested   selfRefSlice
+}
+
+func TestSamePackageSelfReference(t *testing.T) {
+	const pkgPath = "github.com/mailru/easyjson/gen"
+	for _, path := range []string{
+		pkgPath,
+		"example.com/app/vendor/" + pkgPath,
+	} {
+		g := NewGenerator("tree.go")
+		g.SetPkg("gen", path)
+		g.Add(selfRefTree{})
+		g.Add(selfRefSlice{})
+
+		var out bytes.Buffer
+		if err := g.Run(&out); err != nil {
+			t.Fatalf("%v: Run() error: %v", path, err)
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), "tree_easyjson.go", out.Bytes(), 0)
+		if err != nil {
+			t.Fatalf("%v: output does not parse: %v\n%s", path, err, out.Bytes())
+		}
+		for _, imp := range f.Imports {
+			if imp.Path.Value == strconv.Quote(pkgPath) || imp.Path.Value == strconv.Quote(path) {
+				t.Errorf("%v: output imports its own package:\n%s", path, out.Bytes())
+			}
+		}
+		if !bytes.Contains(out.Bytes(), []byte("[]selfRefTree")) {
+			t.Errorf("%v: output does not refer to []selfRefTree:\n%s", path, out.Bytes())
+		}
+	}
+}
+
+type inlineTwoMaps struct {
+	A map[string]int    `json:",inline"`
+	B map[string]string `json:",inline"`
+}
+
+type inlineIntKeys struct {
+	A map[int]int `json:",inline"`
+}
+
+type inlineScalar struct {
+	A int `json:",inline"`
+}
+
+type inlineMapBase struct {
+	Extra map[string]int `json:",inline"`
+}
+
+type inlineThroughPointer struct {
+	*inlineMapBase
+}
+
+type inlineNested struct {
+	Name  string
+	Inner struct {
+		Value int
+	} `json:",inline"`
+	Extra map[string]int `json:",inline"`
+}
+
+func TestInlineFields(t *testing.T) {
+	for i, test := range []struct {
+		obj     interface{}
+		wantErr string
+	}{
+		{obj: inlineTwoMaps{}, wantErr: "fields A and B both take the keys matching no field"},
+		{obj: inlineIntKeys{}, wantErr: "'inline' option is only supported for structs and maps with string keys"},
+		{obj: inlineScalar{}, wantErr: "'inline' option is only supported for structs and maps with string keys"},
+		{obj: inlineThroughPointer{}, wantErr: "inline map field Extra is promoted through an embedded pointer"},
+		{obj: inlineNested{}},
+	} {
+		g := NewGenerator("inline.go")
+		g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+		g.Add(test.obj)
+
+		var out bytes.Buffer
+		err := g.Run(&out)
+		switch {
+		case test.wantErr == "":
+			if err != nil {
+				t.Errorf("[%d] Run() for %T error: %v", i, test.obj, err)
+			}
+		case err == nil:
+			t.Errorf("[%d] Run() for %T ok; want error %q", i, test.obj, test.wantErr)
+		case !strings.Contains(err.Error(), test.wantErr):
+			t.Errorf("[%d] Run() for %T error %q; want it to contain %q", i, test.obj, err, test.wantErr)
+		}
+	}
+}
+
+type extraTwoFields struct {
+	A map[string]json.RawMessage `json:",extra"`
+	B map[string]json.RawMessage `json:",extra"`
+}
+
+type extraWithInline struct {
+	A map[string]json.RawMessage `json:",extra"`
+	B map[string]string          `json:",inline"`
+}
+
+type extraNotRaw struct {
+	A map[string]string `json:",extra"`
+}
+
+func TestExtraFields(t *testing.T) {
+	for i, test := range []struct {
+		obj     interface{}
+		wantErr string
+	}{
+		{obj: extraTwoFields{}, wantErr: "fields A and B both take the keys matching no field"},
+		{obj: extraWithInline{}, wantErr: "fields A and B both take the keys matching no field"},
+		{obj: extraNotRaw{}, wantErr: "'extra' option is only supported for map[string]json.RawMessage"},
+	} {
+		g := NewGenerator("extra.go")
+		g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+		g.Add(test.obj)
+
+		var out bytes.Buffer
+		err := g.Run(&out)
+		switch {
+		case err == nil:
+			t.Errorf("[%d] Run() for %T ok; want error %q", i, test.obj, test.wantErr)
+		case !strings.Contains(err.Error(), test.wantErr):
+			t.Errorf("[%d] Run() for %T error %q; want it to contain %q", i, test.obj, err, test.wantErr)
+		}
+	}
+}
+
+type viewedStruct struct {
+	Public string `view:"public"`
+	Admin  string `view:"admin" json:"admin,omitempty"`
+	Custom int    `roles:"public"`
+}
+
+type viewedSlice []viewedStruct
+
+func TestViews(t *testing.T) {
+	for i, test := range []struct {
+		obj      interface{}
+		views    []string
+		tagKey   string
+		wantErr  string
+		wantCode []string
+	}{
+		{
+			obj:      viewedStruct{},
+			views:    []string{"public", "admin"},
+			wantCode: []string{"func (v viewedStruct) MarshalJSONPublic() ([]byte, error)", "func (v viewedStruct) MarshalJSONAdmin() ([]byte, error)"},
+		},
+		{obj: viewedStruct{}, views: []string{"public"}, tagKey: "roles", wantCode: []string{`const prefix string = ",\"Custom\":"`}},
+		{obj: viewedSlice{}, views: []string{"public"}, wantErr: "not a struct type"},
+		{obj: viewedStruct{}, views: []string{"read-only"}, wantErr: `invalid name of view "read-only"`},
+		{obj: viewedStruct{}, views: []string{"admin", "admin"}, wantErr: `view "admin" is added for gen.viewedStruct twice`},
+	} {
+		g := NewGenerator("views.go")
+		g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+		for _, name := range test.views {
+			g.AddView(test.obj, name, test.tagKey)
+		}
+
+		var out bytes.Buffer
+		err := g.Run(&out)
+		switch {
+		case test.wantErr == "":
+			if err != nil {
+				t.Errorf("[%d] Run() for %T error: %v", i, test.obj, err)
+			}
+		case err == nil:
+			t.Errorf("[%d] Run() for %T ok; want error %q", i, test.obj, test.wantErr)
+		case !strings.Contains(err.Error(), test.wantErr):
+			t.Errorf("[%d] Run() for %T error %q; want it to contain %q", i, test.obj, err, test.wantErr)
+		}
+		for _, code := range test.wantCode {
+			if !strings.Contains(out.String(), code) {
+				t.Errorf("[%d] output does not contain %q:\n%s", i, code, out.String())
+			}
+		}
+	}
+}
+
+func TestIndentPooledWriter(t *testing.T) {
+	g := NewGenerator("indent.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.Indent("", "  ")
+	g.UsePooledWriter()
+	g.Add(fuzzRecord{})
+
+	if err := g.Run(&bytes.Buffer{}); err == nil {
+		t.Error("Run() with Indent and UsePooledWriter ok; want error")
+	}
+}
@@ -0,0 +1,62 @@
+//go:build go1.18
+// +build go1.18
+
+package gen
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"testing"
+	"time"
+)
+
+type genericBox[T any] struct {
+	Val T
+}
+
+type genericItem struct {
+	Name string
+}
+
+type genericPair[K comparable, V any] struct {
+	Items map[K][]V
+}
+
+type genericHolder struct {
+	Box  genericBox[*time.Time]
+	Pair genericPair[string, genericBox[genericItem]]
+}
+
+func TestGenericInstantiations(t *testing.T) {
+	g := NewGenerator("box.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.Add(genericBox[string]{})
+	g.Add(genericBox[int]{})
+	g.Add(genericHolder{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "box_easyjson.go", out.Bytes(), 0); err != nil {
+		t.Fatalf("output does not parse: %v\n%s", err, out.Bytes())
+	}
+	for _, want := range []string{
+		"EncodeGithubComMailruEasyjsonGenGenericBoxString(out *jwriter.Writer, in genericBox[string])",
+		"DecodeGithubComMailruEasyjsonGenGenericBoxInt(in *jlexer.Lexer, out *genericBox[int])",
+		"out *genericBox[*time.Time]",
+		"in genericPair[string, genericBox[genericItem]]",
+		"func (v genericHolder) MarshalJSON() ([]byte, error)",
+		`time "time"`,
+	} {
+		if !bytes.Contains(out.Bytes(), []byte(want)) {
+			t.Errorf("output does not contain %q:\n%s", want, out.Bytes())
+		}
+	}
+
+	// Methods cannot be declared on the instantiations.
+	if bytes.Contains(out.Bytes(), []byte("func (v genericBox")) || bytes.Contains(out.Bytes(), []byte("func (v *genericBox")) {
+		t.Errorf("output declares methods on an instantiation:\n%s", out.Bytes())
+	}
+}
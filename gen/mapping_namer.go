@@ -0,0 +1,49 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+)
+
+// MappingFieldNamer names fields after an explicit mapping from the names of
+// struct types to ones from the names of their fields to the JSON names, which
+// take precedence over field tags. Fields not in the mapping are named by
+// Fallback.
+type MappingFieldNamer struct {
+	Names map[string]map[string]string
+
+	// Fallback names the fields not in Names, DefaultFieldNamer if nil.
+	Fallback FieldNamer
+}
+
+// NewMappingFieldNamer returns a field namer using names, e.g. read with
+// LoadFieldNameMapping, and fallback for the fields not in it.
+func NewMappingFieldNamer(names map[string]map[string]string, fallback FieldNamer) MappingFieldNamer {
+	return MappingFieldNamer{Names: names, Fallback: fallback}
+}
+
+func (n MappingFieldNamer) GetJSONFieldName(t reflect.Type, f reflect.StructField) string {
+	if name, ok := n.Names[t.Name()][f.Name]; ok {
+		return name
+	}
+	if n.Fallback == nil {
+		return DefaultFieldNamer{}.GetJSONFieldName(t, f)
+	}
+	return n.Fallback.GetJSONFieldName(t, f)
+}
+
+// LoadFieldNameMapping reads the mapping of a MappingFieldNamer from a JSON file
+// holding an object per type, like {"User": {"UserID": "uid"}}.
+func LoadFieldNameMapping(filename string) (map[string]map[string]string, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var names map[string]map[string]string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("cannot load field names from %v: %v", filename, err)
+	}
+	return names, nil
+}
@@ -0,0 +1,89 @@
+package gen
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type mappedUser struct {
+	UserID int
+	Name   string `json:"name"`
+}
+
+type mappedOrder struct {
+	UserID int
+}
+
+func TestMappingFieldNamer(t *testing.T) {
+	f, err := ioutil.TempFile("", "easyjson-names")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(`{"mappedUser": {"UserID": "uid"}}`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	names, err := LoadFieldNameMapping(f.Name())
+	if err != nil {
+		t.Fatalf("LoadFieldNameMapping() error: %v", err)
+	}
+	g := NewGenerator("mapped.go")
+	g.SetPkg("gen", "github.com/mailru/easyjson/gen")
+	g.SetFieldNamer(NewMappingFieldNamer(names, nil))
+	g.Add(mappedUser{})
+	g.Add(mappedOrder{})
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	code := out.String()
+	for want, n := range map[string]int{
+		`case "uid":`:    1,
+		`case "UserID":`: 1,
+		`case "name":`:   1,
+	} {
+		if got := strings.Count(code, want); got != n {
+			t.Errorf("output contains %q %d times; want %d", want, got, n)
+		}
+	}
+}
+
+func TestMappingFieldNamerTagKey(t *testing.T) {
+	type tagged struct {
+		A int `yaml:"a"`
+		B int `yaml:"b"`
+	}
+	g := NewGenerator("mapped.go")
+	g.SetFieldNamer(NewMappingFieldNamer(map[string]map[string]string{"tagged": {"B": "bee"}}, SnakeCaseFieldNamer{}))
+	g.SetTagKey("yaml")
+
+	typ := reflect.TypeOf(tagged{})
+	for i, want := range []string{"a", "bee"} {
+		if got := g.jsonFieldName(typ, typ.Field(i)); got != want {
+			t.Errorf("jsonFieldName(%v) = %q; want %q", typ.Field(i).Name, got, want)
+		}
+	}
+}
+
+func TestLoadFieldNameMappingError(t *testing.T) {
+	f, err := ioutil.TempFile("", "easyjson-names")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(`{"mappedUser": ["uid"]}`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if _, err := LoadFieldNameMapping(f.Name()); err == nil || !strings.Contains(err.Error(), f.Name()) {
+		t.Errorf("LoadFieldNameMapping() error = %v; want one naming the file", err)
+	}
+}
@@ -0,0 +1,75 @@
+package gen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// GenerateReset makes the generator add a Reset method to the types with
+// UnmarshalJSON methods, which sets the value to zero so that it can be put back
+// into a pool and reused for decoding. Slices are truncated to length zero,
+// keeping their capacity, and fields of types with a Reset method are reset with
+// it.
+func (g *Generator) GenerateReset() {
+	g.reset = true
+}
+
+// hasReset returns whether the type t has a Reset method without arguments and
+// results, called by the generated Reset methods of the types with fields of t.
+func hasReset(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		return false
+	}
+	m, ok := reflect.PtrTo(t).MethodByName("Reset")
+	return ok && m.Type.NumIn() == 1 && m.Type.NumOut() == 0
+}
+
+// genReset generates the Reset method of the type t.
+func (g *Generator) genReset(t reflect.Type) {
+	fmt.Fprintln(g.out)
+	fmt.Fprintln(g.out, "// Reset sets v to zero to reuse it, keeping the capacity of slices")
+	fmt.Fprintln(g.out, "func (v *"+g.getType(t)+") Reset() {")
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Name == "_" {
+				continue
+			}
+			g.genResetCode(f.Type, "v."+f.Name, 1)
+		}
+	} else {
+		g.genZeroCode(t, "*v", 1)
+	}
+	fmt.Fprintln(g.out, "}")
+}
+
+// genResetCode generates code that resets out of type t, with its Reset method if
+// it has one.
+func (g *Generator) genResetCode(t reflect.Type, out string, indent int) {
+	if hasReset(t) {
+		fmt.Fprintln(g.out, strings.Repeat("  ", indent)+"("+out+").Reset()")
+		return
+	}
+	g.genZeroCode(t, out, indent)
+}
+
+// genZeroCode generates code that sets out of type t to zero, truncating slices.
+func (g *Generator) genZeroCode(t reflect.Type, out string, indent int) {
+	ws := strings.Repeat("  ", indent)
+
+	switch t.Kind() {
+	case reflect.Slice:
+		fmt.Fprintln(g.out, ws+out+" = ("+out+")[:0]")
+	case reflect.Struct, reflect.Array:
+		fmt.Fprintln(g.out, ws+out+" = "+g.getType(t)+"{}")
+	case reflect.String:
+		fmt.Fprintln(g.out, ws+out+` = ""`)
+	case reflect.Bool:
+		fmt.Fprintln(g.out, ws+out+" = false")
+	case reflect.Map, reflect.Ptr, reflect.Interface, reflect.Chan, reflect.Func:
+		fmt.Fprintln(g.out, ws+out+" = nil")
+	default:
+		fmt.Fprintln(g.out, ws+out+" = 0")
+	}
+}
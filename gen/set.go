@@ -0,0 +1,111 @@
+package gen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// isSetType returns whether t is a map type with empty struct values, like
+// map[string]struct{}, which models a set of its keys.
+func isSetType(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Elem().Kind() == reflect.Struct && t.Elem().NumField() == 0
+}
+
+// checkSetTag returns an error if the 'set' tag option is set for a field type
+// that is not a set or a pointer to one.
+func checkSetTag(t reflect.Type, f reflect.StructField, tags fieldTags) error {
+	if !tags.set {
+		return nil
+	}
+
+	ft := f.Type
+	if ft.Kind() == reflect.Ptr {
+		ft = ft.Elem()
+	}
+	if isSetType(ft) {
+		return nil
+	}
+	return fmt.Errorf("field %v of %v: 'set' option is not supported for type %v: only maps with struct{} values are allowed", f.Name, t, f.Type)
+}
+
+// genSetEncoder generates code that encodes the set in of type t as an array of
+// its keys, sorted if they are strings like the keys of objects are.
+func (g *Generator) genSetEncoder(t reflect.Type, in string, tags fieldTags, indent int, assumeNonEmpty bool) error {
+	ws := strings.Repeat("  ", indent)
+	key := t.Key()
+	tmpVar := g.uniqueVarName()
+
+	if !assumeNonEmpty {
+		fmt.Fprintln(g.out, ws+"if "+in+" == nil && (out.Flags & jwriter.NilMapAsEmpty) == 0 {")
+		fmt.Fprintln(g.out, ws+`  out.RawString("null")`)
+		fmt.Fprintln(g.out, ws+"} else {")
+	} else {
+		fmt.Fprintln(g.out, ws+"{")
+	}
+	fmt.Fprintln(g.out, ws+"  out.RawByte('[')")
+	fmt.Fprintln(g.out, ws+"  "+tmpVar+"First := true")
+	if !g.noSortMapKeys && key.Kind() == reflect.String && !hasCustomMarshaler(key) {
+		sortPkg := g.pkgAlias("sort")
+		fmt.Fprintln(g.out, ws+"  "+tmpVar+"Keys := make([]string, 0, len("+in+"))")
+		fmt.Fprintln(g.out, ws+"  for "+tmpVar+"Name := range "+in+" {")
+		fmt.Fprintln(g.out, ws+"    "+tmpVar+"Keys = append("+tmpVar+"Keys, string("+tmpVar+"Name))")
+		fmt.Fprintln(g.out, ws+"  }")
+		fmt.Fprintln(g.out, ws+"  "+sortPkg+".Strings("+tmpVar+"Keys)")
+		fmt.Fprintln(g.out, ws+"  for _, "+tmpVar+"Name := range "+tmpVar+"Keys {")
+	} else {
+		if g.fuzzHarness {
+			return errUnsortedFuzzKeys(key)
+		}
+		fmt.Fprintln(g.out, ws+"  for "+tmpVar+"Name := range "+in+" {")
+	}
+	fmt.Fprintln(g.out, ws+"    if "+tmpVar+"First { "+tmpVar+"First = false } else { out.RawByte(',') }")
+	if err := g.genTypeEncoder(key, tmpVar+"Name", tags, indent+2, false); err != nil {
+		return err
+	}
+	fmt.Fprintln(g.out, ws+"  }")
+	fmt.Fprintln(g.out, ws+"  out.RawByte(']')")
+	fmt.Fprintln(g.out, ws+"}")
+	return nil
+}
+
+// genSetDecoder generates code that decodes an array of keys into the set out of
+// type t, adding them to an existing set like entries are added to maps. Nulls in
+// the array are skipped.
+func (g *Generator) genSetDecoder(t reflect.Type, out string, tags fieldTags, indent int) error {
+	ws := strings.Repeat("  ", indent)
+	key := t.Key()
+	tmpVar := g.uniqueVarName()
+
+	fmt.Fprintln(g.out, ws+"if in.IsNull() {")
+	fmt.Fprintln(g.out, ws+"  in.Skip()")
+	fmt.Fprintln(g.out, ws+"  "+out+" = nil")
+	fmt.Fprintln(g.out, ws+"} else {")
+	fmt.Fprintln(g.out, ws+"  in.Delim('[')")
+	fmt.Fprintln(g.out, ws+"  if "+out+" == nil {")
+	fmt.Fprintln(g.out, ws+"    "+out+" = make("+g.getType(t)+")")
+	fmt.Fprintln(g.out, ws+"  }")
+	if g.errorPaths {
+		fmt.Fprintln(g.out, ws+"  "+tmpVar+"Index := 0")
+	}
+	fmt.Fprintln(g.out, ws+"  for !in.IsDelim(']') {")
+	g.genEnterElement(ws+"    ", "EnterIndex("+tmpVar+"Index)")
+	fmt.Fprintln(g.out, ws+"    if in.IsNull() {")
+	fmt.Fprintln(g.out, ws+"      in.Skip()")
+	fmt.Fprintln(g.out, ws+"    } else {")
+	fmt.Fprintln(g.out, ws+"      var "+tmpVar+" "+g.getType(key))
+	if err := g.genTypeDecoder(key, tmpVar, tags, indent+3); err != nil {
+		return err
+	}
+	fmt.Fprintln(g.out, ws+"      ("+out+")["+tmpVar+"] = struct{}{}")
+	fmt.Fprintln(g.out, ws+"    }")
+	g.genLeaveElement(ws + "    ")
+	if g.errorPaths {
+		fmt.Fprintln(g.out, ws+"    "+tmpVar+"Index++")
+	}
+	fmt.Fprintln(g.out, ws+"    in.WantComma()")
+	fmt.Fprintln(g.out, ws+"  }")
+	fmt.Fprintln(g.out, ws+"  in.Delim(']')")
+	fmt.Fprintln(g.out, ws+"}")
+	return nil
+}
@@ -0,0 +1,217 @@
+package gen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Rough encoded sizes of values, used by the generated size estimators.
+var primitiveSizes = map[reflect.Kind]int{
+	reflect.Bool:    5,
+	reflect.Int:     8,
+	reflect.Int8:    3,
+	reflect.Int16:   5,
+	reflect.Int32:   8,
+	reflect.Int64:   8,
+	reflect.Uint:    8,
+	reflect.Uint8:   3,
+	reflect.Uint16:  5,
+	reflect.Uint32:  8,
+	reflect.Uint64:  8,
+	reflect.Uintptr: 8,
+	reflect.Float32: 8,
+	reflect.Float64: 8,
+}
+
+// GenerateSizeEstimator makes the generator add an estimatedSize method to the
+// types with MarshalJSON methods, which walks the value for a rough estimate of
+// its encoded size, taking lengths of strings, slices and maps into account.
+// MarshalJSON uses it to size the buffer instead of a static estimate.
+func (g *Generator) GenerateSizeEstimator() {
+	g.sizeEstimator = true
+}
+
+func (g *Generator) getSizerName(t reflect.Type) string {
+	return g.functionName("size", t)
+}
+
+// addSizerType requests to generate the size estimator func for the given type.
+func (g *Generator) addSizerType(t reflect.Type) {
+	if g.curTypeCode != nil {
+		g.curTypeCode.deps[t] = true
+	}
+	if g.sizersSeen[t] || g.sizersWanted[t] {
+		return
+	}
+	g.sizersWanted[t] = true
+	g.addType(t)
+}
+
+// genSizer generates the size estimator func for the type t.
+func (g *Generator) genSizer(t reflect.Type) error {
+	fname := g.getSizerName(t)
+	typ := g.getType(t)
+
+	fmt.Fprintln(g.out, "func "+fname+"(in *"+typ+") int {")
+	if t.Kind() == reflect.Struct && t != timeType {
+		fs, err := g.structFields(t)
+		if err != nil {
+			return fmt.Errorf("cannot generate size estimator for %v: %v", t, err)
+		}
+
+		// Braces, quoted names, colons and commas.
+		size := 2
+		for _, f := range fs {
+			if tags := parseFieldTags(f, g.tagKey); !tags.omit && !tags.readOnly {
+				size += len(g.jsonFieldName(t, f)) + 4
+			}
+		}
+		fmt.Fprintf(g.out, "  size := %d\n", size)
+
+		for _, f := range fs {
+			if tags := parseFieldTags(f, g.tagKey); tags.omit || tags.readOnly {
+				continue
+			}
+			if a, ok := g.fieldAccessor(t, f); ok {
+				if a.getter != "" {
+					v := g.uniqueVarName()
+					fmt.Fprintln(g.out, "  "+v+" := in."+a.getter+"()")
+					fmt.Fprintln(g.out, "  _ = "+v)
+					g.genSizeCode(f.Type, v, 1)
+				}
+				continue
+			}
+			var checks []string
+			for _, p := range embeddedPointers(t, f) {
+				checks = append(checks, "in."+p.path+" != nil")
+			}
+			if len(checks) == 0 {
+				g.genSizeCode(f.Type, "in."+fieldSelector(t, f), 1)
+				continue
+			}
+			fmt.Fprintln(g.out, "  if "+strings.Join(checks, " && ")+" {")
+			g.genSizeCode(f.Type, "in."+fieldSelector(t, f), 2)
+			fmt.Fprintln(g.out, "  }")
+		}
+	} else {
+		fmt.Fprintln(g.out, "  size := 0")
+		g.genSizeCode(t, "(*in)", 1)
+	}
+	fmt.Fprintln(g.out, "  return size")
+	fmt.Fprintln(g.out, "}")
+	return nil
+}
+
+// genSizeCode generates code that adds the estimated encoded size of in of type t
+// to the size variable.
+func (g *Generator) genSizeCode(t reflect.Type, in string, indent int) {
+	ws := strings.Repeat("  ", indent)
+
+	if t == timeType {
+		fmt.Fprintln(g.out, ws+"size += 32")
+		return
+	}
+	if t == bigIntType {
+		fmt.Fprintln(g.out, ws+"size += ("+in+").BitLen()/3 + 2")
+		return
+	}
+	if t == bigFloatType {
+		fmt.Fprintln(g.out, ws+"size += int(("+in+").MinPrec())/3 + 8")
+		return
+	}
+	if t == rawMessageType || t == jsonNumberType {
+		fmt.Fprintln(g.out, ws+"size += len("+in+")")
+		return
+	}
+	if n := primitiveSizes[t.Kind()]; n != 0 {
+		fmt.Fprintf(g.out, ws+"size += %d\n", n)
+		return
+	}
+
+	leave, ok := g.enterInline(t)
+	if !ok {
+		g.addSizerType(t)
+		fmt.Fprintln(g.out, ws+"size += "+g.getSizerName(t)+"(&"+in+")")
+		return
+	}
+	defer leave()
+
+	switch t.Kind() {
+	case reflect.String:
+		fmt.Fprintln(g.out, ws+"size += len("+in+") + 2")
+
+	case reflect.Slice, reflect.Array:
+		elem := t.Elem()
+		if elem.Kind() == reflect.Uint8 && elem.Name() == "uint8" {
+			if g.simpleBytes {
+				fmt.Fprintln(g.out, ws+"size += len("+in+") + 2")
+			} else {
+				fmt.Fprintln(g.out, ws+"size += len("+in+")*4/3 + 4")
+			}
+			return
+		}
+		if n := primitiveSizes[elem.Kind()]; n != 0 {
+			fmt.Fprintf(g.out, ws+"size += 2 + len("+in+")*%d\n", n+1)
+			return
+		}
+		vVar := g.uniqueVarName()
+		fmt.Fprintln(g.out, ws+"size += 2 + len("+in+")")
+		fmt.Fprintln(g.out, ws+"for _, "+vVar+" := range "+in+" {")
+		g.genSizeCode(elem, vVar, indent+1)
+		fmt.Fprintln(g.out, ws+"}")
+
+	case reflect.Map:
+		stringKeys := t.Key().Kind() == reflect.String
+		// Quoted keys, colons and commas, with non-string keys assumed short.
+		entrySize := 4
+		if !stringKeys {
+			entrySize += 8
+		}
+		kVar := g.uniqueVarName()
+		if n := primitiveSizes[t.Elem().Kind()]; n != 0 {
+			fmt.Fprintf(g.out, ws+"size += 2 + len("+in+")*%d\n", entrySize+n)
+			if stringKeys {
+				fmt.Fprintln(g.out, ws+"for "+kVar+" := range "+in+" {")
+				fmt.Fprintln(g.out, ws+"  size += len("+kVar+")")
+				fmt.Fprintln(g.out, ws+"}")
+			}
+			return
+		}
+		vVar := g.uniqueVarName()
+		if !stringKeys {
+			kVar = "_"
+		}
+		fmt.Fprintf(g.out, ws+"size += 2 + len("+in+")*%d\n", entrySize)
+		fmt.Fprintln(g.out, ws+"for "+kVar+", "+vVar+" := range "+in+" {")
+		if stringKeys {
+			fmt.Fprintln(g.out, ws+"  size += len("+kVar+")")
+		}
+		g.genSizeCode(t.Elem(), vVar, indent+1)
+		fmt.Fprintln(g.out, ws+"}")
+
+	case reflect.Ptr:
+		fmt.Fprintln(g.out, ws+"if "+in+" == nil {")
+		fmt.Fprintln(g.out, ws+"  size += 4")
+		fmt.Fprintln(g.out, ws+"} else {")
+		g.genSizeCode(t.Elem(), "(*"+in+")", indent+1)
+		fmt.Fprintln(g.out, ws+"}")
+
+	case reflect.Struct:
+		g.addSizerType(t)
+		fmt.Fprintln(g.out, ws+"size += "+g.getSizerName(t)+"(&"+in+")")
+
+	default:
+		fmt.Fprintln(g.out, ws+"size += 4")
+	}
+}
+
+// genSizeEstimatorMethod generates the estimatedSize method of the type t.
+func (g *Generator) genSizeEstimatorMethod(t reflect.Type) {
+	g.addSizerType(t)
+
+	fmt.Fprintln(g.out, "// estimatedSize returns a rough estimate of the size of the JSON encoding of v")
+	fmt.Fprintln(g.out, "func (v *"+g.getType(t)+") estimatedSize() int {")
+	fmt.Fprintln(g.out, "  return "+g.getSizerName(t)+"(v)")
+	fmt.Fprintln(g.out, "}")
+}
@@ -0,0 +1,156 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+)
+
+// SharedFileName is the name of the file RunSplit puts the code used by
+// several of the requested types to.
+const SharedFileName = "shared_easyjson.go"
+
+// typeCode is the code generated for a single type.
+type typeCode struct {
+	code bytes.Buffer
+
+	// packages the code refers to
+	imports map[string]bool
+
+	// types whose encoders/decoders the code calls
+	deps map[reflect.Type]bool
+}
+
+// switchTypeCode makes the generator write the code to the buffer of type t.
+func (g *Generator) switchTypeCode(t reflect.Type) {
+	c := g.typeCodes[t]
+	if c == nil {
+		c = &typeCode{
+			imports: make(map[string]bool),
+			deps:    make(map[reflect.Type]bool),
+		}
+		g.typeCodes[t] = c
+		g.typeCodeOrder = append(g.typeCodeOrder, t)
+	}
+	g.curTypeCode = c
+	g.out = &c.code
+}
+
+// isRequested returns whether any methods were requested for t by user.
+func (g *Generator) isRequested(t reflect.Type) bool {
+	return g.marshalers[t] || g.unmarshalers[t] || g.streamers[t] || g.decodeStreamers[t] || len(g.views[t]) > 0
+}
+
+// splitFileName returns the name of the output file for a requested type.
+func splitFileName(t reflect.Type) string {
+	return camelToSnake(t.Name()) + "_easyjson.go"
+}
+
+// RunSplit runs the generator like Run, but returns a separate file for each
+// requested type, named like "type_name_easyjson.go", keyed by file name.
+// Encoders and decoders of the types they depend on go into the file of the
+// only requested type using them, or into SharedFileName if used by several,
+// like the variables the generated code shares.
+func (g *Generator) RunSplit() (map[string][]byte, error) {
+	g.typeCodes = make(map[reflect.Type]*typeCode)
+	defer func() {
+		g.typeCodes = nil
+		g.curTypeCode = nil
+	}()
+
+	if err := g.prepare(); err != nil {
+		return nil, err
+	}
+	if err := g.genTypes(); err != nil {
+		return nil, err
+	}
+
+	// Map every generated type to the files that need it, not looking past
+	// other requested types which have files of their own.
+	users := make(map[reflect.Type]map[string]bool)
+	owners := make(map[string]reflect.Type)
+	for _, t := range g.typeCodeOrder {
+		if !g.isRequested(t) {
+			continue
+		}
+		if t.Name() == "" {
+			return nil, fmt.Errorf("cannot generate a separate file for unnamed type %v", t)
+		}
+		name := splitFileName(t)
+		if name == SharedFileName {
+			return nil, fmt.Errorf("file name %v for type %v is reserved for shared code", name, t)
+		}
+		if t1, ok := owners[name]; ok {
+			return nil, fmt.Errorf("types %v and %v map to the same file %v", t1, t, name)
+		}
+		owners[name] = t
+
+		queue := []reflect.Type{t}
+		for len(queue) > 0 {
+			t1 := queue[0]
+			queue = queue[1:]
+			if users[t1] == nil {
+				users[t1] = make(map[string]bool)
+			}
+			if users[t1][name] {
+				continue
+			}
+			users[t1][name] = true
+			for dep := range g.typeCodes[t1].deps {
+				if dep != t1 && !g.isRequested(dep) {
+					queue = append(queue, dep)
+				}
+			}
+		}
+	}
+
+	var names []string
+	files := make(map[string][]*typeCode)
+	for _, t := range g.typeCodeOrder {
+		name := SharedFileName
+		if g.isRequested(t) {
+			name = splitFileName(t)
+		} else if len(users[t]) == 1 {
+			for user := range users[t] {
+				name = user
+			}
+		}
+		if files[name] == nil {
+			names = append(names, name)
+		}
+		files[name] = append(files[name], g.typeCodes[t])
+	}
+	if g.boolDecodeMapUsed {
+		c := &typeCode{}
+		g.genBoolDecodeMap(&c.code)
+		if files[SharedFileName] == nil {
+			names = append(names, SharedFileName)
+		}
+		files[SharedFileName] = append(files[SharedFileName], c)
+	}
+
+	ret := make(map[string][]byte, len(files))
+	for _, name := range names {
+		imports := make(map[string]string)
+		for _, pkg := range []string{g.writerPkg, g.lexerPkg, pkgEasyJSON} {
+			imports[pkg] = g.imports[pkg]
+		}
+		for _, c := range files[name] {
+			for pkg := range c.imports {
+				imports[pkg] = g.imports[pkg]
+			}
+		}
+
+		var out bytes.Buffer
+		g.printHeader(&out, imports)
+		for _, c := range files[name] {
+			out.Write(c.code.Bytes())
+		}
+		data, err := g.formatSource(out.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", name, err)
+		}
+		ret[name] = data
+	}
+	return ret, nil
+}
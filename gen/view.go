@@ -0,0 +1,110 @@
+package gen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// defaultViewTagKey is the struct tag key listing the views of a field if none
+// is given to AddView.
+const defaultViewTagKey = "view"
+
+// view is a subset of the fields of a struct type, encoded by its own method.
+type view struct {
+	name   string
+	tagKey string
+}
+
+// AddView requests to generate a MarshalJSON<Name> method for the struct type of
+// given object, e.g. MarshalJSONAdmin for the view "admin", which writes only the
+// fields listing the view in their tagKey tag, like `view:"public,admin"`, with
+// the options of MarshalJSON. The values of the fields are encoded as usual,
+// views apply to the fields of the type itself only. An empty tagKey stands for
+// "view".
+func (g *Generator) AddView(obj interface{}, name, tagKey string) {
+	if tagKey == "" {
+		tagKey = defaultViewTagKey
+	}
+	t := objType(obj)
+	g.addType(t)
+	g.views[t] = append(g.views[t], view{name: name, tagKey: tagKey})
+}
+
+// has returns whether the field f is in the view.
+func (v view) has(f reflect.StructField) bool {
+	for _, name := range strings.Split(f.Tag.Get(v.tagKey), ",") {
+		if strings.TrimSpace(name) == v.name {
+			return true
+		}
+	}
+	return false
+}
+
+// fields returns the fields of fs in the view.
+func (v view) fields(fs []reflect.StructField) []reflect.StructField {
+	var ret []reflect.StructField
+	for _, f := range fs {
+		if v.has(f) {
+			ret = append(ret, f)
+		}
+	}
+	return ret
+}
+
+// methodName returns the name of the method encoding the view.
+func (v view) methodName() string {
+	return joinFunctionNameParts(true, "MarshalJSON", v.name)
+}
+
+// isIdent returns whether s is a valid Go identifier.
+func isIdent(s string) bool {
+	for i, c := range s {
+		if !unicode.IsLetter(c) && c != '_' && (i == 0 || !unicode.IsDigit(c)) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// genViews generates the encoder funcs and methods for the views of the type t.
+func (g *Generator) genViews(t reflect.Type) error {
+	g.viewsSeen[t] = true
+
+	names := make(map[string]bool)
+	for i := range g.views[t] {
+		v := &g.views[t][i]
+		if t.Kind() != reflect.Struct {
+			return fmt.Errorf("cannot generate view %q for %v, not a struct type", v.name, t)
+		}
+		if !isIdent(v.name) {
+			return fmt.Errorf("invalid name of view %q for %v", v.name, t)
+		}
+		if names[v.name] {
+			return fmt.Errorf("view %q is added for %v twice", v.name, t)
+		}
+		names[v.name] = true
+
+		fname := g.functionName(joinFunctionNameParts(true, "encodeView", v.name), t)
+		if err := g.genStructFieldsEncoder(t, fname, v); err != nil {
+			return err
+		}
+		if !canDeclareMethods(t) {
+			continue
+		}
+
+		fmt.Fprintln(g.out)
+		fmt.Fprintf(g.out, "// %v writes the JSON encoding of the fields of v in the %q view\n", v.methodName(), v.name)
+		fmt.Fprintln(g.out, "func (v "+g.getType(t)+") "+v.methodName()+"() ([]byte, error) {")
+		opts := g.writerOpts()
+		if g.indentPrefix != "" || g.indent != "" {
+			opts = append(opts, fmt.Sprintf("Prefix: %q", g.indentPrefix), fmt.Sprintf("Indent: %q", g.indent))
+		}
+		fmt.Fprintln(g.out, "  w := jwriter.Writer{"+strings.Join(opts, ", ")+"}")
+		fmt.Fprintln(g.out, "  "+fname+"(&w, v)")
+		fmt.Fprintln(g.out, "  return w.BuildBytes()")
+		fmt.Fprintln(g.out, "}")
+	}
+	return nil
+}
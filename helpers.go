@@ -38,11 +38,35 @@ type UnknownsUnmarshaler interface {
 	UnmarshalUnknown(in *jlexer.Lexer, key string)
 }
 
+// UnknownFieldHandler is implemented by types which handle the object keys
+// matching none of their fields themselves, e.g. to log or capture some of them.
+// The generated decoder calls UnknownField for each such key, null values
+// included, with the lexer at the value, which the method must consume, e.g. with
+// in.SkipRecursive(). The key refers to the input, so it must be copied to be
+// kept.
+type UnknownFieldHandler interface {
+	UnknownField(key string, in *jlexer.Lexer)
+}
+
 // UnknownsMarshaler provides a method to write additional struct fields
 type UnknownsMarshaler interface {
 	MarshalUnknowns(w *jwriter.Writer, first bool)
 }
 
+// AfterUnmarshaler is implemented by types which validate or normalize themselves
+// after their fields are decoded. The generated decoder calls AfterUnmarshal if
+// decoding succeeded and reports the error it returns.
+type AfterUnmarshaler interface {
+	AfterUnmarshal() error
+}
+
+// BeforeMarshaler is implemented by types which prepare themselves for encoding.
+// The generated encoder calls BeforeMarshal on the copy of the value it encodes
+// and stops with the error it returns, if any.
+type BeforeMarshaler interface {
+	BeforeMarshal() error
+}
+
 func isNilInterface(i interface{}) bool {
 	return (*[2]uintptr)(unsafe.Pointer(&i))[1] == 0
 }
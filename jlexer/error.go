@@ -8,8 +8,12 @@ type LexerError struct {
 	Reason string
 	Offset int
 	Data   string
+	Path   string // Path to the failed value if tracked, see Lexer.EnterKey.
 }
 
 func (l *LexerError) Error() string {
+	if l.Path != "" {
+		return fmt.Sprintf("parse error: %s at %s near offset %d of '%s'", l.Reason, l.Path, l.Offset, l.Data)
+	}
 	return fmt.Sprintf("parse error: %s near offset %d of '%s'", l.Reason, l.Offset, l.Data)
 }
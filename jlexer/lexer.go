@@ -11,7 +11,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"strconv"
+	"time"
 	"unicode"
 	"unicode/utf16"
 	"unicode/utf8"
@@ -43,7 +45,11 @@ type token struct {
 
 // Lexer is a JSON lexer: it iterates over JSON tokens in a byte slice.
 type Lexer struct {
-	Data []byte // Input data given to the lexer.
+	Data []byte // Input data given to the lexer, or the data read so far by a streaming lexer.
+
+	reader  io.Reader // Input of a streaming lexer, nil if all the input is in Data.
+	readErr error     // Error the reader returned, io.EOF once all of the input is read.
+	offset  int       // Offset of Data in the input of a streaming lexer.
 
 	start int   // Start of the current token.
 	pos   int   // Current unscanned position in the input stream.
@@ -52,9 +58,139 @@ type Lexer struct {
 	firstElement bool // Whether current element is the first in array or an object.
 	wantSep      byte // A comma or a colon character, which need to occur before a token.
 
-	UseMultipleErrors bool          // If we want to use multiple errors.
-	fatalError        error         // Fatal error occurred during lexing. It is usually a syntax error.
-	multipleErrors    []*LexerError // Semantic errors occurred during lexing. Marshalling will be continued after finding this errors.
+	depth    int // Nesting depth of the values being decoded, see EnterNested.
+	maxDepth int // Limit on depth set by EnterNested, 0 if there is none.
+
+	path []pathElement // Keys and indexes leading to the value being decoded, see EnterKey.
+
+	UseMultipleErrors   bool          // If we want to use multiple errors.
+	UseNumber           bool          // Decode numbers into interface{} values as json.Number rather than float64.
+	LenientTypes        bool          // Accept quoted numbers for numbers and the numbers 0 and 1 for booleans.
+	JSON5               bool          // Accept unquoted identifier keys and trailing commas, as JSON5 does.
+	AcceptQuotedNumbers bool          // Accept numbers and booleans enclosed in string literals, like "5" and "true".
+	MaxInputBytes       int           // Fail on input longer than this many bytes, 0 for no limit.
+	fatalError          error         // Fatal error occurred during lexing. It is usually a syntax error.
+	multipleErrors      []*LexerError // Semantic errors occurred during lexing. Marshalling will be continued after finding this errors.
+}
+
+// streamingBufferSize is the size of the chunks a streaming lexer reads its
+// input in.
+const streamingBufferSize = 4096
+
+// NewStreamingLexer returns a Lexer that reads its input from reader in chunks
+// while it is being parsed, instead of requiring all of it in Data. Only the
+// tokens being parsed are kept in memory.
+func NewStreamingLexer(reader io.Reader) *Lexer {
+	return &Lexer{reader: reader}
+}
+
+// fill reads more of the input of a streaming lexer into Data. It returns false
+// if there is nothing more to read.
+func (r *Lexer) fill() bool {
+	if r.reader == nil || r.readErr != nil || r.overLimit() {
+		return false
+	}
+	if len(r.Data) == cap(r.Data) {
+		// Strings and slices returned by the lexer may point into Data, so the
+		// data from the start of the current token is moved to a new buffer
+		// rather than within the old one.
+		n := len(r.Data) - r.start
+		size := 2 * n
+		if size < streamingBufferSize {
+			size = streamingBufferSize
+		}
+		data := make([]byte, n, size)
+		copy(data, r.Data[r.start:])
+		r.Data = data
+		r.offset += r.start
+		r.pos -= r.start
+		r.start = 0
+	}
+
+	n, err := r.reader.Read(r.Data[len(r.Data):cap(r.Data)])
+	r.Data = r.Data[:len(r.Data)+n]
+	if err != nil {
+		r.readErr = err
+	}
+	return n > 0 || err == nil
+}
+
+// overLimit returns whether more than MaxInputBytes bytes of the input are in
+// Data or were read before it.
+func (r *Lexer) overLimit() bool {
+	return r.MaxInputBytes > 0 && r.offset+len(r.Data) > r.MaxInputBytes
+}
+
+// readError returns the error reading the input of a streaming lexer failed
+// with, if any.
+func (r *Lexer) readError() error {
+	if r.readErr == io.EOF {
+		return nil
+	}
+	return r.readErr
+}
+
+// ensureToken reads the input of a streaming lexer until the next token is in
+// Data as a whole, so that tokens spanning the chunks read are scanned as usual.
+func (r *Lexer) ensureToken() {
+	if r.reader == nil {
+		return
+	}
+
+	inString := false
+	inLiteral := false
+	wasEscape := false
+	for i := r.pos; ; i++ {
+		for i == len(r.Data) {
+			pos := r.pos
+			if !r.fill() {
+				return
+			}
+			i -= pos - r.pos
+		}
+
+		c := r.Data[i]
+		switch {
+		case inString:
+			switch {
+			case wasEscape:
+				wasEscape = false
+			case c == '\\':
+				wasEscape = true
+			case c == '"':
+				return
+			}
+		case inLiteral:
+			if isTokenEnd(c) {
+				return
+			}
+		default:
+			switch c {
+			case ' ', '\t', '\r', '\n', ',', ':':
+			case '"':
+				inString = true
+			case '{', '[', '}', ']':
+				return
+			default:
+				inLiteral = true
+			}
+		}
+	}
+}
+
+// utf8BOM is the UTF-8 encoded byte order mark U+FEFF.
+const utf8BOM = "\xef\xbb\xbf"
+
+// skipBOM skips a byte order mark at the start of the input, which some editors
+// and Windows tools write to UTF-8 files. The whitespace after it is skipped
+// along with the one before any token.
+func (r *Lexer) skipBOM() {
+	for len(r.Data)-r.pos < len(utf8BOM) && r.fill() {
+	}
+	if len(r.Data)-r.pos >= len(utf8BOM) && string(r.Data[r.pos:r.pos+len(utf8BOM)]) == utf8BOM {
+		r.pos += len(utf8BOM)
+		r.start = r.pos
+	}
 }
 
 // FetchToken scans the input for the next token.
@@ -68,15 +204,30 @@ func (r *Lexer) FetchToken() {
 		r.errParse("Unexpected end of data")
 		return
 	}
+	if r.offset+r.pos == 0 {
+		r.skipBOM()
+	}
+	r.ensureToken()
+	if r.overLimit() {
+		r.errParse(fmt.Sprintf("input exceeds the limit of %d bytes", r.MaxInputBytes))
+		return
+	}
+
 	// Determine the type of a token by skipping whitespace and reading the
 	// first character.
+	sawComma := false
 	for _, c := range r.Data[r.pos:] {
+		if r.JSON5 && isIdentChar(c, true) && r.fetchIdentKey() {
+			return
+		}
+
 		switch c {
 		case ':', ',':
 			if r.wantSep == c {
 				r.pos++
 				r.start++
 				r.wantSep = 0
+				sawComma = c == ','
 			} else {
 				r.errSyntax()
 			}
@@ -105,7 +256,7 @@ func (r *Lexer) FetchToken() {
 			return
 
 		case '}', ']':
-			if !r.firstElement && (r.wantSep != ',') {
+			if !r.firstElement && (r.wantSep != ',') && !(r.JSON5 && sawComma) {
 				r.errSyntax()
 			}
 			r.wantSep = 0
@@ -156,10 +307,71 @@ func (r *Lexer) FetchToken() {
 			return
 		}
 	}
-	r.fatalError = io.EOF
+	if err := r.readError(); err != nil {
+		r.fatalError = err
+	} else {
+		r.fatalError = io.EOF
+	}
 	return
 }
 
+// isIdentChar returns whether c can be a part of an unquoted JSON5 key, or its
+// first char if first is set. Only ASCII identifiers are supported.
+func isIdentChar(c byte, first bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_', c == '$':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
+
+// fetchIdentKey fetches an identifier followed by a colon at the current position
+// as the string token of an unquoted key, and returns whether there was one.
+// Identifiers elsewhere, such as the null, true and false keywords, are left to
+// be scanned as usual.
+func (r *Lexer) fetchIdentKey() bool {
+	end := r.pos
+	for ; ; end++ {
+		for end == len(r.Data) {
+			pos := r.pos
+			if !r.fill() {
+				return false
+			}
+			end -= pos - r.pos
+		}
+		if !isIdentChar(r.Data[end], end == r.pos) {
+			break
+		}
+	}
+	for i := end; ; i++ {
+		for i == len(r.Data) {
+			pos := r.pos
+			if !r.fill() {
+				return false
+			}
+			i -= pos - r.pos
+			end -= pos - r.pos
+		}
+		c := r.Data[i]
+		if c == ':' {
+			break
+		}
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			return false
+		}
+	}
+
+	if r.wantSep != 0 {
+		r.errSyntax()
+	}
+	r.token.kind = tokenString
+	r.token.byteValue = r.Data[r.pos:end]
+	r.pos = end
+	return true
+}
+
 // isTokenEnd returns true if the char can follow a non-delimiter token
 func isTokenEnd(c byte) bool {
 	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '[' || c == ']' || c == '{' || c == '}' || c == ',' || c == ':'
@@ -208,38 +420,65 @@ func (r *Lexer) fetchFalse() {
 	}
 }
 
-// fetchNumber scans a number literal token.
+// fetchNumber scans a number literal token, which must follow the JSON grammar as
+// with encoding/json: an optional minus sign, an integer part without leading
+// zeros, then optionally a fraction and an exponent with at least one digit each,
+// like -0.5e+3. Forms like +1, 01, 1. and .5 are syntax errors.
 func (r *Lexer) fetchNumber() {
-	hasE := false
-	afterE := false
-	hasDot := false
-
-	r.pos++
-	for i, c := range r.Data[r.pos:] {
-		switch {
-		case c >= '0' && c <= '9':
-			afterE = false
-		case c == '.' && !hasDot:
-			hasDot = true
-		case (c == 'e' || c == 'E') && !hasE:
-			hasE = true
-			hasDot = true
-			afterE = true
-		case (c == '+' || c == '-') && afterE:
-			afterE = false
-		default:
+	data := r.Data[r.pos:]
+	i := 0
+	if data[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(data) && data[i] == '0':
+		i++
+	case i < len(data) && data[i] >= '1' && data[i] <= '9':
+		i = skipDigits(data, i+1)
+	default:
+		r.pos += i
+		r.errSyntax()
+		return
+	}
+	if i < len(data) && data[i] == '.' {
+		i++
+		if j := skipDigits(data, i); j > i {
+			i = j
+		} else {
 			r.pos += i
-			if !isTokenEnd(c) {
-				r.errSyntax()
-			} else {
-				r.token.byteValue = r.Data[r.start:r.pos]
-			}
+			r.errSyntax()
 			return
 		}
 	}
+	if i < len(data) && (data[i] == 'e' || data[i] == 'E') {
+		i++
+		if i < len(data) && (data[i] == '+' || data[i] == '-') {
+			i++
+		}
+		if j := skipDigits(data, i); j > i {
+			i = j
+		} else {
+			r.pos += i
+			r.errSyntax()
+			return
+		}
+	}
+
+	r.pos += i
+	if r.pos < len(r.Data) && !isTokenEnd(r.Data[r.pos]) {
+		r.errSyntax()
+		return
+	}
+	r.token.byteValue = r.Data[r.start:r.pos]
+}
 
-	r.pos = len(r.Data)
-	r.token.byteValue = r.Data[r.start:]
+// skipDigits returns the index of the first byte of data from i on which is not
+// a decimal digit.
+func skipDigits(data []byte, i int) int {
+	for i < len(data) && data[i] >= '0' && data[i] <= '9' {
+		i++
+	}
+	return i
 }
 
 // findStringLen tries to scan into the string literal for ending quote char to determine required size.
@@ -414,6 +653,12 @@ const maxErrorContextLen = 13
 
 func (r *Lexer) errParse(what string) {
 	if r.fatalError == nil {
+		if err := r.readError(); err != nil {
+			// The input is likely cut short by the failed read.
+			r.fatalError = err
+			return
+		}
+
 		var str string
 		if len(r.Data)-r.pos <= maxErrorContextLen {
 			str = string(r.Data)
@@ -422,12 +667,102 @@ func (r *Lexer) errParse(what string) {
 		}
 		r.fatalError = &LexerError{
 			Reason: what,
-			Offset: r.pos,
+			Offset: r.offset + r.pos,
 			Data:   str,
+			Path:   r.Path(),
 		}
 	}
 }
 
+// EnterNested increments the nesting depth of the value being decoded, letting
+// decoders which recurse into each other limit how deep the input can make them
+// go. If the depth would exceed max, it sets an error and returns false, leaving
+// the depth as is; otherwise the call has to be paired with LeaveNested. The
+// limit also applies to the nested values Interface decodes.
+func (r *Lexer) EnterNested(max int) bool {
+	r.maxDepth = max
+	return r.enterNested()
+}
+
+func (r *Lexer) enterNested() bool {
+	if r.maxDepth > 0 && r.depth >= r.maxDepth {
+		r.errParse(fmt.Sprintf("maximum nesting depth of %d exceeded", r.maxDepth))
+		return false
+	}
+	r.depth++
+	return true
+}
+
+// LeaveNested decrements the nesting depth incremented by EnterNested.
+func (r *Lexer) LeaveNested() {
+	r.depth--
+}
+
+// pathElement is an object key or, if index is not negative, an array index on
+// the path to the value being decoded.
+type pathElement struct {
+	key   string
+	index int
+}
+
+// EnterKey appends the key of the object member being decoded to the path that
+// errors report the location of the failure with. The call has to be paired
+// with LeaveElement. The key may refer to the input, like the names returned by
+// UnsafeFieldName.
+func (r *Lexer) EnterKey(key string) {
+	r.path = append(r.path, pathElement{key: key, index: -1})
+}
+
+// EnterIndex appends the index of the array element being decoded to the path
+// that errors report the location of the failure with. The call has to be
+// paired with LeaveElement.
+func (r *Lexer) EnterIndex(i int) {
+	r.path = append(r.path, pathElement{index: i})
+}
+
+// LeaveElement removes the last key or index added by EnterKey or EnterIndex.
+func (r *Lexer) LeaveElement() {
+	if len(r.path) > 0 {
+		r.path = r.path[:len(r.path)-1]
+	}
+}
+
+// Path returns the path to the value being decoded in JSONPath notation, like
+// "$.items[3].name", or an empty string if neither EnterKey nor EnterIndex was
+// called. Keys which are not identifiers are quoted, like $["a key"].
+func (r *Lexer) Path() string {
+	if len(r.path) == 0 {
+		return ""
+	}
+	buf := []byte{'$'}
+	for _, e := range r.path {
+		switch {
+		case e.index >= 0:
+			buf = append(buf, '[')
+			buf = strconv.AppendInt(buf, int64(e.index), 10)
+			buf = append(buf, ']')
+		case isPathIdent(e.key):
+			buf = append(buf, '.')
+			buf = append(buf, e.key...)
+		default:
+			buf = append(buf, '[')
+			buf = strconv.AppendQuote(buf, e.key)
+			buf = append(buf, ']')
+		}
+	}
+	return string(buf)
+}
+
+// isPathIdent returns whether key can follow a dot in a path.
+func isPathIdent(key string) bool {
+	for i := 0; i < len(key); i++ {
+		if !isIdentChar(key[i], i == 0) {
+			return false
+		}
+	}
+	return key != ""
+}
+
 func (r *Lexer) errSyntax() {
 	r.errParse("syntax error")
 }
@@ -450,7 +785,7 @@ func (r *Lexer) errInvalidToken(expected string) {
 		}
 		r.addNonfatalError(&LexerError{
 			Reason: fmt.Sprintf("expected %s", expected),
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Data:   string(r.Data[r.start:r.pos]),
 		})
 		return
@@ -464,13 +799,14 @@ func (r *Lexer) errInvalidToken(expected string) {
 	}
 	r.fatalError = &LexerError{
 		Reason: fmt.Sprintf("expected %s", expected),
-		Offset: r.pos,
+		Offset: r.offset + r.pos,
 		Data:   str,
+		Path:   r.Path(),
 	}
 }
 
 func (r *Lexer) GetPos() int {
-	return r.pos
+	return r.offset + r.pos
 }
 
 // Delim consumes a token and verifies that it is the given delimiter.
@@ -529,7 +865,6 @@ func (r *Lexer) Skip() {
 func (r *Lexer) SkipRecursive() {
 	r.scanToken()
 	var start, end byte
-	startPos := r.start
 
 	switch r.token.delimValue {
 	case '{':
@@ -547,20 +882,41 @@ func (r *Lexer) SkipRecursive() {
 	inQuotes := false
 	wasEscape := false
 
-	for i, c := range r.Data[r.pos:] {
+	for i := r.pos; ; i++ {
+		for i == len(r.Data) {
+			pos := r.pos
+			if !r.fill() {
+				r.pos = len(r.Data)
+				if err := r.readError(); err != nil {
+					r.fatalError = err
+					return
+				}
+				r.fatalError = &LexerError{
+					Reason: "EOF reached while skipping array/object or token",
+					Offset: r.offset + r.pos,
+					Data:   string(r.Data[r.pos:]),
+					Path:   r.Path(),
+				}
+				return
+			}
+			i -= pos - r.pos
+		}
+
+		c := r.Data[i]
 		switch {
 		case c == start && !inQuotes:
 			level++
 		case c == end && !inQuotes:
 			level--
 			if level == 0 {
-				r.pos += i + 1
-				if !json.Valid(r.Data[startPos:r.pos]) {
+				r.pos = i + 1
+				if !r.valid(r.Data[r.start:r.pos]) {
 					r.pos = len(r.Data)
 					r.fatalError = &LexerError{
 						Reason: "skipped array/object json value is invalid",
-						Offset: r.pos,
+						Offset: r.offset + r.pos,
 						Data:   string(r.Data[r.pos:]),
+						Path:   r.Path(),
 					}
 				}
 				return
@@ -575,11 +931,50 @@ func (r *Lexer) SkipRecursive() {
 		}
 		wasEscape = false
 	}
-	r.pos = len(r.Data)
-	r.fatalError = &LexerError{
-		Reason: "EOF reached while skipping array/object or token",
-		Offset: r.pos,
-		Data:   string(r.Data[r.pos:]),
+}
+
+// valid returns whether the data skipped by SkipRecursive is a single valid
+// value, in the relaxed syntax if JSON5 is set.
+func (r *Lexer) valid(data []byte) bool {
+	if !r.JSON5 {
+		return json.Valid(data)
+	}
+	l := Lexer{Data: data, JSON5: true}
+	l.skipTokens()
+	l.Consumed()
+	return l.Ok()
+}
+
+// skipTokens skips the next value token by token, checking its syntax.
+func (r *Lexer) skipTokens() {
+	r.scanToken()
+	if !r.Ok() {
+		return
+	}
+	if r.token.kind != tokenDelim {
+		r.consume()
+		return
+	}
+
+	switch r.token.delimValue {
+	case '{':
+		r.consume()
+		for !r.IsDelim('}') {
+			r.UnsafeString()
+			r.WantColon()
+			r.skipTokens()
+			r.WantComma()
+		}
+		r.Delim('}')
+	case '[':
+		r.consume()
+		for !r.IsDelim(']') {
+			r.skipTokens()
+			r.WantComma()
+		}
+		r.Delim(']')
+	default:
+		r.errSyntax()
 	}
 }
 
@@ -592,31 +987,58 @@ func (r *Lexer) Raw() []byte {
 	return r.Data[r.start:r.pos]
 }
 
+// RawLexer returns a Lexer decoding data, the value just returned by Raw, again
+// with the options, nesting depth and path of r. The offsets its errors report
+// are the ones in the input of r.
+func (r *Lexer) RawLexer(data []byte) *Lexer {
+	return &Lexer{
+		Data:   data,
+		offset: r.offset + r.start,
+
+		depth:    r.depth,
+		maxDepth: r.maxDepth,
+		path:     append([]pathElement(nil), r.path...),
+
+		UseNumber:           r.UseNumber,
+		LenientTypes:        r.LenientTypes,
+		JSON5:               r.JSON5,
+		AcceptQuotedNumbers: r.AcceptQuotedNumbers,
+	}
+}
+
 // IsStart returns whether the lexer is positioned at the start
 // of an input string.
 func (r *Lexer) IsStart() bool {
-	return r.pos == 0
+	return r.offset+r.pos == 0
 }
 
 // Consumed reads all remaining bytes from the input, publishing an error if
-// there is anything but whitespace remaining.
+// there is anything but whitespace remaining. A streaming lexer stops reading at
+// the first byte that is not whitespace, and drops the whitespace it has read.
 func (r *Lexer) Consumed() {
 	if r.pos > len(r.Data) || !r.Ok() {
 		return
 	}
 
-	for _, c := range r.Data[r.pos:] {
-		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
-			r.AddError(&LexerError{
-				Reason: "invalid character '" + string(c) + "' after top-level value",
-				Offset: r.pos,
-				Data:   string(r.Data[r.pos:]),
-			})
-			return
+	for {
+		for _, c := range r.Data[r.pos:] {
+			if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+				r.AddError(&LexerError{
+					Reason: "invalid character '" + string(c) + "' after top-level value",
+					Offset: r.offset + r.pos,
+					Data:   string(r.Data[r.pos:]),
+				})
+				return
+			}
+			r.pos++
+		}
+		r.start = r.pos
+		if !r.fill() {
+			break
 		}
-
-		r.pos++
-		r.start++
+	}
+	if err := r.readError(); err != nil {
+		r.AddError(err)
 	}
 }
 
@@ -729,11 +1151,25 @@ func (r *Lexer) Bytes() []byte {
 	return ret[:n]
 }
 
-// Bool reads a true or false boolean keyword.
+// Bool reads a true or false boolean keyword, or a 0 or 1 number if LenientTypes
+// is set, or a "true" or "false" string literal if AcceptQuotedNumbers is set.
 func (r *Lexer) Bool() bool {
 	if r.token.kind == tokenUndef && r.Ok() {
 		r.FetchToken()
 	}
+	if r.AcceptQuotedNumbers && r.Ok() && r.token.kind == tokenString {
+		return r.BoolStr()
+	}
+	if r.LenientTypes && r.Ok() && r.token.kind == tokenNumber {
+		switch string(r.token.byteValue) {
+		case "0":
+			r.consume()
+			return false
+		case "1":
+			r.consume()
+			return true
+		}
+	}
 	if !r.Ok() || r.token.kind != tokenBool {
 		r.errInvalidToken("bool")
 		return false
@@ -743,10 +1179,76 @@ func (r *Lexer) Bool() bool {
 	return ret
 }
 
+// BoolStr reads a boolean value enclosed in a string literal, i.e. "true" or "false".
+func (r *Lexer) BoolStr() bool {
+	s, b := r.unsafeString(false)
+	if !r.Ok() {
+		return false
+	}
+
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	r.addNonfatalError(&LexerError{
+		Offset: r.offset + r.start,
+		Reason: "invalid bool string",
+		Data:   string(b),
+	})
+	return false
+}
+
+// BoolMapped reads a boolean value like Bool does, also accepting the strings and
+// numbers whose text is a key of values, like "Y" or 0, as the bools they map
+// to. Other strings are only accepted as with BoolStr if AcceptQuotedNumbers is
+// set.
+func (r *Lexer) BoolMapped(values map[string]bool) bool {
+	if r.token.kind == tokenUndef && r.Ok() {
+		r.FetchToken()
+	}
+	if r.Ok() && r.token.kind == tokenNumber {
+		if v, ok := values[string(r.token.byteValue)]; ok {
+			r.consume()
+			return v
+		}
+	}
+	if !r.Ok() || r.token.kind != tokenString {
+		return r.Bool()
+	}
+
+	s, b := r.unsafeString(false)
+	if !r.Ok() {
+		return false
+	}
+	if v, ok := values[s]; ok {
+		return v
+	}
+	if r.AcceptQuotedNumbers {
+		switch s {
+		case "true":
+			return true
+		case "false":
+			return false
+		}
+	}
+	r.addNonfatalError(&LexerError{
+		Offset: r.offset + r.start,
+		Reason: "invalid bool string",
+		Data:   string(b),
+	})
+	return false
+}
+
 func (r *Lexer) number() string {
 	if r.token.kind == tokenUndef && r.Ok() {
 		r.FetchToken()
 	}
+	if (r.LenientTypes || r.AcceptQuotedNumbers) && r.Ok() && r.token.kind == tokenString {
+		s, _ := r.unsafeString(false)
+		return s
+	}
 	if !r.Ok() || r.token.kind != tokenNumber {
 		r.errInvalidToken("number")
 		return ""
@@ -765,7 +1267,7 @@ func (r *Lexer) Uint8() uint8 {
 	n, err := strconv.ParseUint(s, 10, 8)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   s,
 		})
@@ -782,7 +1284,7 @@ func (r *Lexer) Uint16() uint16 {
 	n, err := strconv.ParseUint(s, 10, 16)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   s,
 		})
@@ -799,7 +1301,7 @@ func (r *Lexer) Uint32() uint32 {
 	n, err := strconv.ParseUint(s, 10, 32)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   s,
 		})
@@ -816,7 +1318,7 @@ func (r *Lexer) Uint64() uint64 {
 	n, err := strconv.ParseUint(s, 10, 64)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   s,
 		})
@@ -825,7 +1327,37 @@ func (r *Lexer) Uint64() uint64 {
 }
 
 func (r *Lexer) Uint() uint {
-	return uint(r.Uint64())
+	s := r.number()
+	if !r.Ok() {
+		return 0
+	}
+
+	n, err := strconv.ParseUint(s, 10, strconv.IntSize)
+	if err != nil {
+		r.addNonfatalError(&LexerError{
+			Offset: r.offset + r.start,
+			Reason: err.Error(),
+			Data:   s,
+		})
+	}
+	return uint(n)
+}
+
+func (r *Lexer) Uintptr() uintptr {
+	s := r.number()
+	if !r.Ok() {
+		return 0
+	}
+
+	n, err := strconv.ParseUint(s, 10, strconv.IntSize)
+	if err != nil {
+		r.addNonfatalError(&LexerError{
+			Offset: r.offset + r.start,
+			Reason: err.Error(),
+			Data:   s,
+		})
+	}
+	return uintptr(n)
 }
 
 func (r *Lexer) Int8() int8 {
@@ -837,7 +1369,7 @@ func (r *Lexer) Int8() int8 {
 	n, err := strconv.ParseInt(s, 10, 8)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   s,
 		})
@@ -854,7 +1386,7 @@ func (r *Lexer) Int16() int16 {
 	n, err := strconv.ParseInt(s, 10, 16)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   s,
 		})
@@ -871,7 +1403,7 @@ func (r *Lexer) Int32() int32 {
 	n, err := strconv.ParseInt(s, 10, 32)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   s,
 		})
@@ -888,7 +1420,7 @@ func (r *Lexer) Int64() int64 {
 	n, err := strconv.ParseInt(s, 10, 64)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   s,
 		})
@@ -897,7 +1429,20 @@ func (r *Lexer) Int64() int64 {
 }
 
 func (r *Lexer) Int() int {
-	return int(r.Int64())
+	s := r.number()
+	if !r.Ok() {
+		return 0
+	}
+
+	n, err := strconv.ParseInt(s, 10, strconv.IntSize)
+	if err != nil {
+		r.addNonfatalError(&LexerError{
+			Offset: r.offset + r.start,
+			Reason: err.Error(),
+			Data:   s,
+		})
+	}
+	return int(n)
 }
 
 func (r *Lexer) Uint8Str() uint8 {
@@ -909,7 +1454,7 @@ func (r *Lexer) Uint8Str() uint8 {
 	n, err := strconv.ParseUint(s, 10, 8)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   string(b),
 		})
@@ -926,7 +1471,7 @@ func (r *Lexer) Uint16Str() uint16 {
 	n, err := strconv.ParseUint(s, 10, 16)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   string(b),
 		})
@@ -943,7 +1488,7 @@ func (r *Lexer) Uint32Str() uint32 {
 	n, err := strconv.ParseUint(s, 10, 32)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   string(b),
 		})
@@ -960,7 +1505,7 @@ func (r *Lexer) Uint64Str() uint64 {
 	n, err := strconv.ParseUint(s, 10, 64)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   string(b),
 		})
@@ -969,11 +1514,37 @@ func (r *Lexer) Uint64Str() uint64 {
 }
 
 func (r *Lexer) UintStr() uint {
-	return uint(r.Uint64Str())
+	s, b := r.unsafeString(false)
+	if !r.Ok() {
+		return 0
+	}
+
+	n, err := strconv.ParseUint(s, 10, strconv.IntSize)
+	if err != nil {
+		r.addNonfatalError(&LexerError{
+			Offset: r.offset + r.start,
+			Reason: err.Error(),
+			Data:   string(b),
+		})
+	}
+	return uint(n)
 }
 
 func (r *Lexer) UintptrStr() uintptr {
-	return uintptr(r.Uint64Str())
+	s, b := r.unsafeString(false)
+	if !r.Ok() {
+		return 0
+	}
+
+	n, err := strconv.ParseUint(s, 10, strconv.IntSize)
+	if err != nil {
+		r.addNonfatalError(&LexerError{
+			Offset: r.offset + r.start,
+			Reason: err.Error(),
+			Data:   string(b),
+		})
+	}
+	return uintptr(n)
 }
 
 func (r *Lexer) Int8Str() int8 {
@@ -985,7 +1556,7 @@ func (r *Lexer) Int8Str() int8 {
 	n, err := strconv.ParseInt(s, 10, 8)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   string(b),
 		})
@@ -1002,7 +1573,7 @@ func (r *Lexer) Int16Str() int16 {
 	n, err := strconv.ParseInt(s, 10, 16)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   string(b),
 		})
@@ -1019,7 +1590,7 @@ func (r *Lexer) Int32Str() int32 {
 	n, err := strconv.ParseInt(s, 10, 32)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   string(b),
 		})
@@ -1036,7 +1607,7 @@ func (r *Lexer) Int64Str() int64 {
 	n, err := strconv.ParseInt(s, 10, 64)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   string(b),
 		})
@@ -1045,7 +1616,20 @@ func (r *Lexer) Int64Str() int64 {
 }
 
 func (r *Lexer) IntStr() int {
-	return int(r.Int64Str())
+	s, b := r.unsafeString(false)
+	if !r.Ok() {
+		return 0
+	}
+
+	n, err := strconv.ParseInt(s, 10, strconv.IntSize)
+	if err != nil {
+		r.addNonfatalError(&LexerError{
+			Offset: r.offset + r.start,
+			Reason: err.Error(),
+			Data:   string(b),
+		})
+	}
+	return int(n)
 }
 
 func (r *Lexer) Float32() float32 {
@@ -1057,7 +1641,7 @@ func (r *Lexer) Float32() float32 {
 	n, err := strconv.ParseFloat(s, 32)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   s,
 		})
@@ -1073,7 +1657,7 @@ func (r *Lexer) Float32Str() float32 {
 	n, err := strconv.ParseFloat(s, 32)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   string(b),
 		})
@@ -1090,7 +1674,7 @@ func (r *Lexer) Float64() float64 {
 	n, err := strconv.ParseFloat(s, 64)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   s,
 		})
@@ -1106,7 +1690,7 @@ func (r *Lexer) Float64Str() float64 {
 	n, err := strconv.ParseFloat(s, 64)
 	if err != nil {
 		r.addNonfatalError(&LexerError{
-			Offset: r.start,
+			Offset: r.offset + r.start,
 			Reason: err.Error(),
 			Data:   string(b),
 		})
@@ -1114,6 +1698,63 @@ func (r *Lexer) Float64Str() float64 {
 	return n
 }
 
+// Time reads a string literal and parses it as a time according to layout.
+func (r *Lexer) Time(layout string) time.Time {
+	s := r.String()
+	if !r.Ok() {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		r.addNonfatalError(&LexerError{
+			Offset: r.offset + r.start,
+			Reason: err.Error(),
+			Data:   s,
+		})
+	}
+	return t
+}
+
+// UnixTime reads a time.Time in UTC from an integer number of seconds since the
+// Unix epoch.
+func (r *Lexer) UnixTime() time.Time {
+	sec := r.Int64()
+	if !r.Ok() {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
+}
+
+// UnixMilliTime reads a time.Time in UTC from an integer number of milliseconds
+// since the Unix epoch.
+func (r *Lexer) UnixMilliTime() time.Time {
+	ms := r.Int64()
+	if !r.Ok() {
+		return time.Time{}
+	}
+	return time.Unix(ms/1000, ms%1000*int64(time.Millisecond)).UTC()
+}
+
+// Duration reads a time.Duration from a string literal in the format of
+// time.ParseDuration, e.g. "1h30m".
+func (r *Lexer) Duration() time.Duration {
+	s := r.String()
+	if !r.Ok() {
+		return 0
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		r.addNonfatalError(&LexerError{
+			Offset: r.offset + r.start,
+			Reason: err.Error(),
+			Data:   s,
+		})
+	}
+	return d
+}
+
 func (r *Lexer) Error() error {
 	return r.fatalError
 }
@@ -1126,13 +1767,14 @@ func (r *Lexer) AddError(e error) {
 
 func (r *Lexer) AddNonFatalError(e error) {
 	r.addNonfatalError(&LexerError{
-		Offset: r.start,
+		Offset: r.offset + r.start,
 		Data:   string(r.Data[r.start:r.pos]),
 		Reason: e.Error(),
 	})
 }
 
 func (r *Lexer) addNonfatalError(err *LexerError) {
+	err.Path = r.Path()
 	if r.UseMultipleErrors {
 		// We don't want to add errors with the same offset.
 		if len(r.multipleErrors) != 0 && r.multipleErrors[len(r.multipleErrors)-1].Offset == err.Offset {
@@ -1173,6 +1815,46 @@ func (r *Lexer) JsonNumber() json.Number {
 	}
 }
 
+// BigInt reads a number into v from its text, keeping all of its digits. Numbers
+// which are not integers are errors.
+func (r *Lexer) BigInt(v *big.Int) {
+	s := r.number()
+	if !r.Ok() {
+		return
+	}
+	if _, ok := v.SetString(s, 10); !ok {
+		r.addNonfatalError(&LexerError{
+			Offset: r.offset + r.start,
+			Reason: "invalid big.Int value",
+			Data:   s,
+		})
+	}
+}
+
+// BigFloat reads a number into v from its text, without going through a float64.
+// If v has no precision yet, it gets enough to keep all the digits of the number.
+func (r *Lexer) BigFloat(v *big.Float) {
+	s := r.number()
+	if !r.Ok() {
+		return
+	}
+	if v.Prec() == 0 {
+		// Each decimal digit takes less than 4 bits.
+		prec := uint(len(s)) * 4
+		if prec < 64 {
+			prec = 64
+		}
+		v.SetPrec(prec)
+	}
+	if _, ok := v.SetString(s); !ok {
+		r.addNonfatalError(&LexerError{
+			Offset: r.offset + r.start,
+			Reason: "invalid big.Float value",
+			Data:   s,
+		})
+	}
+}
+
 // Interface fetches an interface{} analogous to the 'encoding/json' package.
 func (r *Lexer) Interface() interface{} {
 	if r.token.kind == tokenUndef && r.Ok() {
@@ -1186,6 +1868,9 @@ func (r *Lexer) Interface() interface{} {
 	case tokenString:
 		return r.String()
 	case tokenNumber:
+		if r.UseNumber {
+			return json.Number(r.Raw())
+		}
 		return r.Float64()
 	case tokenBool:
 		return r.Bool()
@@ -1194,6 +1879,11 @@ func (r *Lexer) Interface() interface{} {
 		return nil
 	}
 
+	if !r.enterNested() {
+		return nil
+	}
+	defer r.LeaveNested()
+
 	if r.token.delimValue == '{' {
 		r.consume()
 
@@ -3,8 +3,14 @@ package jlexer
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
 	"reflect"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 )
 
 func TestString(t *testing.T) {
@@ -123,12 +129,36 @@ func TestNumber(t *testing.T) {
 		{toParse: "12.35e-15", want: "12.35e-15"},
 		{toParse: "12.35E-15", want: "12.35E-15"},
 		{toParse: "12.35E15", want: "12.35E15"},
+		{toParse: "0", want: "0"},
+		{toParse: "-0", want: "-0"},
+		{toParse: "0.5", want: "0.5"},
+		{toParse: "1e3", want: "1e3"},
+		{toParse: "1E3", want: "1E3"},
+		{toParse: "1.5e-2", want: "1.5e-2"},
+		{toParse: "0e0", want: "0e0"},
+		{toParse: "-0.0E+00", want: "-0.0E+00"},
 
 		{toParse: `"a"`, wantError: true},
 		{toParse: "123junk", wantError: true},
 		{toParse: "1.2.3", wantError: true},
 		{toParse: "1e2e3", wantError: true},
 		{toParse: "1e2.3", wantError: true},
+		{toParse: "+5", wantError: true},
+		{toParse: "01", wantError: true},
+		{toParse: "-01", wantError: true},
+		{toParse: "00", wantError: true},
+		{toParse: "-", wantError: true},
+		{toParse: "-a", wantError: true},
+		{toParse: ".5", wantError: true},
+		{toParse: "-.5", wantError: true},
+		{toParse: "1.", wantError: true},
+		{toParse: "1.e3", wantError: true},
+		{toParse: "1e", wantError: true},
+		{toParse: "1e+", wantError: true},
+		{toParse: "1e-", wantError: true},
+		{toParse: "1e+-2", wantError: true},
+		{toParse: "0x10", wantError: true},
+		{toParse: "1_000", wantError: true},
 	} {
 		l := Lexer{Data: []byte(test.toParse)}
 
@@ -145,6 +175,41 @@ func TestNumber(t *testing.T) {
 	}
 }
 
+// TestNumberGrammar checks that number literals are accepted exactly when
+// encoding/json accepts them, in arrays and objects too.
+func TestNumberGrammar(t *testing.T) {
+	for _, num := range []string{
+		"0", "-0", "7", "-7", "10", "123456789", "0.0", "0.5", "-1.25", "1e3", "1E3", "1e+3", "1e-3",
+		"1.5e-2", "-0.0E+00", "1E03", "+5", "+0", "01", "-01", "00", "0.", "1.", ".5", "-.5", "-",
+		"1.e3", "1e", "1E", "1e+", "1e-", "1e+-2", "1ee2", "0x10", "1_000", "1,5", "- 1", "0-1",
+	} {
+		for _, data := range []string{num, "[" + num + "]", `{"a":` + num + "}", "[" + num + ",1]"} {
+			want := json.Valid([]byte(data))
+
+			l := Lexer{Data: []byte(data)}
+			l.Interface()
+			l.Consumed()
+			if got := l.Error() == nil; got != want {
+				t.Errorf("Interface() of %s ok = %v; want %v (error %v)", data, got, want, l.Error())
+			}
+
+			l = Lexer{Data: []byte(data)}
+			l.SkipRecursive()
+			l.Consumed()
+			if got := l.Error() == nil; got != want {
+				t.Errorf("SkipRecursive() of %s ok = %v; want %v (error %v)", data, got, want, l.Error())
+			}
+
+			streaming := NewStreamingLexer(iotest.OneByteReader(strings.NewReader(data)))
+			streaming.Interface()
+			streaming.Consumed()
+			if got := streaming.Error() == nil; got != want {
+				t.Errorf("streaming Interface() of %s ok = %v; want %v (error %v)", data, got, want, streaming.Error())
+			}
+		}
+	}
+}
+
 func TestBool(t *testing.T) {
 	for i, test := range []struct {
 		toParse   string
@@ -175,6 +240,113 @@ func TestBool(t *testing.T) {
 	}
 }
 
+func TestBoolMapped(t *testing.T) {
+	values := map[string]bool{"Y": true, "N": false, "1": true, "0": false}
+	for i, test := range []struct {
+		toParse   string
+		quoted    bool
+		want      bool
+		wantError bool
+	}{
+		{toParse: "true", want: true},
+		{toParse: "false", want: false},
+		{toParse: `"Y"`, want: true},
+		{toParse: `"\u004e"`, want: false},
+		{toParse: "1", want: true},
+		{toParse: "0", want: false},
+		{toParse: `"1"`, want: true},
+		{toParse: `"true"`, quoted: true, want: true},
+
+		{toParse: `"true"`, wantError: true},
+		{toParse: `"y"`, wantError: true},
+		{toParse: "2", wantError: true},
+		{toParse: "1.0", wantError: true},
+		{toParse: "null", wantError: true},
+	} {
+		l := Lexer{Data: []byte(test.toParse), AcceptQuotedNumbers: test.quoted}
+
+		got := l.BoolMapped(values)
+		if got != test.want {
+			t.Errorf("[%d, %q] BoolMapped() = %v; want %v", i, test.toParse, got, test.want)
+		}
+		err := l.Error()
+		if err != nil && !test.wantError {
+			t.Errorf("[%d, %q] BoolMapped() error: %v", i, test.toParse, err)
+		} else if err == nil && test.wantError {
+			t.Errorf("[%d, %q] BoolMapped() ok; want error", i, test.toParse)
+		}
+	}
+}
+
+func TestLenientTypes(t *testing.T) {
+	for i, test := range []struct {
+		toParse string
+		decode  func(l *Lexer) interface{}
+		want    interface{}
+
+		// Whether decoding fails with LenientTypes set; it always fails without.
+		wantError bool
+	}{
+		{toParse: `"42"`, decode: func(l *Lexer) interface{} { return l.Int() }, want: 42},
+		{toParse: `"-7"`, decode: func(l *Lexer) interface{} { return l.Int64() }, want: int64(-7)},
+		{toParse: `"255"`, decode: func(l *Lexer) interface{} { return l.Uint8() }, want: uint8(255)},
+		{toParse: `"2.5"`, decode: func(l *Lexer) interface{} { return l.Float64() }, want: 2.5},
+		{toParse: `"1e3"`, decode: func(l *Lexer) interface{} { return l.Float32() }, want: float32(1000)},
+		{toParse: `1`, decode: func(l *Lexer) interface{} { return l.Bool() }, want: true},
+		{toParse: `0`, decode: func(l *Lexer) interface{} { return l.Bool() }, want: false},
+
+		{toParse: `"4x"`, decode: func(l *Lexer) interface{} { return l.Int() }, want: 0, wantError: true},
+		{toParse: `"256"`, decode: func(l *Lexer) interface{} { return l.Uint8() }, want: uint8(255), wantError: true},
+		{toParse: `2`, decode: func(l *Lexer) interface{} { return l.Bool() }, want: false, wantError: true},
+		{toParse: `"true"`, decode: func(l *Lexer) interface{} { return l.Bool() }, want: false, wantError: true},
+	} {
+		l := Lexer{Data: []byte(test.toParse), LenientTypes: true, UseMultipleErrors: true}
+		got := test.decode(&l)
+		if got != test.want {
+			t.Errorf("[%d, %q] lenient decoding = %v; want %v", i, test.toParse, got, test.want)
+		}
+		failed := l.Error() != nil || len(l.GetNonFatalErrors()) > 0
+		if failed != test.wantError {
+			t.Errorf("[%d, %q] lenient decoding failed: %v; want %v", i, test.toParse, failed, test.wantError)
+		}
+
+		l = Lexer{Data: []byte(test.toParse), UseMultipleErrors: true}
+		test.decode(&l)
+		if l.Error() == nil && len(l.GetNonFatalErrors()) == 0 {
+			t.Errorf("[%d, %q] strict decoding ok; want error", i, test.toParse)
+		}
+	}
+}
+
+func TestBoolStr(t *testing.T) {
+	for i, test := range []struct {
+		toParse   string
+		want      bool
+		wantError bool
+	}{
+		{toParse: `"true"`, want: true},
+		{toParse: `"false"`, want: false},
+
+		{toParse: "true", wantError: true},
+		{toParse: `"1"`, wantError: true},
+		{toParse: `"True"`, wantError: true},
+		{toParse: `""`, wantError: true},
+	} {
+		l := Lexer{Data: []byte(test.toParse)}
+
+		got := l.BoolStr()
+		if got != test.want {
+			t.Errorf("[%d, %q] BoolStr() = %v; want %v", i, test.toParse, got, test.want)
+		}
+		err := l.Error()
+		if err != nil && !test.wantError {
+			t.Errorf("[%d, %q] BoolStr() error: %v", i, test.toParse, err)
+		} else if err == nil && test.wantError {
+			t.Errorf("[%d, %q] BoolStr() ok; want error", i, test.toParse)
+		}
+	}
+}
+
 func TestSkipRecursive(t *testing.T) {
 	for i, test := range []struct {
 		toParse   string
@@ -269,6 +441,126 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+func TestInterfaceUseNumber(t *testing.T) {
+	for i, test := range []struct {
+		toParse string
+		want    interface{}
+	}{
+		{toParse: "5", want: json.Number("5")},
+		{toParse: "-1.5e3", want: json.Number("-1.5e3")},
+		{toParse: "123456789012345678", want: json.Number("123456789012345678")},
+		{toParse: `"5"`, want: "5"},
+		{toParse: `{"a": [1, 2.5]}`, want: map[string]interface{}{"a": []interface{}{json.Number("1"), json.Number("2.5")}}},
+	} {
+		l := Lexer{Data: []byte(test.toParse), UseNumber: true}
+
+		got := l.Interface()
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("[%d, %q] Interface() = %#v; want %#v", i, test.toParse, got, test.want)
+		}
+		if err := l.Error(); err != nil {
+			t.Errorf("[%d, %q] Interface() error: %v", i, test.toParse, err)
+		}
+	}
+}
+
+func TestJSON5(t *testing.T) {
+	for i, test := range []struct {
+		toParse   string
+		want      interface{}
+		wantError bool
+	}{
+		{toParse: `{a:1, b:2,}`, want: map[string]interface{}{"a": float64(1), "b": float64(2)}},
+		{toParse: `[1,2,]`, want: []interface{}{float64(1), float64(2)}},
+		{toParse: `{ $id_1 : [true ,] , "q":null,}`, want: map[string]interface{}{"$id_1": []interface{}{true}, "q": nil}},
+		{toParse: `{null: true, false :1}`, want: map[string]interface{}{"null": true, "false": float64(1)}},
+
+		{toParse: `[,]`, wantError: true},
+		{toParse: `[1,,]`, wantError: true},
+		{toParse: `{,}`, wantError: true},
+		{toParse: `{a}`, wantError: true},
+		{toParse: `{a:}`, wantError: true},
+		{toParse: `{a:1 b:2}`, wantError: true},
+		{toParse: `{1a:2}`, wantError: true},
+		{toParse: `[a]`, wantError: true},
+	} {
+		l := Lexer{Data: []byte(test.toParse), JSON5: true}
+		got := l.Interface()
+		l.Consumed()
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("[%d, %q] Interface() = %v; want %v", i, test.toParse, got, test.want)
+		}
+		if err := l.Error(); err != nil && !test.wantError {
+			t.Errorf("[%d, %q] Interface() error: %v", i, test.toParse, err)
+		} else if err == nil && test.wantError {
+			t.Errorf("[%d, %q] Interface() ok; want error", i, test.toParse)
+		}
+
+		streaming := NewStreamingLexer(iotest.OneByteReader(strings.NewReader(test.toParse)))
+		streaming.JSON5 = true
+		if got := streaming.Interface(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("[%d, %q] streaming Interface() = %v; want %v", i, test.toParse, got, test.want)
+		}
+
+		l = Lexer{Data: []byte(test.toParse), JSON5: true}
+		l.SkipRecursive()
+		l.Consumed()
+		if (l.Error() != nil) != test.wantError {
+			t.Errorf("[%d, %q] SkipRecursive() error: %v; want error %v", i, test.toParse, l.Error(), test.wantError)
+		}
+
+		l = Lexer{Data: []byte(test.toParse)}
+		l.Interface()
+		if l.Error() == nil {
+			t.Errorf("[%d, %q] strict Interface() ok; want error", i, test.toParse)
+		}
+	}
+}
+
+func TestEnterNested(t *testing.T) {
+	l := Lexer{Data: []byte(`[[1]]`)}
+	if !l.EnterNested(2) || !l.EnterNested(2) {
+		t.Fatalf("EnterNested(2) failed within the limit: %v", l.Error())
+	}
+	if l.EnterNested(2) {
+		t.Errorf("EnterNested(2) succeeded at depth 2")
+	}
+	if l.Error() == nil {
+		t.Errorf("EnterNested(2) set no error at depth 2")
+	}
+
+	l = Lexer{Data: []byte(`[[1]]`)}
+	for i := 0; i < 3; i++ {
+		l.EnterNested(3)
+		l.LeaveNested()
+	}
+	if err := l.Error(); err != nil {
+		t.Errorf("EnterNested(3) error after LeaveNested: %v", err)
+	}
+}
+
+func TestInterfaceMaxDepth(t *testing.T) {
+	for i, test := range []struct {
+		toParse   string
+		wantError bool
+	}{
+		{toParse: `[[1]]`},
+		{toParse: `{"a": [1]}`},
+		{toParse: `[[[1]]]`, wantError: true},
+		{toParse: `{"a": {"b": {}}}`, wantError: true},
+		{toParse: `[1, 2, [3], [4]]`},
+	} {
+		l := Lexer{Data: []byte(test.toParse)}
+		l.EnterNested(3)
+		l.Interface()
+		l.LeaveNested()
+
+		if err := l.Error(); (err != nil) != test.wantError {
+			t.Errorf("[%d, %q] Interface() error = %v; want error %v", i, test.toParse, err, test.wantError)
+		}
+	}
+}
+
 func TestConsumed(t *testing.T) {
 	for i, test := range []struct {
 		toParse   string
@@ -293,6 +585,43 @@ func TestConsumed(t *testing.T) {
 	}
 }
 
+func TestLeadingBOM(t *testing.T) {
+	for i, test := range []struct {
+		toParse   string
+		want      interface{}
+		wantError bool
+	}{
+		{toParse: "\uFEFF{}", want: map[string]interface{}{}},
+		{toParse: "\uFEFF \n\t[1]", want: []interface{}{float64(1)}},
+		{toParse: "\t\n\r {\"a\":1}\n", want: map[string]interface{}{"a": float64(1)}},
+		{toParse: "\uFEFF\"\uFEFF\"", want: "\uFEFF"},
+
+		{toParse: "\uFEFF", wantError: true},
+		{toParse: "\uFEFF\uFEFF{}", wantError: true},
+		{toParse: " \uFEFF{}", wantError: true},
+		{toParse: "[\uFEFF1]", wantError: true},
+	} {
+		l := Lexer{Data: []byte(test.toParse)}
+		got := l.Interface()
+		l.Consumed()
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("[%d, %q] Interface() = %v; want %v", i, test.toParse, got, test.want)
+		}
+		if err := l.Error(); err != nil && !test.wantError {
+			t.Errorf("[%d, %q] Interface() error: %v", i, test.toParse, err)
+		} else if err == nil && test.wantError {
+			t.Errorf("[%d, %q] Interface() ok; want error", i, test.toParse)
+		}
+
+		streaming := NewStreamingLexer(iotest.OneByteReader(strings.NewReader(test.toParse)))
+		got = streaming.Interface()
+		streaming.Consumed()
+		if !reflect.DeepEqual(got, test.want) || (streaming.Error() != nil) != test.wantError {
+			t.Errorf("[%d, %q] streaming Interface() = %v, %v; want %v", i, test.toParse, got, streaming.Error(), test.want)
+		}
+	}
+}
+
 func TestJsonNumber(t *testing.T) {
 	for i, test := range []struct {
 		toParse        string
@@ -355,6 +684,48 @@ func TestJsonNumber(t *testing.T) {
 	}
 }
 
+func TestBigNumbers(t *testing.T) {
+	for i, test := range []struct {
+		toParse      string
+		wantInt      string
+		wantIntError bool
+		wantFloat    string
+		wantError    bool
+	}{
+		{toParse: `0`, wantInt: "0", wantFloat: "0"},
+		{toParse: `-12`, wantInt: "-12", wantFloat: "-12"},
+		{toParse: `123456789012345678901234567890`, wantInt: "123456789012345678901234567890", wantFloat: "1.2345678901234567890123456789e+29"},
+		{toParse: `1.5`, wantIntError: true, wantFloat: "1.5"},
+		{toParse: `25E-4`, wantIntError: true, wantFloat: "0.0025"},
+
+		{toParse: `"12"`, wantError: true},
+		{toParse: `null`, wantError: true},
+		{toParse: `[1]`, wantError: true},
+	} {
+		var n big.Int
+		l := Lexer{Data: []byte(test.toParse)}
+		l.BigInt(&n)
+		if err := l.Error(); err != nil && !test.wantError && !test.wantIntError {
+			t.Errorf("[%d, %q] BigInt() error: %v", i, test.toParse, err)
+		} else if err == nil && (test.wantError || test.wantIntError) {
+			t.Errorf("[%d, %q] BigInt() = %v; want error", i, test.toParse, &n)
+		} else if err == nil && n.String() != test.wantInt {
+			t.Errorf("[%d, %q] BigInt() = %v; want %v", i, test.toParse, &n, test.wantInt)
+		}
+
+		var f big.Float
+		l = Lexer{Data: []byte(test.toParse)}
+		l.BigFloat(&f)
+		if err := l.Error(); err != nil && !test.wantError {
+			t.Errorf("[%d, %q] BigFloat() error: %v", i, test.toParse, err)
+		} else if err == nil && test.wantError {
+			t.Errorf("[%d, %q] BigFloat() = %v; want error", i, test.toParse, &f)
+		} else if got := f.Text('g', -1); err == nil && got != test.wantFloat {
+			t.Errorf("[%d, %q] BigFloat() = %v; want %v", i, test.toParse, got, test.wantFloat)
+		}
+	}
+}
+
 func TestFetchStringUnterminatedString(t *testing.T) {
 	for _, test := range []struct {
 		data []byte
@@ -373,3 +744,254 @@ func TestFetchStringUnterminatedString(t *testing.T) {
 		}
 	}
 }
+
+func TestStreamingLexer(t *testing.T) {
+	long := strings.Repeat(`a\"b`, 3*streamingBufferSize)
+	for i, test := range []string{
+		`{}`,
+		` [1, -2.5e3, true, false, null, "str"] `,
+		`{"a":{"b":[{}, [], "c\\\""]},"d":"\u00e9"}`,
+		`"` + long + `"`,
+		`{"` + long + `":[` + strings.Repeat(`123456789,`, streamingBufferSize) + `0]}`,
+		`[1, 2`,
+		`{"a":tru}`,
+		`"unterminated`,
+		`{} x`,
+	} {
+		want := Lexer{Data: []byte(test)}
+		wantValue := want.Interface()
+		want.Consumed()
+
+		got := NewStreamingLexer(iotest.OneByteReader(strings.NewReader(test)))
+		gotValue := got.Interface()
+		got.Consumed()
+
+		if (got.Error() == nil) != (want.Error() == nil) {
+			t.Errorf("[%d] streaming error: %v; want %v", i, got.Error(), want.Error())
+		}
+		if !reflect.DeepEqual(gotValue, wantValue) {
+			t.Errorf("[%d] streaming Interface() = %.40v; want %.40v", i, gotValue, wantValue)
+		}
+	}
+}
+
+func TestStreamingLexerSkip(t *testing.T) {
+	skipped := `{"b":[1,{"c":"]}"}],"d":[` + strings.Repeat(`"pad",`, streamingBufferSize) + `null]}`
+	data := `{"a":` + skipped + `,"key":` + strings.Repeat(" ", streamingBufferSize) + `"value"}`
+
+	l := NewStreamingLexer(iotest.HalfReader(strings.NewReader(data)))
+	l.Delim('{')
+	if key := l.UnsafeFieldName(false); key != "a" {
+		t.Errorf("UnsafeFieldName() = %q; want %q", key, "a")
+	}
+	l.WantColon()
+	if raw := string(l.Raw()); raw != skipped {
+		t.Errorf("Raw() = %.40q; want %.40q", raw, skipped)
+	}
+	l.WantComma()
+
+	// The key points into the data read, which must stay intact while more
+	// data is read to get the value.
+	key := l.UnsafeFieldName(false)
+	l.WantColon()
+	value := l.String()
+	if key != "key" || value != "value" {
+		t.Errorf("got %q: %q; want %q: %q", key, value, "key", "value")
+	}
+	l.WantComma()
+	l.Delim('}')
+	l.Consumed()
+	if err := l.Error(); err != nil {
+		t.Errorf("Error() = %v", err)
+	}
+}
+
+func TestErrorPath(t *testing.T) {
+	l := Lexer{Data: []byte(`{"items":[1,{"content-type":7}]}`)}
+	if got := l.Path(); got != "" {
+		t.Errorf("Path() = %q before EnterKey; want empty", got)
+	}
+
+	l.Delim('{')
+	key := l.UnsafeFieldName(false)
+	l.WantColon()
+	l.EnterKey(key)
+	l.Delim('[')
+	l.EnterIndex(0)
+	l.Int()
+	l.LeaveElement()
+	l.WantComma()
+	l.EnterIndex(1)
+	l.Delim('{')
+	key = l.UnsafeFieldName(false)
+	l.WantColon()
+	l.EnterKey(key)
+	if got, want := l.Path(), `$.items[1]["content-type"]`; got != want {
+		t.Errorf("Path() = %q; want %q", got, want)
+	}
+
+	_ = l.String()
+	err, ok := l.Error().(*LexerError)
+	if !ok {
+		t.Fatalf("Error() = %v; want *LexerError", l.Error())
+	}
+	if want := `$.items[1]["content-type"]`; err.Path != want {
+		t.Errorf("Error().Path = %q; want %q", err.Path, want)
+	}
+	if want := `parse error: expected string at $.items[1]["content-type"] near offset 29 of '7'`; err.Error() != want {
+		t.Errorf("Error() = %q; want %q", err.Error(), want)
+	}
+
+	l.LeaveElement()
+	l.LeaveElement()
+	l.LeaveElement()
+	l.LeaveElement()
+	if got := l.Path(); got != "" {
+		t.Errorf("Path() = %q after LeaveElement; want empty", got)
+	}
+}
+
+func TestRawLexer(t *testing.T) {
+	l := Lexer{Data: []byte(`{"a": {"n": 1, "s": "x"}}`), UseNumber: true, LenientTypes: true}
+	l.Delim('{')
+	key := l.UnsafeFieldName(false)
+	l.WantColon()
+	l.EnterKey(key)
+	data := l.Raw()
+
+	r := l.RawLexer(data)
+	r.Delim('{')
+	r.UnsafeFieldName(false)
+	r.WantColon()
+	if got := r.Interface(); got != json.Number("1") {
+		t.Errorf("Interface() = %#v; want json.Number(\"1\")", got)
+	}
+	r.WantComma()
+	r.UnsafeFieldName(false)
+	r.WantColon()
+	r.Int()
+	err, ok := r.Error().(*LexerError)
+	if !ok {
+		t.Fatalf("Error() = %v; want *LexerError", r.Error())
+	}
+	if want := `parse error: strconv.ParseInt: parsing "x": invalid syntax at $.a near offset 20 of 'x'`; err.Error() != want {
+		t.Errorf("Error() = %q; want %q", err.Error(), want)
+	}
+	if !l.Ok() {
+		t.Errorf("Error() = %v for the parent lexer; want nil", l.Error())
+	}
+}
+
+func TestStreamingLexerReadError(t *testing.T) {
+	errRead := errors.New("read failed")
+	for i, test := range []string{
+		`{"a":[1,2`,
+		`{"a":"str`,
+		`{"a":{}`,
+		`{"a":1}`,
+	} {
+		l := NewStreamingLexer(iotest.DataErrReader(&errReader{data: test, err: errRead}))
+		l.Interface()
+		l.Consumed()
+		if err := l.Error(); err != errRead {
+			t.Errorf("[%d] Error() = %v; want %v", i, err, errRead)
+		}
+	}
+}
+
+func TestStreamingLexerConsumedOpenReader(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go pw.Write([]byte(`{"a":[1,2]}   x`))
+
+	done := make(chan error)
+	go func() {
+		l := NewStreamingLexer(pr)
+		l.Interface()
+		l.Consumed()
+		done <- l.Error()
+	}()
+	select {
+	case err := <-done:
+		if want := "parse error: invalid character 'x' after top-level value near offset 14 of 'x'"; err == nil || err.Error() != want {
+			t.Errorf("Error() = %v; want %v", err, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Consumed() blocked reading the rest of the input")
+	}
+}
+
+func TestStreamingLexerConsumedWhitespace(t *testing.T) {
+	l := NewStreamingLexer(strings.NewReader("1" + strings.Repeat(" ", 1<<20)))
+	l.Int()
+	l.Consumed()
+	if err := l.Error(); err != nil {
+		t.Errorf("Error() = %v; want nil", err)
+	}
+	if cap(l.Data) > 2*streamingBufferSize {
+		t.Errorf("cap(Data) = %d after trailing whitespace; want at most %d", cap(l.Data), 2*streamingBufferSize)
+	}
+}
+
+// errReader returns data and then err.
+type errReader struct {
+	data string
+	err  error
+}
+
+func (r *errReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestMaxInputBytes(t *testing.T) {
+	const limit = 1 << 20
+	big := `{"a":"` + strings.Repeat("x", 2<<20) + `"}`
+	want := "parse error: input exceeds the limit of 1048576 bytes"
+
+	l := &Lexer{Data: []byte(big), MaxInputBytes: limit}
+	l.Interface()
+	if err := l.Error(); err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("Error() = %v; want %q", err, want)
+	}
+
+	r := &countingReader{r: strings.NewReader(big)}
+	l = NewStreamingLexer(r)
+	l.MaxInputBytes = limit
+	l.Interface()
+	if err := l.Error(); err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("streaming Error() = %v; want %q", err, want)
+	}
+	if r.n > 2*limit {
+		t.Errorf("streaming lexer read %d bytes; want at most %d", r.n, 2*limit)
+	}
+
+	small := `{"a":"` + strings.Repeat("x", 1000) + `"}`
+	l = &Lexer{Data: []byte(small), MaxInputBytes: limit}
+	l.Interface()
+	if err := l.Error(); err != nil {
+		t.Errorf("Error() = %v for input within the limit", err)
+	}
+	l = NewStreamingLexer(strings.NewReader(small))
+	l.MaxInputBytes = limit
+	l.Interface()
+	if err := l.Error(); err != nil {
+		t.Errorf("streaming Error() = %v for input within the limit", err)
+	}
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += n
+	return n, err
+}
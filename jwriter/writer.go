@@ -2,8 +2,17 @@
 package jwriter
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"io"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"reflect"
 	"strconv"
+	"sync"
+	"time"
 	"unicode/utf8"
 
 	"github.com/mailru/easyjson/buffer"
@@ -18,6 +27,16 @@ const (
 	NilSliceAsEmpty                   // Encode nil slice as '[]' rather than 'null'.
 )
 
+// NaNPolicy describes how NaN and infinite float values, which have no JSON
+// representation, are written.
+type NaNPolicy int
+
+const (
+	NaNError  NaNPolicy = iota // Fail with a *json.UnsupportedValueError, as encoding/json does.
+	NaNNull                    // Write null.
+	NaNString                  // Write a "NaN", "+Inf" or "-Inf" string.
+)
+
 // Writer is a JSON writer.
 type Writer struct {
 	Flags Flags
@@ -25,6 +44,90 @@ type Writer struct {
 	Error        error
 	Buffer       buffer.Buffer
 	NoEscapeHTML bool
+	NaNPolicy    NaNPolicy
+
+	// FloatFormat and FloatPrecision are the format and precision floats are
+	// written with, as by strconv.AppendFloat. With the zero FloatFormat floats
+	// are written in the shortest form that reads back as the same value, without
+	// an exponent for magnitudes in [1e-6, 1e21), as encoding/json does.
+	FloatFormat    byte
+	FloatPrecision int
+
+	// Prefix and Indent make the output indented the same way as json.MarshalIndent does.
+	// The data is written out compact and indented when it is retrieved from the writer,
+	// so they are not supported by streaming and pooled writers.
+	Prefix string
+	Indent string
+
+	// set for writers from GetWriter, which keep their buffer for reuse
+	pooled bool
+	// set for writers from NewStreamingWriter, which write the data out as it comes
+	streaming bool
+}
+
+// errIndentUnsupported is the error of streaming and pooled writers with Prefix
+// or Indent set.
+var errIndentUnsupported = errors.New("jwriter: Prefix and Indent are not supported by streaming and pooled writers")
+
+// errTimeYearRange is the error of Time for the years RFC 3339 has no format for.
+var errTimeYearRange = errors.New("jwriter: time year outside of range [0,9999]")
+
+// NewStreamingWriter returns a Writer that writes the data out to sink in chunks
+// while it is being serialized, instead of keeping all of it in memory. Flush has
+// to be called after serialization is done. Flush fails if Prefix or Indent is
+// set, as streamed data cannot be indented.
+func NewStreamingWriter(sink io.Writer) *Writer {
+	w := &Writer{streaming: true}
+	w.Buffer.SetSink(sink)
+	return w
+}
+
+// maxPooledBufSize is the largest buffer a pooled writer keeps for reuse, so
+// that a single large value does not pin a lot of memory.
+const maxPooledBufSize = 64 * 1024
+
+var writerPool = sync.Pool{
+	New: func() interface{} {
+		w := &Writer{pooled: true}
+		w.Buffer.UseBuf(nil)
+		return w
+	},
+}
+
+// GetWriter returns a Writer from a package-level pool, which reuses the buffer
+// the writer was used with before instead of allocating new chunks. BuildBytes
+// of a pooled writer returns a copy of the data; it and DumpTo fail if Prefix or
+// Indent is set. The writer has to be returned with PutWriter after the data is
+// retrieved with BuildBytes or DumpTo, and must not be used afterwards.
+func GetWriter() *Writer {
+	return writerPool.Get().(*Writer)
+}
+
+// PutWriter resets a writer returned by GetWriter and puts it back to the pool.
+// It is safe to call with a writer left in any state, e.g. by a panic while
+// encoding.
+func PutWriter(w *Writer) {
+	buf := w.Buffer.Buf[:0]
+	if cap(buf) > maxPooledBufSize {
+		buf = nil
+	}
+	*w = Writer{pooled: true}
+	w.Buffer.UseBuf(buf)
+	writerPool.Put(w)
+}
+
+// Flush writes out the buffered data of a streaming writer and returns the
+// serialization error or the first error returned by the sink.
+func (w *Writer) Flush() error {
+	if w.Error != nil {
+		return w.Error
+	}
+	if w.streaming && w.indenting() {
+		w.Error = errIndentUnsupported
+		return w.Error
+	}
+	w.Error = w.Buffer.Flush()
+	return w.Error
 }
 
 // Size returns the size of the data that was written out.
@@ -32,9 +135,34 @@ func (w *Writer) Size() int {
 	return w.Buffer.Size()
 }
 
+// indenting reports whether the output has to be indented.
+func (w *Writer) indenting() bool {
+	return w.Prefix != "" || w.Indent != ""
+}
+
+// indented returns the indented writer data, resetting the buffer.
+func (w *Writer) indented() ([]byte, error) {
+	if w.pooled || w.streaming {
+		return nil, errIndentUnsupported
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, w.Buffer.BuildBytes(), w.Prefix, w.Indent); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
 // DumpTo outputs the data to given io.Writer, resetting the buffer.
 func (w *Writer) DumpTo(out io.Writer) (written int, err error) {
-	return w.Buffer.DumpTo(out)
+	if !w.indenting() {
+		return w.Buffer.DumpTo(out)
+	}
+
+	data, err := w.indented()
+	if err != nil {
+		return 0, err
+	}
+	return out.Write(data)
 }
 
 // BuildBytes returns writer data as a single byte slice. You can optionally provide one byte slice
@@ -43,6 +171,17 @@ func (w *Writer) BuildBytes(reuse ...[]byte) ([]byte, error) {
 	if w.Error != nil {
 		return nil, w.Error
 	}
+	if w.indenting() {
+		return w.indented()
+	}
+	if w.pooled {
+		// The buffer stays with the writer for reuse, so the data is copied.
+		var ret []byte
+		if len(reuse) == 1 {
+			ret = reuse[0][:0]
+		}
+		return append(ret, w.Buffer.Buf...), nil
+	}
 
 	return w.Buffer.BuildBytes(reuse...), nil
 }
@@ -53,10 +192,27 @@ func (w *Writer) ReadCloser() (io.ReadCloser, error) {
 	if w.Error != nil {
 		return nil, w.Error
 	}
+	if w.indenting() {
+		data, err := w.indented()
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
 
 	return w.Buffer.ReadCloser(), nil
 }
 
+// JsonNumber writes a json.Number from 'encoding/json' package verbatim. An empty
+// number is written as 0, the same way encoding/json does.
+func (w *Writer) JsonNumber(n json.Number) {
+	if n == "" {
+		w.Buffer.AppendByte('0')
+		return
+	}
+	w.Buffer.AppendString(string(n))
+}
+
 // RawByte appends raw binary data to the buffer.
 func (w *Writer) RawByte(c byte) {
 	w.Buffer.AppendByte(c)
@@ -82,18 +238,31 @@ func (w *Writer) Raw(data []byte, err error) {
 	}
 }
 
+// Interface writes v as an interface{} value of the generated code: with its
+// MarshalEasyJSON method if it has one, with its MarshalJSON method otherwise,
+// and with json.Marshal if it has neither.
+func (w *Writer) Interface(v interface{}) {
+	switch m := v.(type) {
+	case interface{ MarshalEasyJSON(w *Writer) }:
+		m.MarshalEasyJSON(w)
+	case json.Marshaler:
+		w.Raw(m.MarshalJSON())
+	default:
+		w.Raw(json.Marshal(v))
+	}
+}
+
 // RawText encloses raw binary data in quotes and appends in to the buffer.
-// Useful for calling with results of MarshalText-like functions.
+// Useful for calling with results of MarshalText-like functions. Empty data
+// is written as an empty string, as encoding/json does.
 func (w *Writer) RawText(data []byte, err error) {
 	switch {
 	case w.Error != nil:
 		return
 	case err != nil:
 		w.Error = err
-	case len(data) > 0:
-		w.String(string(data))
 	default:
-		w.RawString("null")
+		w.String(string(data))
 	}
 }
 
@@ -133,6 +302,11 @@ func (w *Writer) Uint64(n uint64) {
 	w.Buffer.Buf = strconv.AppendUint(w.Buffer.Buf, n, 10)
 }
 
+func (w *Writer) Uintptr(n uintptr) {
+	w.Buffer.EnsureSpace(20)
+	w.Buffer.Buf = strconv.AppendUint(w.Buffer.Buf, uint64(n), 10)
+}
+
 func (w *Writer) Int8(n int8) {
 	w.Buffer.EnsureSpace(4)
 	w.Buffer.Buf = strconv.AppendInt(w.Buffer.Buf, int64(n), 10)
@@ -235,30 +409,108 @@ func (w *Writer) Int64Str(n int64) {
 	w.Buffer.Buf = append(w.Buffer.Buf, '"')
 }
 
+// nonFinite writes n according to the NaN policy if it is NaN or an infinity,
+// and reports whether it did.
+func (w *Writer) nonFinite(n float64) bool {
+	if !math.IsNaN(n) && !math.IsInf(n, 0) {
+		return false
+	}
+	switch w.NaNPolicy {
+	case NaNNull:
+		w.RawString("null")
+	case NaNString:
+		w.Buffer.EnsureSpace(6)
+		w.Buffer.Buf = append(w.Buffer.Buf, '"')
+		w.Buffer.Buf = strconv.AppendFloat(w.Buffer.Buf, n, 'g', -1, 64)
+		w.Buffer.Buf = append(w.Buffer.Buf, '"')
+	default:
+		if w.Error == nil {
+			w.Error = &json.UnsupportedValueError{
+				Value: reflect.ValueOf(n),
+				Str:   strconv.FormatFloat(n, 'g', -1, 64),
+			}
+		}
+	}
+	return true
+}
+
+// appendFloat appends n of the given bit size in the format of the writer.
+func (w *Writer) appendFloat(n float64, bits int) {
+	if w.FloatFormat != 0 {
+		w.Buffer.EnsureSpace(24 + w.FloatPrecision)
+		w.Buffer.Buf = strconv.AppendFloat(w.Buffer.Buf, n, w.FloatFormat, w.FloatPrecision, bits)
+		return
+	}
+
+	w.Buffer.EnsureSpace(24)
+	format := byte('f')
+	if abs := math.Abs(n); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	w.Buffer.Buf = strconv.AppendFloat(w.Buffer.Buf, n, format, -1, bits)
+	if format == 'e' {
+		// Shorten two-digit negative exponents like e-07 to e-7, as encoding/json does.
+		b := w.Buffer.Buf
+		if l := len(b); l >= 4 && b[l-4] == 'e' && b[l-3] == '-' && b[l-2] == '0' {
+			b[l-2] = b[l-1]
+			w.Buffer.Buf = b[:l-1]
+		}
+	}
+}
+
 func (w *Writer) Float32(n float32) {
-	w.Buffer.EnsureSpace(20)
-	w.Buffer.Buf = strconv.AppendFloat(w.Buffer.Buf, float64(n), 'g', -1, 32)
+	if w.nonFinite(float64(n)) {
+		return
+	}
+	w.appendFloat(float64(n), 32)
 }
 
 func (w *Writer) Float32Str(n float32) {
-	w.Buffer.EnsureSpace(20)
+	if w.nonFinite(float64(n)) {
+		return
+	}
+	w.Buffer.EnsureSpace(1)
 	w.Buffer.Buf = append(w.Buffer.Buf, '"')
-	w.Buffer.Buf = strconv.AppendFloat(w.Buffer.Buf, float64(n), 'g', -1, 32)
+	w.appendFloat(float64(n), 32)
+	w.Buffer.EnsureSpace(1)
 	w.Buffer.Buf = append(w.Buffer.Buf, '"')
 }
 
 func (w *Writer) Float64(n float64) {
-	w.Buffer.EnsureSpace(20)
-	w.Buffer.Buf = strconv.AppendFloat(w.Buffer.Buf, n, 'g', -1, 64)
+	if w.nonFinite(n) {
+		return
+	}
+	w.appendFloat(n, 64)
 }
 
 func (w *Writer) Float64Str(n float64) {
-	w.Buffer.EnsureSpace(20)
+	if w.nonFinite(n) {
+		return
+	}
+	w.Buffer.EnsureSpace(1)
 	w.Buffer.Buf = append(w.Buffer.Buf, '"')
-	w.Buffer.Buf = strconv.AppendFloat(w.Buffer.Buf, float64(n), 'g', -1, 64)
+	w.appendFloat(n, 64)
+	w.Buffer.EnsureSpace(1)
 	w.Buffer.Buf = append(w.Buffer.Buf, '"')
 }
 
+// BigInt writes n as a number with all of its digits.
+func (w *Writer) BigInt(n *big.Int) {
+	w.Buffer.AppendString(n.String())
+}
+
+// BigFloat writes n as a number in the shortest form that keeps its value, with
+// the NaNPolicy of the writer applied to infinities.
+func (w *Writer) BigFloat(n *big.Float) {
+	if n.IsInf() {
+		w.nonFinite(math.Inf(n.Sign()))
+		return
+	}
+	w.Buffer.AppendString(n.Text('g', -1))
+}
+
 func (w *Writer) Bool(v bool) {
 	w.Buffer.EnsureSpace(5)
 	if v {
@@ -268,6 +520,62 @@ func (w *Writer) Bool(v bool) {
 	}
 }
 
+func (w *Writer) BoolStr(v bool) {
+	w.Buffer.EnsureSpace(7)
+	if v {
+		w.Buffer.Buf = append(w.Buffer.Buf, `"true"`...)
+	} else {
+		w.Buffer.Buf = append(w.Buffer.Buf, `"false"`...)
+	}
+}
+
+// Time appends t formatted according to layout as a string literal. Output that
+// needs escaping, as for layouts with quotes or backslashes, is written like
+// String writes it. With the RFC 3339 layouts it fails for years outside of
+// [0,9999], which the format cannot represent, like time.Time.MarshalJSON does.
+func (w *Writer) Time(t time.Time, layout string) {
+	if y := t.Year(); (y < 0 || y > 9999) && (layout == time.RFC3339 || layout == time.RFC3339Nano) {
+		if w.Error == nil {
+			w.Error = errTimeYearRange
+		}
+		return
+	}
+	w.Buffer.EnsureSpace(len(layout) + 12)
+	start := len(w.Buffer.Buf)
+	w.Buffer.Buf = append(w.Buffer.Buf, '"')
+	w.Buffer.Buf = t.AppendFormat(w.Buffer.Buf, layout)
+	for _, c := range w.Buffer.Buf[start+1:] {
+		if c < 0x20 || c == '"' || c == '\\' {
+			w.Buffer.Buf = w.Buffer.Buf[:start]
+			w.String(t.Format(layout))
+			return
+		}
+	}
+	w.Buffer.Buf = append(w.Buffer.Buf, '"')
+}
+
+// UnixTime writes a time.Time as the integer number of seconds since the Unix
+// epoch, dropping fractions of a second.
+func (w *Writer) UnixTime(t time.Time) {
+	w.Int64(t.Unix())
+}
+
+// UnixMilliTime writes a time.Time as the integer number of milliseconds since
+// the Unix epoch, dropping fractions of a millisecond.
+func (w *Writer) UnixMilliTime(t time.Time) {
+	w.Int64(t.Unix()*1000 + int64(t.Nanosecond())/int64(time.Millisecond))
+}
+
+// Duration writes a time.Duration as a string in the format of its String method,
+// e.g. "1h30m0s".
+func (w *Writer) Duration(d time.Duration) {
+	s := d.String()
+	w.Buffer.EnsureSpace(len(s) + 2)
+	w.Buffer.Buf = append(w.Buffer.Buf, '"')
+	w.Buffer.Buf = append(w.Buffer.Buf, s...)
+	w.Buffer.Buf = append(w.Buffer.Buf, '"')
+}
+
 const chars = "0123456789abcdef"
 
 func getTable(falseValues ...int) [128]bool {
@@ -0,0 +1,23 @@
+package tests
+
+// AnyValues holds interface{} values, which are encoded with the generated
+// encoder of AnyRegistered as it is registered with the generator in
+// api_gen.go, and with encoding/json for other types without marshalers.
+type AnyValues struct {
+	Value  interface{}            `json:"value"`
+	Values []interface{}          `json:"values"`
+	ByName map[string]interface{} `json:"by_name,omitempty"`
+}
+
+// AnyRegistered has no marshaler methods, but a readonly field which only the
+// generated encoder skips.
+type AnyRegistered struct {
+	Name   string `json:"name"`
+	Secret string `json:"secret,readonly"`
+}
+
+// AnyUnregistered is like AnyRegistered, but not registered with the generator.
+type AnyUnregistered struct {
+	Name   string `json:"name"`
+	Secret string `json:"secret,readonly"`
+}
@@ -0,0 +1,43 @@
+package tests
+
+import "testing"
+
+func TestAnyValueMarshallerTypes(t *testing.T) {
+	for _, test := range []struct {
+		v    AnyValues
+		want string
+	}{
+		{
+			v:    AnyValues{Value: AnyRegistered{Name: "a", Secret: "s"}},
+			want: `{"value":{"name":"a"},"values":null}`,
+		},
+		{
+			v:    AnyValues{Value: &AnyRegistered{Name: "a", Secret: "s"}},
+			want: `{"value":{"name":"a"},"values":null}`,
+		},
+		{
+			v:    AnyValues{Value: (*AnyRegistered)(nil)},
+			want: `{"value":null,"values":null}`,
+		},
+		{
+			v:    AnyValues{Value: AnyUnregistered{Name: "a", Secret: "s"}},
+			want: `{"value":{"name":"a","secret":"s"},"values":null}`,
+		},
+		{
+			v: AnyValues{
+				Values: []interface{}{AnyRegistered{Name: "a"}, AnyUnregistered{Name: "b"}, nil, 1, "c"},
+				ByName: map[string]interface{}{"r": &AnyRegistered{Name: "d", Secret: "s"}},
+			},
+			want: `{"value":null,"values":[{"name":"a"},{"name":"b","secret":""},null,1,"c"],"by_name":{"r":{"name":"d"}}}`,
+		},
+	} {
+		data, err := test.v.MarshalJSON()
+		if err != nil {
+			t.Errorf("MarshalJSON(%+v) error: %v", test.v, err)
+			continue
+		}
+		if string(data) != test.want {
+			t.Errorf("MarshalJSON(%+v) = %s; want %s", test.v, data, test.want)
+		}
+	}
+}
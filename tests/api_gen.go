@@ -0,0 +1,83 @@
+//go:build ignore
+// +build ignore
+
+// Generates the files below in the directory given as the argument: the options
+// they are generated with are only available through the generator API.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/mailru/easyjson/gen"
+	"github.com/mailru/easyjson/tests"
+)
+
+var files = []struct {
+	name  string
+	setup func(g *gen.Generator)
+}{
+	{
+		// Concrete types registered for an interface.
+		name: "shape_easyjson.go",
+		setup: func(g *gen.Generator) {
+			g.RegisterInterfaceImpl(reflect.TypeOf((*tests.Shape)(nil)).Elem(), "kind", map[string]reflect.Type{
+				"circle": reflect.TypeOf(tests.Circle{}),
+				"square": reflect.TypeOf(&tests.Square{}),
+				"label":  reflect.TypeOf(tests.ShapeLabel{}),
+			})
+			g.Add(tests.Shapes{})
+		},
+	},
+	{
+		// Views of struct types.
+		name: "view_easyjson.go",
+		setup: func(g *gen.Generator) {
+			g.Add(tests.Account{})
+			g.AddView(tests.Account{}, "public", "")
+			g.AddView(tests.Account{}, "admin", "view")
+		},
+	},
+	{
+		// Types encoded from interface{} values.
+		name: "any_value_easyjson.go",
+		setup: func(g *gen.Generator) {
+			g.RegisterMarshallerType(reflect.TypeOf(tests.AnyRegistered{}))
+			g.Add(tests.AnyValues{})
+		},
+	},
+	{
+		// The strings and numbers decoded as bools.
+		name: "bool_values_easyjson.go",
+		setup: func(g *gen.Generator) {
+			g.SetBoolDecodeMap(map[string]bool{"Y": true, "N": false, "1": true, "0": false})
+			g.Add(tests.BoolValues{})
+		},
+	},
+}
+
+func main() {
+	for _, f := range files {
+		if err := generate(f.name, f.setup); err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", f.name, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func generate(name string, setup func(g *gen.Generator)) error {
+	g := gen.NewGenerator(name)
+	g.SetPkg("tests", "github.com/mailru/easyjson/tests")
+	setup(g)
+
+	var out bytes.Buffer
+	if err := g.Run(&out); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(os.Args[1], name), out.Bytes(), 0644)
+}
@@ -0,0 +1,25 @@
+package tests
+
+//easyjson:json
+type AppendChild struct {
+	Name  string
+	Score float64
+}
+
+//easyjson:json
+type AppendParent struct {
+	ID       int
+	Child    AppendChild
+	Children []AppendChild
+	Tags     map[string]string
+}
+
+var appendParentValue = AppendParent{
+	ID:    1,
+	Child: AppendChild{Name: "first", Score: 1.5},
+	Children: []AppendChild{
+		{Name: "second", Score: 2},
+		{Name: "third <&>", Score: -3.25},
+	},
+	Tags: map[string]string{"b": "2", "a": "1"},
+}
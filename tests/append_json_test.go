@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestAppendJSON(t *testing.T) {
+	want, err := appendParentValue.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+
+	if got := appendParentValue.AppendJSON(nil); string(got) != string(want) {
+		t.Errorf("AppendJSON(nil) = %s; want %s", got, want)
+	}
+
+	dst := []byte(`{"parent":`)
+	got := appendParentValue.AppendJSON(dst)
+	if string(got) != `{"parent":`+string(want) {
+		t.Errorf("AppendJSON(prefix) = %s; want prefix followed by %s", got, want)
+	}
+
+	// Values can be appended one after another into the same buffer.
+	got = append(got, `,"child":`...)
+	got = appendParentValue.Child.AppendJSON(got)
+	got = append(got, '}')
+	var decoded struct {
+		Parent AppendParent
+		Child  AppendChild
+	}
+	if err := json.Unmarshal(got, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error: %v", got, err)
+	}
+	if decoded.Child != appendParentValue.Child || decoded.Parent.ID != appendParentValue.ID {
+		t.Errorf("AppendJSON() composed %s", got)
+	}
+}
+
+func TestAppendJSONError(t *testing.T) {
+	dst := []byte("abc")
+	v := AppendParent{Child: AppendChild{Score: math.NaN()}}
+	if got := v.AppendJSON(dst); string(got) != "abc" {
+		t.Errorf("AppendJSON() of NaN = %s; want the unchanged buffer", got)
+	}
+}
+
+func TestAppendJSONReuseNoAllocs(t *testing.T) {
+	buf := appendParentValue.AppendJSON(nil)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = appendParentValue.Child.AppendJSON(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("AppendJSON() into a large enough buffer made %v allocations; want 0", allocs)
+	}
+}
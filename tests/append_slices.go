@@ -0,0 +1,13 @@
+package tests
+
+//easyjson:json
+type AppendSlices struct {
+	IDs      []int
+	Children []AppendSlicesChild
+	Data     []byte
+}
+
+type AppendSlicesChild struct {
+	Name string
+	Tags []string
+}
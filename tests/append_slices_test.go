@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mailru/easyjson/jlexer"
+)
+
+func TestAppendSlices(t *testing.T) {
+	var v AppendSlices
+	for _, data := range []string{
+		`{"IDs":[1,2],"Children":[{"Name":"a","Tags":["x"]}],"Data":"AQ=="}`,
+		`{"IDs":[3],"Children":[{"Name":"b"}],"Data":"Ag=="}`,
+		`{"IDs":null,"Children":[]}`,
+	} {
+		if err := v.UnmarshalJSON([]byte(data)); err != nil {
+			t.Fatalf("UnmarshalJSON(%s) error: %v", data, err)
+		}
+	}
+
+	want := AppendSlices{
+		IDs: []int{1, 2, 3},
+		Children: []AppendSlicesChild{
+			{Name: "a", Tags: []string{"x"}},
+			{Name: "b"},
+		},
+		Data: []byte{2},
+	}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("UnmarshalJSON() of all documents = %+v; want %+v", v, want)
+	}
+}
+
+func TestAppendSlicesErrorPath(t *testing.T) {
+	v := AppendSlices{IDs: []int{1, 2, 3}}
+	err := v.UnmarshalJSON([]byte(`{"IDs":[4,"5"]}`))
+	lexErr, ok := err.(*jlexer.LexerError)
+	if !ok {
+		t.Fatalf("UnmarshalJSON() error = %v; want *jlexer.LexerError", err)
+	}
+	if want := "$.IDs[1]"; lexErr.Path != want {
+		t.Errorf("UnmarshalJSON() error path = %q; want %q", lexErr.Path, want)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jlexer"
 	"github.com/mailru/easyjson/jwriter"
 )
 
@@ -24,6 +25,9 @@ var testCases = []struct {
 	{&namedPrimitiveTypesValue, namedPrimitiveTypesString},
 	{&structsValue, structsString},
 	{&omitEmptyValue, omitEmptyString},
+	{&omitEmptyOneOfThreeValue, omitEmptyOneOfThreeString},
+	{&omitEmptyOneOfThreeFilledValue, omitEmptyOneOfThreeFilledString},
+	{&jsonNumberStructValue, jsonNumberStructString},
 	{&snakeStructValue, snakeStructString},
 	{&omitEmptyDefaultValue, omitEmptyDefaultString},
 	{&optsValue, optsString},
@@ -33,6 +37,17 @@ var testCases = []struct {
 	{&unexportedStructValue, unexportedStructString},
 	{&excludedFieldValue, excludedFieldString},
 	{&sliceValue, sliceString},
+	{&byteSlicesValue, byteSlicesString},
+	{&anonymousStructsValue, anonymousStructsString},
+	{&listValue, listString},
+	{&recursiveAValue, recursiveAString},
+	{&recursiveContainersValue, recursiveContainersString},
+	{&shapesValue, shapesString},
+	{&rawMessagesValue, rawMessagesString},
+	{&namedPrimitivesValue, namedPrimitivesString},
+	{&fieldOrderValue, fieldOrderString},
+	{&omitEmptyIsZeroValue, omitEmptyIsZeroString},
+	{&omitEmptyIsZeroFilledValue, omitEmptyIsZeroFilledString},
 	{&arrayValue, arrayString},
 	{&mapsValue, mapsString},
 	{&deepNestValue, deepNestString},
@@ -41,12 +56,18 @@ var testCases = []struct {
 	{&namedTypeValue, namedTypeValueString},
 	{&customMapKeyTypeValue, customMapKeyTypeValueString},
 	{&embeddedTypeValue, embeddedTypeValueString},
+	{&embeddedLevelsValue, embeddedLevelsString},
+	{&embeddedSiblingsValue, embeddedSiblingsString},
+	{&embeddedNodeValue, embeddedNodeString},
+	{&embeddedTaggedValue, embeddedTaggedString},
 	{&mapMyIntStringValue, mapMyIntStringValueString},
 	{&mapIntStringValue, mapIntStringValueString},
 	{&mapInt32StringValue, mapInt32StringValueString},
 	{&mapInt64StringValue, mapInt64StringValueString},
 	{&mapUintStringValue, mapUintStringValueString},
 	{&mapUint32StringValue, mapUint32StringValueString},
+	{&mapUint32BoolValue, mapUint32BoolValueString},
+	{&mapInt8Uint16BoolValue, mapInt8Uint16BoolValueString},
 	{&mapUint64StringValue, mapUint64StringValueString},
 	{&mapUintptrStringValue, mapUintptrStringValueString},
 	{&intKeyedMapStructValue, intKeyedMapStructValueString},
@@ -59,6 +80,12 @@ var testCases = []struct {
 	{&myTypeDeclaredValue, myTypeDeclaredString},
 	{&myTypeNotSkippedValue, myTypeNotSkippedString},
 	{&intern, internString},
+	{&stringTaggedValue, stringTaggedString},
+	{&tagKeyValue, tagKeyString},
+	{&kebabStructValue, kebabStructString},
+	{&textMarshalerValue, textMarshalerString},
+	{&jsonMarshalerValue, jsonMarshalerString},
+	{&timeStructValue, timeStructString},
 }
 
 func TestMarshal(t *testing.T) {
@@ -258,6 +285,41 @@ func TestNestedMarshaler(t *testing.T) {
 	}
 }
 
+func TestJSONMarshalerDelegation(t *testing.T) {
+	mixed := LowerString("MiXeD")
+	s := JSONMarshalerStruct{Value: "UPPER", Ptr: &mixed, Values: []LowerString{"A"}}
+
+	data, err := easyjson.Marshal(s)
+	if err != nil {
+		t.Errorf("easyjson.Marshal() error: %v", err)
+	}
+	if want := `{"Value":"upper","Ptr":"mixed","Values":["a"]}`; string(data) != want {
+		t.Errorf("easyjson.Marshal() = %s; want %s", data, want)
+	}
+
+	var s2 JSONMarshalerStruct
+	if err := easyjson.Unmarshal([]byte(`{"Value":"ABC","Ptr":"DEF"}`), &s2); err != nil {
+		t.Errorf("easyjson.Unmarshal() error: %v", err)
+	}
+	if s2.Value != "abc" || s2.Ptr == nil || *s2.Ptr != "def" {
+		t.Errorf("easyjson.Unmarshal() = %#v; want lower case values", s2)
+	}
+
+	lower := LowerString("IFACE")
+	i := JSONMarshalerIfaceStruct{Value: &lower}
+	data, err = easyjson.Marshal(i)
+	if err != nil {
+		t.Errorf("easyjson.Marshal() error: %v", err)
+	}
+	if want := `{"Value":"iface","Nil":null}`; string(data) != want {
+		t.Errorf("easyjson.Marshal() = %s; want %s", data, want)
+	}
+
+	if err := easyjson.Unmarshal([]byte(`{"Value":1}`), &i); err == nil {
+		t.Errorf("easyjson.Unmarshal() ok; want UnmarshalJSON error")
+	}
+}
+
 func TestUnmarshalStructWithEmbeddedPtrStruct(t *testing.T) {
 	var s = StructWithInterface{Field2: &EmbeddedStruct{}}
 	var err error
@@ -273,8 +335,27 @@ func TestUnmarshalStructWithEmbeddedPtrStruct(t *testing.T) {
 func TestDisallowUnknown(t *testing.T) {
 	var d DisallowUnknown
 	err := easyjson.Unmarshal([]byte(disallowUnknownString), &d)
+	if e, ok := err.(*jlexer.LexerError); !ok {
+		t.Errorf("want *jlexer.LexerError, got %v", err)
+	} else if e.Data != "field_two" || e.Offset == 0 {
+		t.Errorf("want error with key and offset, got %v", e)
+	}
+
+	err = easyjson.Unmarshal([]byte(disallowUnknownNullString), &d)
 	if err == nil {
-		t.Error("want error, got nil")
+		t.Error("want error for unknown null field, got nil")
+	}
+
+	err = easyjson.Unmarshal([]byte(disallowUnknownKnownString), &d)
+	if err != nil {
+		t.Errorf("want no error for known null field, got %v", err)
+	}
+
+	// Without the option unknown keys are skipped.
+	var s ExcludedField
+	err = easyjson.Unmarshal([]byte(`{"process":true,"unknown":{"a":[1]}}`), &s)
+	if err != nil {
+		t.Errorf("want no error when unknown fields are allowed, got %v", err)
 	}
 }
 
@@ -329,3 +410,322 @@ func TestNil(t *testing.T) {
 		t.Errorf("Wanted null, got %q", s)
 	}
 }
+
+func TestEmbeddedPointer(t *testing.T) {
+	var v EmbeddedPtrType
+	if err := easyjson.Unmarshal([]byte(`{}`), &v); err != nil {
+		t.Errorf("Unmarshal() error: %v", err)
+	}
+	if v.EmbeddedPtrBase != nil {
+		t.Errorf("Unmarshal() of {} allocated embedded pointer: %+v", v.EmbeddedPtrBase)
+	}
+
+	v = EmbeddedPtrType{}
+	if err := easyjson.Unmarshal([]byte(`{"baseField":1}`), &v); err != nil {
+		t.Errorf("Unmarshal() error: %v", err)
+	}
+	if v.EmbeddedPtrBase == nil || v.BaseField != 1 {
+		t.Errorf("Unmarshal() of {\"baseField\":1} = %+v; want allocated embedded pointer", v)
+	}
+
+	for i, test := range []struct {
+		value easyjson.Marshaler
+		want  string
+	}{
+		{EmbeddedPtrType{Other: 1}, `{"other":1}`},
+		{EmbeddedPtrType{EmbeddedPtrBase: &EmbeddedPtrBase{BaseField: 2}}, `{"other":0,"baseField":2}`},
+		{EmbeddedPtrsType{}, `{}`},
+		{EmbeddedPtrsType{EmbeddedInnerType: &EmbeddedInnerType{Field1: 3}}, `{"Field1":3}`},
+		{EmbeddedPtrsType{&EmbeddedPtrBase{BaseField: 2}, &EmbeddedInnerType{Field1: 3}}, `{"Field1":3,"baseField":2}`},
+	} {
+		data, err := easyjson.Marshal(test.value)
+		if err != nil {
+			t.Errorf("[%d] Marshal() error: %v", i, err)
+		}
+		if string(data) != test.want {
+			t.Errorf("[%d] Marshal() = %s; want %s", i, data, test.want)
+		}
+	}
+}
+
+func TestStrictArrays(t *testing.T) {
+	for i, test := range []struct {
+		data      string
+		want      StrictArrays
+		wantError string
+	}{
+		{data: `{"Ints":[1,2,3,4],"Bytes":"YWI="}`, want: StrictArrays{Ints: [4]int{1, 2, 3, 4}, Bytes: [2]byte{'a', 'b'}}},
+		{data: `{"Ints":[1,2,3]}`, wantError: "array of length 4 expected, got 3 elements"},
+		{data: `{"Ints":[1,2,3,4,5]}`, wantError: "array of length 4 expected, got 5 elements"},
+		{data: `{"Bytes":"YWJj"}`, wantError: "array of length 2 expected, got 3 bytes"},
+		{data: `{"Ints":null,"Bytes":null}`},
+	} {
+		var v StrictArrays
+		err := easyjson.Unmarshal([]byte(test.data), &v)
+		if test.wantError == "" {
+			if err != nil {
+				t.Errorf("[%d] Unmarshal(%v) error: %v", i, test.data, err)
+			}
+			if v != test.want {
+				t.Errorf("[%d] Unmarshal(%v) = %+v; want %+v", i, test.data, v, test.want)
+			}
+		} else if err == nil || err.Error() != test.wantError {
+			t.Errorf("[%d] Unmarshal(%v) error = %v; want %v", i, test.data, err, test.wantError)
+		}
+	}
+}
+
+func TestByteSlicesSTD(t *testing.T) {
+	want, err := json.Marshal(byteSlicesValue)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	got, err := byteSlicesValue.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("MarshalJSON() = %s; json.Marshal() = %s", got, want)
+	}
+}
+
+func TestShapes(t *testing.T) {
+	for i, test := range []struct {
+		data      string
+		want      Shapes
+		wantError string
+	}{
+		{data: `{"Main":{"Radius":1,"kind":"circle"}}`, want: Shapes{Main: Circle{Kind: "circle", Radius: 1}}},
+		{data: `{"Main":{"Side":2,"kind":"square"}}`, want: Shapes{Main: &Square{Kind: "square", Side: 2}}},
+		{data: `{"Main":null,"Others":[]}`, want: Shapes{Others: []Shape{}}},
+		{data: `{"Main":{"kind":"triangle"}}`, wantError: "parse error: unknown kind of tests.Shape near offset 8 of 'triangle'"},
+		{data: `{"Main":{"Radius":1}}`, wantError: "parse error: unknown kind of tests.Shape near offset 8 of ''"},
+		{data: `{"Main":{"kind":"circle","Radius":"x"}}`, wantError: "parse error: expected number near offset 37 of 'x'"},
+	} {
+		var v Shapes
+		err := easyjson.Unmarshal([]byte(test.data), &v)
+		if test.wantError == "" {
+			if err != nil {
+				t.Errorf("[%d] Unmarshal(%v) error: %v", i, test.data, err)
+			}
+			if !reflect.DeepEqual(v, test.want) {
+				t.Errorf("[%d] Unmarshal(%v) = %+v; want %+v", i, test.data, v, test.want)
+			}
+		} else if err == nil || err.Error() != test.wantError {
+			t.Errorf("[%d] Unmarshal(%v) error = %v; want %v", i, test.data, err, test.wantError)
+		}
+	}
+}
+
+func TestShapesJSON5(t *testing.T) {
+	l := jlexer.Lexer{Data: []byte(`{Main: {kind: "square", Side: 2,}, Others: [{Radius: 1, kind: "circle"},],}`), JSON5: true}
+	var v Shapes
+	v.UnmarshalEasyJSON(&l)
+	if err := l.Error(); err != nil {
+		t.Fatalf("UnmarshalEasyJSON() error: %v", err)
+	}
+	want := Shapes{Main: &Square{Kind: "square", Side: 2}, Others: []Shape{Circle{Kind: "circle", Radius: 1}}}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("UnmarshalEasyJSON() = %+v; want %+v", v, want)
+	}
+}
+
+func TestShapesUseNumber(t *testing.T) {
+	l := jlexer.Lexer{Data: []byte(`{"Main":{"kind":"label","Value":123456789012345678}}`), UseNumber: true}
+	var v Shapes
+	v.UnmarshalEasyJSON(&l)
+	if err := l.Error(); err != nil {
+		t.Fatalf("UnmarshalEasyJSON() error: %v", err)
+	}
+	want := Shapes{Main: ShapeLabel{Kind: "label", Value: json.Number("123456789012345678")}}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("UnmarshalEasyJSON() = %+v; want %+v", v, want)
+	}
+}
+
+func TestInterfaceMapRoundTrip(t *testing.T) {
+	v := Maps{InterfaceMap: map[string]interface{}{
+		"string": "s",
+		"number": float64(1.5),
+		"bool":   true,
+		"null":   nil,
+		"array":  []interface{}{float64(1), "two", false, nil, []interface{}{}},
+		"object": map[string]interface{}{"nested": map[string]interface{}{"a": []interface{}{"b"}}},
+	}}
+
+	data, err := easyjson.Marshal(&v)
+	if err != nil {
+		t.Fatalf("easyjson.Marshal() error: %v", err)
+	}
+
+	var got Maps
+	if err := easyjson.Unmarshal(data, &got); err != nil {
+		t.Fatalf("easyjson.Unmarshal(%s) error: %v", data, err)
+	}
+	if !reflect.DeepEqual(got.InterfaceMap, v.InterfaceMap) {
+		t.Errorf("easyjson.Unmarshal(%s) = %#v; want %#v", data, got.InterfaceMap, v.InterfaceMap)
+	}
+
+	var std struct{ InterfaceMap map[string]interface{} }
+	if err := json.Unmarshal(data, &std); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error: %v", data, err)
+	}
+	if !reflect.DeepEqual(got.InterfaceMap, std.InterfaceMap) {
+		t.Errorf("easyjson.Unmarshal(%s) = %#v; json.Unmarshal() = %#v", data, got.InterfaceMap, std.InterfaceMap)
+	}
+}
+
+func TestNullValues(t *testing.T) {
+	one := 1
+	filled := func() NullFields {
+		return NullFields{
+			Ptr:    &one,
+			Map:    map[string]int{"a": 1},
+			Slice:  []int{1},
+			Iface:  "x",
+			Int:    1,
+			Struct: SubStruct{Value: "v"},
+		}
+	}
+
+	for i, test := range []struct {
+		data string
+		want NullFields
+	}{
+		{data: `null`, want: filled()},
+		{data: ` null `, want: filled()},
+		{data: `{"Ptr":null,"Map":null,"Slice":null,"Iface":null}`, want: NullFields{Int: 1, Struct: SubStruct{Value: "v"}}},
+		{data: `{"Int":null,"Struct":null}`, want: filled()},
+	} {
+		v := filled()
+		if err := easyjson.Unmarshal([]byte(test.data), &v); err != nil {
+			t.Errorf("[%d] easyjson.Unmarshal(%v) error: %v", i, test.data, err)
+		}
+		if !reflect.DeepEqual(v, test.want) {
+			t.Errorf("[%d] easyjson.Unmarshal(%v) = %+v; want %+v", i, test.data, v, test.want)
+		}
+
+		std := filled()
+		if err := json.Unmarshal([]byte(test.data), (*stdNullFields)(&std)); err != nil {
+			t.Errorf("[%d] json.Unmarshal(%v) error: %v", i, test.data, err)
+		}
+		if !reflect.DeepEqual(v, std) {
+			t.Errorf("[%d] easyjson.Unmarshal(%v) = %+v; json.Unmarshal() = %+v", i, test.data, v, std)
+		}
+	}
+
+	ints := Ints{1}
+	if err := easyjson.Unmarshal([]byte(`null`), &ints); err != nil || ints != nil {
+		t.Errorf("easyjson.Unmarshal(null) = %v, %v; want nil slice", ints, err)
+	}
+	m := MapStringString{"a": "b"}
+	if err := easyjson.Unmarshal([]byte(`null`), &m); err != nil || m != nil {
+		t.Errorf("easyjson.Unmarshal(null) = %v, %v; want nil map", m, err)
+	}
+}
+
+// stdNullFields has the fields of NullFields without the generated methods.
+type stdNullFields NullFields
+
+func TestRawMessagesSTD(t *testing.T) {
+	data := []byte(rawMessagesString)
+
+	var v RawMessages
+	if err := easyjson.Unmarshal(data, &v); err != nil {
+		t.Fatalf("easyjson.Unmarshal() error: %v", err)
+	}
+	type stdRawMessages RawMessages
+	var std stdRawMessages
+	if err := json.Unmarshal(data, &std); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+	// encoding/json keeps a null RawMessage as "null", while easyjson skips null
+	// fields of types that are not set to nil.
+	std.Nil = nil
+	if !reflect.DeepEqual(v, RawMessages(std)) {
+		t.Errorf("easyjson.Unmarshal() = %+v; json.Unmarshal() = %+v", v, std)
+	}
+
+	if got, want := &v.NoCopy[0], &data[bytes.Index(data, []byte(`"x"`))]; got != want {
+		t.Errorf("nocopy RawMessage does not point into the input")
+	}
+	if got, notWant := &v.Object[0], &data[bytes.Index(data, []byte(`{ "a"`))]; got == notWant {
+		t.Errorf("RawMessage points into the input")
+	}
+}
+
+func TestNamedPrimitivesTopLevel(t *testing.T) {
+	s := Status(5)
+	data, err := easyjson.Marshal(s)
+	if err != nil || string(data) != "5" {
+		t.Errorf("easyjson.Marshal(Status) = %q, %v; want \"5\", nil", data, err)
+	}
+
+	if err := easyjson.Unmarshal([]byte(` 7 `), &s); err != nil || s != 7 {
+		t.Errorf("easyjson.Unmarshal(7) got %v, %v; want 7, nil", s, err)
+	}
+	if err := easyjson.Unmarshal([]byte(`null`), &s); err != nil || s != 7 {
+		t.Errorf("easyjson.Unmarshal(null) got %v, %v; want 7, nil", s, err)
+	}
+	if err := easyjson.Unmarshal([]byte(`"7"`), &s); err == nil {
+		t.Errorf("easyjson.Unmarshal(\"7\") got no error for a string")
+	}
+	if err := easyjson.Unmarshal([]byte(`7 8`), &s); err == nil {
+		t.Errorf("easyjson.Unmarshal(7 8) got no error for trailing data")
+	}
+
+	var l Label
+	if err := easyjson.Unmarshal([]byte(`"hot"`), &l); err != nil || l != "hot" {
+		t.Errorf("easyjson.Unmarshal(\"hot\") got %q, %v; want \"hot\", nil", l, err)
+	}
+	data, err = json.Marshal(l)
+	if err != nil || string(data) != `"hot"` {
+		t.Errorf("json.Marshal(Label) = %q, %v; want \"hot\", nil", data, err)
+	}
+}
+
+func TestEmbeddedLevelsSTD(t *testing.T) {
+	type stdLevels EmbeddedLevels
+	type stdSiblings EmbeddedSiblings
+	type stdTagged EmbeddedTagged
+	for _, test := range []struct {
+		data   string
+		v, std interface{}
+	}{
+		{data: `{"Name":"a","label":"b","Middle":1,"Deep":2,"Title":"c"}`, v: &EmbeddedLevels{}, std: &stdLevels{}},
+		{data: `{"ID":1,"Value":2,"B":3}`, v: &EmbeddedSiblings{}, std: &stdSiblings{}},
+		{data: `{"b_id":1,"c_id":2}`, v: &EmbeddedTagged{}, std: &stdTagged{}},
+	} {
+		if err := easyjson.Unmarshal([]byte(test.data), test.v.(easyjson.Unmarshaler)); err != nil {
+			t.Errorf("easyjson.Unmarshal(%s) error: %v", test.data, err)
+			continue
+		}
+		if err := json.Unmarshal([]byte(test.data), test.std); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error: %v", test.data, err)
+		}
+		got := reflect.ValueOf(test.v).Elem().Interface()
+		want := reflect.ValueOf(test.std).Elem().Convert(reflect.TypeOf(got)).Interface()
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("easyjson.Unmarshal(%s) = %+v; json.Unmarshal() = %+v", test.data, got, want)
+		}
+
+		gotData, err := easyjson.Marshal(test.v.(easyjson.Marshaler))
+		if err != nil {
+			t.Errorf("easyjson.Marshal() error: %v", err)
+		}
+		wantData, err := json.Marshal(test.std)
+		if err != nil {
+			t.Fatalf("json.Marshal() error: %v", err)
+		}
+		var gotMap, wantMap map[string]interface{}
+		if err := json.Unmarshal(gotData, &gotMap); err != nil {
+			t.Fatalf("easyjson.Marshal() = %s, not JSON: %v", gotData, err)
+		}
+		if err := json.Unmarshal(wantData, &wantMap); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error: %v", wantData, err)
+		}
+		if !reflect.DeepEqual(gotMap, wantMap) {
+			t.Errorf("easyjson.Marshal() = %s; json.Marshal() = %s", gotData, wantData)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package tests
+
+import "math/big"
+
+//easyjson:json
+type BigNumbers struct {
+	Int      big.Int
+	IntPtr   *big.Int
+	Float    big.Float
+	FloatPtr *big.Float `json:",omitempty"`
+	Ints     []*big.Int
+	ByName   map[string]big.Float
+}
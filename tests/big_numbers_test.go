@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/mailru/easyjson"
+)
+
+// bigDigits is a 100-digit integer, far beyond the range of int64 and the
+// precision of float64.
+var bigDigits = "1" + strings.Repeat("234567890", 11)
+
+// bigFloatDigits is bigDigits in the exponent form big.Float values are written in.
+var bigFloatDigits = bigDigits[:1] + "." + strings.TrimRight(bigDigits[1:], "0") + "e+99"
+
+func TestBigNumbers(t *testing.T) {
+	for _, data := range []string{
+		`{"Int":0,"IntPtr":null,"Float":0,"Ints":null,"ByName":null}`,
+		`{"Int":` + bigDigits + `,"IntPtr":-` + bigDigits + `,"Float":` + bigFloatDigits + `,"FloatPtr":1.5,"Ints":[` + bigDigits + `,null,-1],"ByName":{"x":-` + bigFloatDigits + `}}`,
+		`{"Int":-17,"IntPtr":0,"Float":1e+100,"FloatPtr":-0.000125,"Ints":[],"ByName":{"e":1.25e-300}}`,
+	} {
+		var v BigNumbers
+		if err := easyjson.Unmarshal([]byte(data), &v); err != nil {
+			t.Errorf("Unmarshal(%s) error: %v", data, err)
+			continue
+		}
+		out, err := easyjson.Marshal(v)
+		if err != nil {
+			t.Errorf("Marshal(%s) error: %v", data, err)
+			continue
+		}
+		if string(out) != data {
+			t.Errorf("Marshal(Unmarshal(%s)) = %s", data, out)
+		}
+	}
+}
+
+func TestBigNumbersDigits(t *testing.T) {
+	var v BigNumbers
+	data := `{"Int":` + bigDigits + `,"Float":` + bigDigits + `}`
+	if err := easyjson.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatalf("Unmarshal(%s) error: %v", data, err)
+	}
+	if got := v.Int.String(); got != bigDigits {
+		t.Errorf("Unmarshal(%s).Int = %v; want %v", data, got, bigDigits)
+	}
+	want, _ := new(big.Int).SetString(bigDigits, 10)
+	if got, acc := v.Float.Int(nil); got.Cmp(want) != 0 || acc != big.Exact {
+		t.Errorf("Unmarshal(%s).Float = %v (%v); want %v", data, got, acc, bigDigits)
+	}
+}
+
+func TestBigNumbersErrors(t *testing.T) {
+	for _, data := range []string{
+		`{"Int":1.5}`,
+		`{"Int":1e3}`,
+		`{"IntPtr":"12"}`,
+		`{"Float":"1.5"}`,
+		`{"Ints":[true]}`,
+	} {
+		var v BigNumbers
+		if err := easyjson.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("Unmarshal(%s) = %+v; want an error", data, v)
+		}
+	}
+}
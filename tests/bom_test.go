@@ -0,0 +1,34 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeLeadingBOM(t *testing.T) {
+	want := StreamingDecodeStruct{Name: "x", Tags: map[string]string{"a": "b"}}
+	for _, prefix := range []string{"\uFEFF", "\t\n\r\n  ", "\uFEFF\n\t", ""} {
+		data := prefix + `{"Name":"x","Tags":{"a":"b"}}` + "\n"
+
+		var got StreamingDecodeStruct
+		if err := got.UnmarshalJSON([]byte(data)); err != nil {
+			t.Errorf("UnmarshalJSON(%q) error: %v", data, err)
+		} else if !reflect.DeepEqual(got, want) {
+			t.Errorf("UnmarshalJSON(%q) = %+v; want %+v", data, got, want)
+		}
+
+		var streamed StreamingDecodeStruct
+		if err := streamed.DecodeJSON(strings.NewReader(data)); err != nil {
+			t.Errorf("DecodeJSON(%q) error: %v", data, err)
+		} else if !reflect.DeepEqual(streamed, want) {
+			t.Errorf("DecodeJSON(%q) = %+v; want %+v", data, streamed, want)
+		}
+	}
+
+	data := `{"Name":"x"}` + "\uFEFF"
+	var v StreamingDecodeStruct
+	if err := v.UnmarshalJSON([]byte(data)); err == nil {
+		t.Errorf("UnmarshalJSON(%q) ok; want error for a trailing BOM", data)
+	}
+}
@@ -0,0 +1,12 @@
+package tests
+
+// BoolValues has its bools decoded from "Y" and "N" and numbers 1 and 0 too, as
+// set with SetBoolDecodeMap by api_gen.go.
+type BoolValues struct {
+	Active   bool
+	Deleted  bool `json:",omitempty"`
+	Ptr      *bool
+	Flags    []bool
+	ByName   map[string]bool
+	Quantity int
+}
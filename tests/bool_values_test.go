@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mailru/easyjson"
+)
+
+func TestBoolDecodeMap(t *testing.T) {
+	yes, no := true, false
+	for _, test := range []struct {
+		data string
+		want BoolValues
+	}{
+		{
+			data: `{"Active":"Y","Deleted":"N","Ptr":"Y","Flags":["Y","N",1,0,true,false],"ByName":{"a":"N","b":1}}`,
+			want: BoolValues{Active: true, Ptr: &yes, Flags: []bool{true, false, true, false, true, false}, ByName: map[string]bool{"a": false, "b": true}},
+		},
+		{
+			data: `{"Active":1,"Deleted":0,"Ptr":0}`,
+			want: BoolValues{Active: true, Ptr: &no},
+		},
+		{
+			data: `{"Active":true,"Deleted":false,"Ptr":null,"Quantity":1}`,
+			want: BoolValues{Active: true, Quantity: 1},
+		},
+	} {
+		var got BoolValues
+		if err := easyjson.Unmarshal([]byte(test.data), &got); err != nil {
+			t.Errorf("Unmarshal(%s) error: %v", test.data, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Unmarshal(%s) = %+v; want %+v", test.data, got, test.want)
+		}
+	}
+
+	for _, data := range []string{
+		`{"Active":"yes"}`,
+		`{"Active":"true"}`,
+		`{"Active":2}`,
+		`{"Active":1.0}`,
+	} {
+		var v BoolValues
+		if err := easyjson.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("Unmarshal(%s) = %+v; want error", data, v)
+		}
+	}
+
+	data, err := easyjson.Marshal(BoolValues{Active: true})
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	if want := `{"Active":true,"Ptr":null,"Flags":null,"ByName":null,"Quantity":0}`; string(data) != want {
+		t.Errorf("Marshal() = %s; want %s", data, want)
+	}
+}
@@ -0,0 +1,10 @@
+package tests
+
+//easyjson:json
+type CaseInsensitive struct {
+	Username string
+	UserName string
+	ID       int    `json:"id"`
+	Email    string `json:"email,omitempty"`
+	Ptr      *int
+}
@@ -0,0 +1,37 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCaseInsensitive(t *testing.T) {
+	for _, test := range []string{
+		`{"USERNAME":"x"}`,
+		`{"username":"x","id":1}`,
+		`{"Username":"x","UserName":"y","ID":2}`,
+		`{"userName":"y","Username":"x"}`,
+		`{"EMAIL":"e","Id":3,"PTR":5}`,
+		`{"Ptr":5,"ptr":null}`,
+		`{"unknown":1,"UsErNaMe":"z"}`,
+	} {
+		var got, want CaseInsensitive
+		if err := got.UnmarshalJSON([]byte(test)); err != nil {
+			t.Errorf("UnmarshalJSON(%s) error: %v", test, err)
+			continue
+		}
+		type std CaseInsensitive
+		if err := json.Unmarshal([]byte(test), (*std)(&want)); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error: %v", test, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("UnmarshalJSON(%s) got %+v; want %+v", test, got, want)
+		}
+	}
+
+	var v CaseInsensitive
+	if err := v.UnmarshalJSON([]byte(`{"USERNAME":"x"}`)); err != nil || v.Username != "x" {
+		t.Errorf("UnmarshalJSON() got %+v, %v; want Username x", v, err)
+	}
+}
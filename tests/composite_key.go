@@ -0,0 +1,29 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+)
+
+// CompositeKey is a struct map key encoded as its parts joined by a colon.
+type CompositeKey struct {
+	A, B string
+}
+
+func (k CompositeKey) MarshalText() ([]byte, error) {
+	return []byte(k.A + ":" + k.B), nil
+}
+
+func (k *CompositeKey) UnmarshalText(text []byte) error {
+	parts := strings.SplitN(string(text), ":", 2)
+	if len(parts) != 2 {
+		return errors.New("composite key without a colon")
+	}
+	k.A, k.B = parts[0], parts[1]
+	return nil
+}
+
+//easyjson:json
+type CompositeKeyMap struct {
+	Counts map[CompositeKey]int
+}
@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCompositeMapKey(t *testing.T) {
+	v := CompositeKeyMap{Counts: map[CompositeKey]int{
+		{A: "b", B: "x"}: 3,
+		{A: "a", B: "b"}: 1,
+		{A: "a", B: "a"}: 2,
+	}}
+	want := `{"Counts":{"a:a":2,"a:b":1,"b:x":3}}`
+
+	for i := 0; i < 10; i++ {
+		data, err := v.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON() error: %v", err)
+		}
+		if string(data) != want {
+			t.Fatalf("[%d] MarshalJSON() = %s; want %s", i, data, want)
+		}
+	}
+	if std, err := json.Marshal(v); err != nil || string(std) != want {
+		t.Errorf("json.Marshal() = %s, %v; want %s", std, err, want)
+	}
+
+	var got CompositeKeyMap
+	if err := got.UnmarshalJSON([]byte(want)); err != nil {
+		t.Fatalf("UnmarshalJSON(%s) error: %v", want, err)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("UnmarshalJSON(%s) = %+v; want %+v", want, got, v)
+	}
+}
+
+func TestCompositeMapKeyError(t *testing.T) {
+	data := `{"Counts":{"ab":1}}`
+	var v CompositeKeyMap
+	if err := v.UnmarshalJSON([]byte(data)); err == nil {
+		t.Errorf("UnmarshalJSON(%s) ok; want error", data)
+	}
+}
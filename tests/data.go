@@ -358,6 +358,24 @@ var omitEmptyString = "{" +
 	`"SubPNE":{"Value":"3","Value2":"4"}` +
 	"}"
 
+type OmitEmptyOneOfThree struct {
+	Name  string
+	Tags  []string `json:"tags,omitempty"`
+	Count int
+}
+
+var omitEmptyOneOfThreeValue = OmitEmptyOneOfThree{}
+
+var omitEmptyOneOfThreeString = `{"Name":"","Count":0}`
+
+var omitEmptyOneOfThreeFilledValue = OmitEmptyOneOfThree{
+	Name:  "n",
+	Tags:  []string{"t"},
+	Count: 1,
+}
+
+var omitEmptyOneOfThreeFilledString = `{"Name":"n","tags":["t"],"Count":1}`
+
 type Opts struct {
 	StrNull      opt.String
 	StrEmpty     opt.String
@@ -462,14 +480,29 @@ type ExcludedField struct {
 	Process       bool `json:"process"`
 	DoNotProcess  bool `json:"-"`
 	DoNotProcess1 bool `json:"-"`
+	Dash          bool `json:"-,"`
+
+	ExcludedInner
+	ExcludedEmbedded `json:"-"`
+}
+
+type ExcludedInner struct {
+	Inner        int `json:"inner"`
+	DoNotProcess int `json:"-"`
+}
+
+type ExcludedEmbedded struct {
+	Embedded int
 }
 
 var excludedFieldValue = ExcludedField{
 	Process:       true,
 	DoNotProcess:  false,
 	DoNotProcess1: false,
+	Dash:          true,
+	ExcludedInner: ExcludedInner{Inner: 1},
 }
-var excludedFieldString = `{"process":true}`
+var excludedFieldString = `{"process":true,"-":true,"inner":1}`
 
 type Slices struct {
 	ByteSlice      []byte
@@ -498,6 +531,59 @@ var sliceString = `{` +
 	`"NilIntSlice":null` +
 	`}`
 
+type AnonymousStructs struct {
+	A struct{ V int }
+	B struct{ V int }
+	C []struct {
+		W string `json:"w"`
+	}
+	D *struct{ V, W int }
+}
+
+var anonymousStructsValue = AnonymousStructs{
+	A: struct{ V int }{1},
+	B: struct{ V int }{2},
+	C: []struct {
+		W string `json:"w"`
+	}{{"x"}, {"y"}},
+	D: &struct{ V, W int }{3, 4},
+}
+
+var anonymousStructsString = `{"A":{"V":1},"B":{"V":2},"C":[{"w":"x"},{"w":"y"}],"D":{"V":3,"W":4}}`
+
+type Blob []byte
+
+type ByteSlices struct {
+	Blob      Blob
+	EmptyBlob Blob
+	NilBlob   Blob
+	BlobPtr   *Blob
+	Map       map[string][]byte
+	NilMap    map[string][]byte
+	BlobMap   map[string]Blob
+}
+
+var blob = Blob("blob")
+
+var byteSlicesValue = ByteSlices{
+	Blob:      Blob("abc"),
+	EmptyBlob: Blob{},
+	BlobPtr:   &blob,
+	Map:       map[string][]byte{"a": []byte("abc")},
+	NilMap:    map[string][]byte{"n": nil},
+	BlobMap:   map[string]Blob{"e": {}},
+}
+
+var byteSlicesString = `{` +
+	`"Blob":"YWJj",` +
+	`"EmptyBlob":"",` +
+	`"NilBlob":null,` +
+	`"BlobPtr":"YmxvYg==",` +
+	`"Map":{"a":"YWJj"},` +
+	`"NilMap":{"n":null},` +
+	`"BlobMap":{"e":""}` +
+	`}`
+
 type Arrays struct {
 	ByteArray      [3]byte
 	EmptyByteArray [0]byte
@@ -692,6 +778,13 @@ type RequiredOptionalStruct struct {
 	Lastname  string `json:"last_name"`
 }
 
+//easyjson:json
+type RequiredPairStruct struct {
+	ID   int    `json:"id,required"`
+	Name string `json:"name,required"`
+	Note string `json:"note"`
+}
+
 type RequiredOptionalMap struct {
 	ReqMap         map[int]string `json:"req_map,required"`
 	OmitEmptyMap   map[int]string `json:"oe_map,omitempty"`
@@ -752,6 +845,18 @@ type MapUint32String map[uint32]string
 var mapUint32StringValue = MapUint32String{354634382: "life"}
 var mapUint32StringValueString = `{"354634382":"life"}`
 
+//easyjson:json
+type MapUint32Bool map[uint32]bool
+
+var mapUint32BoolValue = MapUint32Bool{354634382: true}
+var mapUint32BoolValueString = `{"354634382":true}`
+
+//easyjson:json
+type MapInt8Uint16Bool map[int8]map[uint16]bool
+
+var mapInt8Uint16BoolValue = MapInt8Uint16Bool{-8: {16: false}}
+var mapInt8Uint16BoolValueString = `{"-8":{"16":false}}`
+
 //easyjson:json
 type MapUint64String map[uint64]string
 
@@ -820,3 +925,32 @@ type MyUInt8Array [2]MyUInt8
 var myUInt8ArrayValue = MyUInt8Array{1, 2}
 
 var myUInt8ArrayString = `[1,2]`
+
+//easyjson:json
+type StringTagged struct {
+	ID   int64   `json:"id,string"`
+	Flag bool    `json:"flag,string"`
+	Rate float64 `json:"rate,string"`
+	Ptr  *uint   `json:"ptr,string"`
+}
+
+var stringTaggedPtr uint = 7
+
+var stringTaggedValue = StringTagged{
+	ID:   42,
+	Flag: true,
+	Rate: 0.5,
+	Ptr:  &stringTaggedPtr,
+}
+
+var stringTaggedString = `{"id":"42","flag":"true","rate":"0.5","ptr":"7"}`
+
+//easyjson:json
+type NullFields struct {
+	Ptr    *int
+	Map    map[string]int
+	Slice  []int
+	Iface  interface{}
+	Int    int
+	Struct SubStruct
+}
@@ -0,0 +1,13 @@
+package tests
+
+//easyjson:json
+type Defaults struct {
+	Retries int     `json:"retries" default:"3"`
+	Timeout uint16  `json:"timeout" default:"30"`
+	Ratio   float32 `json:"ratio" default:"0.75"`
+	Enabled bool    `json:"enabled" default:"true"`
+	Name    string  `json:"name" default:"anonymous \"user\""`
+	Status  Status  `json:"status" default:"2"`
+	Label   Label   `json:"label" default:"none"`
+	Plain   int     `json:"plain"`
+}
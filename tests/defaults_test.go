@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+)
+
+var defaultsValue = Defaults{
+	Retries: 3,
+	Timeout: 30,
+	Ratio:   0.75,
+	Enabled: true,
+	Name:    `anonymous "user"`,
+	Status:  2,
+	Label:   "none",
+}
+
+func TestFieldDefaults(t *testing.T) {
+	for i, test := range []struct {
+		data string
+		want func(*Defaults)
+	}{
+		{data: `{}`},
+		{data: `{"plain":1}`, want: func(d *Defaults) { d.Plain = 1 }},
+		{data: `{"retries":0}`, want: func(d *Defaults) { d.Retries = 0 }},
+		{data: `{"retries":null}`},
+		{data: `{"enabled":false,"name":""}`, want: func(d *Defaults) {
+			d.Enabled = false
+			d.Name = ""
+		}},
+		{
+			data: `{"retries":5,"timeout":1,"ratio":2,"enabled":false,"name":"x","status":7,"label":"on","plain":9}`,
+			want: func(d *Defaults) { *d = Defaults{5, 1, 2, false, "x", 7, "on", 9} },
+		},
+	} {
+		want := defaultsValue
+		if test.want != nil {
+			test.want(&want)
+		}
+
+		// Absent keys are reset to their defaults, not left unchanged.
+		got := Defaults{Retries: 100}
+		if err := got.UnmarshalJSON([]byte(test.data)); err != nil {
+			t.Errorf("[%d] UnmarshalJSON(%s) error: %v", i, test.data, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("[%d] UnmarshalJSON(%s) = %+v; want %+v", i, test.data, got, want)
+		}
+	}
+}
@@ -6,3 +6,7 @@ type DisallowUnknown struct {
 }
 
 var disallowUnknownString = `{"field_one": "one", "field_two": "two"}`
+
+var disallowUnknownNullString = `{"field_one": null, "field_two": null}`
+
+var disallowUnknownKnownString = `{"field_one": null}`
@@ -0,0 +1,13 @@
+package tests
+
+//easyjson:json
+type DuplicateKeys struct {
+	A     int                `json:"a"`
+	B     *string            `json:"b"`
+	Inner DuplicateKeysInner `json:"inner"`
+}
+
+//easyjson:json
+type DuplicateKeysInner struct {
+	C []int `json:"c"`
+}
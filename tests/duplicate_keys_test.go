@@ -0,0 +1,42 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRejectDuplicateKeys(t *testing.T) {
+	for _, data := range []string{
+		`{"a":1,"a":2}`,
+		`{"b":"x","a":1,"b":null}`,
+		`{"b":null,"b":null}`,
+		`{"inner":{"c":[1],"c":[2]}}`,
+		`{"inner":{},"a":1,"inner":{}}`,
+	} {
+		var v DuplicateKeys
+		err := v.UnmarshalJSON([]byte(data))
+		if err == nil || !strings.Contains(err.Error(), "duplicate field") {
+			t.Errorf("UnmarshalJSON(%s) error = %v; want duplicate field error", data, err)
+		}
+	}
+
+	for _, data := range []string{
+		`{"a":1,"b":"x","inner":{"c":[1]}}`,
+		`{"inner":{"c":[1]},"other":1,"other":2}`,
+	} {
+		var v DuplicateKeys
+		if err := v.UnmarshalJSON([]byte(data)); err != nil {
+			t.Errorf("UnmarshalJSON(%s) error: %v", data, err)
+		}
+	}
+}
+
+func TestDuplicateKeysLastWins(t *testing.T) {
+	var v MergeTarget
+	if err := v.UnmarshalJSON([]byte(`{"Count":1,"Count":2}`)); err != nil {
+		t.Fatalf("UnmarshalJSON() error: %v", err)
+	}
+	if v.Count != 2 {
+		t.Errorf("UnmarshalJSON() Count = %d; want 2", v.Count)
+	}
+}
@@ -0,0 +1,11 @@
+package tests
+
+import "time"
+
+//easyjson:json
+type DurationString struct {
+	Timeout   time.Duration
+	Timeouts  []time.Duration
+	Ptr       *time.Duration
+	OmitEmpty time.Duration `json:",omitempty"`
+}
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDurationNumber(t *testing.T) {
+	d := 90 * time.Minute
+	v := DurationNumber{Timeout: d, Timeouts: []time.Duration{time.Second, -time.Millisecond}, Ptr: &d}
+	want := `{"Timeout":5400000000000,"Timeouts":[1000000000,-1000000],"Ptr":5400000000000}`
+
+	data, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if string(data) != want {
+		t.Errorf("MarshalJSON() = %s; want %s", data, want)
+	}
+	std, _ := json.Marshal(struct {
+		Timeout   time.Duration
+		Timeouts  []time.Duration
+		Ptr       *time.Duration
+		OmitEmpty time.Duration `json:",omitempty"`
+	}(v))
+	if string(data) != string(std) {
+		t.Errorf("MarshalJSON() = %s; json.Marshal() = %s", data, std)
+	}
+
+	var got DurationNumber
+	if err := got.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON(%s) error: %v", data, err)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("UnmarshalJSON(%s) = %+v; want %+v", data, got, v)
+	}
+}
+
+func TestDurationString(t *testing.T) {
+	d := 90 * time.Minute
+	v := DurationString{Timeout: d, Timeouts: []time.Duration{time.Second, -time.Millisecond}, Ptr: &d}
+	want := `{"Timeout":"1h30m0s","Timeouts":["1s","-1ms"],"Ptr":"1h30m0s"}`
+
+	data, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if string(data) != want {
+		t.Errorf("MarshalJSON() = %s; want %s", data, want)
+	}
+
+	var got DurationString
+	if err := got.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON(%s) error: %v", data, err)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("UnmarshalJSON(%s) = %+v; want %+v", data, got, v)
+	}
+
+	for _, data := range []string{
+		`{"Timeout":"90 minutes"}`,
+		`{"Timeout":5400000000000}`,
+	} {
+		if err := new(DurationString).UnmarshalJSON([]byte(data)); err == nil {
+			t.Errorf("UnmarshalJSON(%s) ok; want error", data)
+		}
+	}
+}
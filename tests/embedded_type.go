@@ -28,3 +28,132 @@ func init() {
 }
 
 var embeddedTypeValueString = `{"Inner":{"Field1":3},"Field2":2,"named":{"Field3":4},"Field1":1}`
+
+type EmbeddedPtrBase struct {
+	BaseField int `json:"baseField"`
+}
+
+//easyjson:json
+type EmbeddedPtrType struct {
+	*EmbeddedPtrBase
+	Other int `json:"other"`
+}
+
+//easyjson:json
+type EmbeddedPtrsType struct {
+	*EmbeddedPtrBase
+	*EmbeddedInnerType
+}
+
+type EmbeddedLevel3 struct {
+	Name  string
+	Deep  int
+	Title string `json:"label"`
+}
+
+type EmbeddedLevel2 struct {
+	EmbeddedLevel3
+	Middle int
+}
+
+// EmbeddedLevels has fields shadowing the ones promoted from two levels down,
+// by Go name and by JSON name.
+//
+//easyjson:json
+type EmbeddedLevels struct {
+	EmbeddedLevel2
+	Name  string
+	Label string `json:"label"`
+}
+
+var embeddedLevelsValue = EmbeddedLevels{
+	EmbeddedLevel2: EmbeddedLevel2{
+		EmbeddedLevel3: EmbeddedLevel3{Deep: 3},
+		Middle:         2,
+	},
+	Name:  "top",
+	Label: "top label",
+}
+
+var embeddedLevelsString = `{"Name":"top","label":"top label","Middle":2,"Deep":3}`
+
+type EmbeddedSiblingA struct {
+	ID    int
+	Value int
+}
+
+type EmbeddedSiblingB struct {
+	ID int
+	B  int
+}
+
+type EmbeddedSiblingDeep struct {
+	EmbeddedSiblingA
+}
+
+// EmbeddedSiblings has an ID field that is ambiguous at the same depth and a
+// Value field promoted from different depths.
+//
+//easyjson:json
+type EmbeddedSiblings struct {
+	EmbeddedSiblingA
+	EmbeddedSiblingB
+	EmbeddedSiblingDeep
+}
+
+var embeddedSiblingsValue = EmbeddedSiblings{
+	EmbeddedSiblingA: EmbeddedSiblingA{Value: 1},
+	EmbeddedSiblingB: EmbeddedSiblingB{B: 2},
+}
+
+var embeddedSiblingsString = `{"B":2,"Value":1}`
+
+//easyjson:json
+type EmbeddedNode struct {
+	*EmbeddedNode
+	Value int
+}
+
+var embeddedNodeValue = EmbeddedNode{Value: 1}
+
+var embeddedNodeString = `{"Value":1}`
+
+type EmbeddedTaggedB struct {
+	ID int `json:"b_id"`
+}
+
+type EmbeddedTaggedC struct {
+	ID int `json:"c_id"`
+}
+
+// EmbeddedTagged has promoted fields of the same Go name at the same depth,
+// which are kept as they have different JSON names.
+//
+//easyjson:json
+type EmbeddedTagged struct {
+	*EmbeddedTaggedC
+	EmbeddedTaggedB
+}
+
+var embeddedTaggedValue = EmbeddedTagged{
+	EmbeddedTaggedC: &EmbeddedTaggedC{ID: 2},
+	EmbeddedTaggedB: EmbeddedTaggedB{ID: 1},
+}
+
+var embeddedTaggedString = `{"b_id":1,"c_id":2}`
+
+type EmbeddedRequiredA struct {
+	X int `json:"a,required"`
+}
+
+type EmbeddedRequiredB struct {
+	X int `json:"b,required"`
+}
+
+// EmbeddedRequired has required promoted fields of the same Go name.
+//
+//easyjson:json
+type EmbeddedRequired struct {
+	EmbeddedRequiredA
+	EmbeddedRequiredB
+}
@@ -0,0 +1,19 @@
+package tests
+
+//easyjson:json
+type PathOrder struct {
+	ID     int               `json:"id"`
+	Items  []PathItem        `json:"items"`
+	Totals [2]int            `json:"totals"`
+	Extra  map[string]string `json:"extra"`
+	ByID   map[int]PathMeta  `json:"by_id"`
+}
+
+type PathItem struct {
+	SKU  string   `json:"sku"`
+	Meta PathMeta `json:"meta"`
+}
+
+type PathMeta struct {
+	Name string `json:"name"`
+}
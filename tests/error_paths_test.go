@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mailru/easyjson/jlexer"
+)
+
+func TestErrorPaths(t *testing.T) {
+	for _, test := range []struct {
+		data string
+		path string
+	}{
+		{data: `{"id":"1"}`, path: "$.id"},
+		{data: `{"id":1,"items":[{"sku":"a"},{"sku":"b","meta":{"name":7}}]}`, path: "$.items[1].meta.name"},
+		{data: `{"items":[{"sku":"a","meta":null},{"sku":2}]}`, path: "$.items[1].sku"},
+		{data: `{"unknown":{"a":1},"totals":[1,"2"]}`, path: "$.totals[1]"},
+		{data: `{"extra":{"a":"x","content-type":false}}`, path: `$.extra["content-type"]`},
+		{data: `{"by_id":{"3":{"name":[]}}}`, path: "$.by_id[\"3\"].name"},
+	} {
+		var v PathOrder
+		err := v.UnmarshalJSON([]byte(test.data))
+		lexErr, ok := err.(*jlexer.LexerError)
+		if !ok {
+			t.Errorf("UnmarshalJSON(%s) error = %v; want *jlexer.LexerError", test.data, err)
+			continue
+		}
+		if lexErr.Path != test.path {
+			t.Errorf("UnmarshalJSON(%s) error path = %q; want %q", test.data, lexErr.Path, test.path)
+		}
+		if !strings.Contains(err.Error(), " at "+test.path+" ") {
+			t.Errorf("UnmarshalJSON(%s) error = %q; want the path %s in it", test.data, err, test.path)
+		}
+	}
+
+	var v PathOrder
+	if err := v.UnmarshalJSON([]byte(`{"id":1,"items":[{"meta":{"name":"a"}}],"by_id":{"1":{}}}`)); err != nil {
+		t.Errorf("UnmarshalJSON() error: %v", err)
+	}
+}
@@ -24,3 +24,12 @@ type ErrorNestedStruct struct {
 
 //easyjson:json
 type ErrorIntMap map[uint32]string
+
+type ErrorIntRanges struct {
+	Int8   int8   `json:"int8"`
+	Uint16 uint16 `json:"uint16"`
+	Int32  int32  `json:"int32"`
+	Int    int    `json:"int"`
+	Uint   uint   `json:"uint"`
+	Uint8  uint8  `json:"uint8,string"`
+}
@@ -283,3 +283,69 @@ func TestMultipleErrorsIntMap(t *testing.T) {
 		}
 	}
 }
+
+func TestIntRanges(t *testing.T) {
+	for i, test := range []struct {
+		Data      string
+		Want      ErrorIntRanges
+		WantError string
+	}{
+		{
+			Data: `{"int8":-128,"uint16":65535,"int32":-2147483648,"uint8":"255"}`,
+			Want: ErrorIntRanges{Int8: -128, Uint16: 65535, Int32: -2147483648, Uint8: 255},
+		},
+		{
+			Data: `{"int8":127,"uint16":0,"int32":2147483647}`,
+			Want: ErrorIntRanges{Int8: 127, Int32: 2147483647},
+		},
+		{
+			Data:      `{"int8":128}`,
+			WantError: `parse error: strconv.ParseInt: parsing "128": value out of range near offset 8 of '128'`,
+		},
+		{
+			Data:      `{"int8":-129}`,
+			WantError: `parse error: strconv.ParseInt: parsing "-129": value out of range near offset 8 of '-129'`,
+		},
+		{
+			Data:      `{"uint16":65536}`,
+			WantError: `parse error: strconv.ParseUint: parsing "65536": value out of range near offset 10 of '65536'`,
+		},
+		{
+			Data:      `{"uint16":-1}`,
+			WantError: `parse error: strconv.ParseUint: parsing "-1": invalid syntax near offset 10 of '-1'`,
+		},
+		{
+			Data:      `{"int32":2147483648}`,
+			WantError: `parse error: strconv.ParseInt: parsing "2147483648": value out of range near offset 9 of '2147483648'`,
+		},
+		{
+			Data:      `{"int32":-2147483649}`,
+			WantError: `parse error: strconv.ParseInt: parsing "-2147483649": value out of range near offset 9 of '-2147483649'`,
+		},
+		{
+			Data:      `{"uint8":"256"}`,
+			WantError: `parse error: strconv.ParseUint: parsing "256": value out of range near offset 9 of '256'`,
+		},
+		{
+			Data:      `{"int":9223372036854775808}`,
+			WantError: `parse error: strconv.ParseInt: parsing "9223372036854775808": value out of range near offset 7 of '9223372036854775808'`,
+		},
+		{
+			Data:      `{"uint":18446744073709551616}`,
+			WantError: `parse error: strconv.ParseUint: parsing "18446744073709551616": value out of range near offset 8 of '18446744073709551616'`,
+		},
+	} {
+		var v ErrorIntRanges
+		err := v.UnmarshalJSON([]byte(test.Data))
+		if test.WantError == "" {
+			if err != nil {
+				t.Errorf("[%d] UnmarshalJSON(%v) error: %v", i, test.Data, err)
+			}
+			if v != test.Want {
+				t.Errorf("[%d] UnmarshalJSON(%v) = %+v; want %+v", i, test.Data, v, test.Want)
+			}
+		} else if err == nil || err.Error() != test.WantError {
+			t.Errorf("[%d] UnmarshalJSON(%v) error = %v; want %v", i, test.Data, err, test.WantError)
+		}
+	}
+}
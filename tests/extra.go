@@ -0,0 +1,10 @@
+package tests
+
+import "encoding/json"
+
+//easyjson:json
+type ExtraFields struct {
+	ID    int                        `json:"id"`
+	Name  string                     `json:"name,omitempty"`
+	Extra map[string]json.RawMessage `json:",extra"`
+}
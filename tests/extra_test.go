@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExtraFieldsRoundTrip(t *testing.T) {
+	data := `{"id":7,"name":"x","tags":["a","b"],"meta":{"k":null},"score":1.5}`
+
+	var v ExtraFields
+	if err := v.UnmarshalJSON([]byte(data)); err != nil {
+		t.Fatalf("UnmarshalJSON() error: %v", err)
+	}
+	if v.ID != 7 || v.Name != "x" {
+		t.Errorf("UnmarshalJSON() known fields = %d, %q; want 7, \"x\"", v.ID, v.Name)
+	}
+	wantExtra := map[string]string{
+		"tags":  `["a","b"]`,
+		"meta":  `{"k":null}`,
+		"score": `1.5`,
+	}
+	if len(v.Extra) != len(wantExtra) {
+		t.Errorf("UnmarshalJSON() extra = %d keys; want %d", len(v.Extra), len(wantExtra))
+	}
+	for k, want := range wantExtra {
+		if got := string(v.Extra[k]); got != want {
+			t.Errorf("UnmarshalJSON() extra[%q] = %s; want %s", k, got, want)
+		}
+	}
+
+	// The extra keys are written after the fields, sorted.
+	want := `{"id":7,"name":"x","meta":{"k":null},"score":1.5,"tags":["a","b"]}`
+	got, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("MarshalJSON() = %s; want %s", got, want)
+	}
+}
+
+func TestExtraFieldsCopied(t *testing.T) {
+	data := []byte(`{"id":1,"other":"value","gone":null}`)
+
+	var v ExtraFields
+	if err := v.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON() error: %v", err)
+	}
+	for i := range data {
+		data[i] = ' '
+	}
+	if got := string(v.Extra["other"]); got != `"value"` {
+		t.Errorf("extra[\"other\"] = %s after reusing the input; want \"value\"", got)
+	}
+	if raw, ok := v.Extra["gone"]; !ok || raw != nil {
+		t.Errorf("extra[\"gone\"] = %q, %v; want nil, true", raw, ok)
+	}
+
+	got, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if want := `{"id":1,"gone":null,"other":"value"}`; string(got) != want {
+		t.Errorf("MarshalJSON() = %s; want %s", got, want)
+	}
+}
+
+func TestExtraFieldsEmpty(t *testing.T) {
+	v := ExtraFields{ID: 2}
+	got, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if want := `{"id":2}`; string(got) != want {
+		t.Errorf("MarshalJSON() = %s; want %s", got, want)
+	}
+
+	var v2 ExtraFields
+	if err := v2.UnmarshalJSON(got); err != nil {
+		t.Fatalf("UnmarshalJSON() error: %v", err)
+	}
+	if v2.Extra != nil {
+		t.Errorf("UnmarshalJSON() extra = %v; want nil", v2.Extra)
+	}
+
+	// A value without extra keys encodes like encoding/json does.
+	std, _ := json.Marshal(struct {
+		ID int `json:"id"`
+	}{2})
+	if string(std) != string(got) {
+		t.Errorf("MarshalJSON() = %s; encoding/json %s", got, std)
+	}
+}
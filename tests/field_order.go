@@ -0,0 +1,45 @@
+package tests
+
+//easyjson:json
+type FieldOrder struct {
+	Zulu    string
+	Alpha   int
+	Yankee  bool
+	Bravo   float64
+	Xray    []string
+	Charlie map[string]int
+	Whiskey *int
+	Delta   string `json:"echo"`
+	Victor  uint8
+	Foxtrot string `json:",omitempty"`
+}
+
+var fieldOrderWhiskey = 7
+
+var fieldOrderValue = FieldOrder{
+	Zulu:    "z",
+	Alpha:   1,
+	Yankee:  true,
+	Bravo:   2.5,
+	Xray:    []string{"x"},
+	Charlie: map[string]int{"c": 3},
+	Whiskey: &fieldOrderWhiskey,
+	Delta:   "d",
+	Victor:  9,
+	Foxtrot: "f",
+}
+
+// The keys must come out in the declaration order of the fields, regardless
+// of their names.
+var fieldOrderString = `{` +
+	`"Zulu":"z",` +
+	`"Alpha":1,` +
+	`"Yankee":true,` +
+	`"Bravo":2.5,` +
+	`"Xray":["x"],` +
+	`"Charlie":{"c":3},` +
+	`"Whiskey":7,` +
+	`"echo":"d",` +
+	`"Victor":9,` +
+	`"Foxtrot":"f"` +
+	`}`
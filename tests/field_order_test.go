@@ -0,0 +1,19 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFieldOrderMatchesStd(t *testing.T) {
+	// A type without the generated methods, so encoding/json uses reflection.
+	type fieldOrderStd FieldOrder
+
+	data, err := json.Marshal(fieldOrderStd(fieldOrderValue))
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	if string(data) != fieldOrderString {
+		t.Errorf("json.Marshal() = %s; want %s", data, fieldOrderString)
+	}
+}
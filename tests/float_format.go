@@ -0,0 +1,9 @@
+package tests
+
+//easyjson:json
+type FloatFormatted struct {
+	F64   float64
+	F32   float32
+	Str   float64 `json:",string"`
+	Slice []float64
+}
@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/mailru/easyjson/jwriter"
+)
+
+func TestFloatDefaultFormat(t *testing.T) {
+	for _, n := range []float64{0.1, 1e20, 3.14159, 0, -0.5, 1e21, 1e-6, 1e-7, 123456789, 2.5e-8, math.MaxFloat32, math.SmallestNonzeroFloat64} {
+		want, err := json.Marshal(n)
+		if err != nil {
+			t.Fatalf("json.Marshal(%v) error: %v", n, err)
+		}
+		w := jwriter.Writer{}
+		w.Float64(n)
+		if got := string(w.Buffer.BuildBytes()); got != string(want) {
+			t.Errorf("Float64(%v) = %s; want %s", n, got, want)
+		}
+
+		want, err = json.Marshal(float32(n))
+		if err != nil {
+			t.Fatalf("json.Marshal(float32(%v)) error: %v", n, err)
+		}
+		w = jwriter.Writer{}
+		w.Float32(float32(n))
+		if got := string(w.Buffer.BuildBytes()); got != string(want) {
+			t.Errorf("Float32(%v) = %s; want %s", float32(n), got, want)
+		}
+	}
+}
+
+func TestFloatFormat(t *testing.T) {
+	for _, test := range []struct {
+		n    float64
+		want string
+	}{
+		{0.1, `{"F64":0.10,"F32":0.10,"Str":"0.10","Slice":[0.10]}`},
+		{1e20, `{"F64":100000000000000000000.00,"F32":100000002004087734272.00,"Str":"100000000000000000000.00","Slice":[100000000000000000000.00]}`},
+		{3.14159, `{"F64":3.14,"F32":3.14,"Str":"3.14","Slice":[3.14]}`},
+	} {
+		v := FloatFormatted{F64: test.n, F32: float32(test.n), Str: test.n, Slice: []float64{test.n}}
+		data, err := v.MarshalJSON()
+		if err != nil {
+			t.Errorf("MarshalJSON() of %v error: %v", test.n, err)
+		} else if string(data) != test.want {
+			t.Errorf("MarshalJSON() of %v = %s; want %s", test.n, data, test.want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package tests
+
+import "strconv"
+
+//easyjson:json
+type RoundTripRecord struct {
+	Name  string           `json:"name"`
+	Tags  []string         `json:"tags,omitempty"`
+	Meta  map[string]int   `json:"meta"`
+	Ratio float64          `json:"ratio"`
+	Next  *RoundTripRecord `json:"next"`
+	Vals  map[int]string   `json:"vals"`
+}
+
+//easyjson:json
+type RoundTripLossy struct {
+	Count LossyCounter `json:"count"`
+}
+
+// LossyCounter is encoded as one more than its value, so it does not survive a
+// round-trip.
+type LossyCounter int
+
+func (c LossyCounter) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(c) + 1)), nil
+}
+
+func (c *LossyCounter) UnmarshalJSON(data []byte) error {
+	n, err := strconv.Atoi(string(data))
+	*c = LossyCounter(n)
+	return err
+}
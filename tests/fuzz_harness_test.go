@@ -0,0 +1,34 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFuzzHarness(t *testing.T) {
+	for _, test := range []struct {
+		data string
+		want int
+	}{
+		{data: `{"name":"a","tags":["x","y"],"meta":{"b":2,"a":1},"ratio":0.5,"next":{"name":"b"}}`, want: 1},
+		{data: `{"vals":{"1":"a","2":"b","3":"c","4":"d","5":"e","6":"f","7":"g","8":"h"}}`, want: 1},
+		{data: `{}`, want: 1},
+		{data: `null`, want: 1},
+		{data: `{"name":1}`, want: 0},
+		{data: `{"name":"a"`, want: 0},
+	} {
+		if got := FuzzRoundTripRecord([]byte(test.data)); got != test.want {
+			t.Errorf("FuzzRoundTripRecord(%s) = %d; want %d", test.data, got, test.want)
+		}
+	}
+}
+
+func TestFuzzHarnessMismatch(t *testing.T) {
+	defer func() {
+		r := recover()
+		if msg, _ := r.(string); !strings.Contains(msg, "round-trip mismatch") {
+			t.Errorf("FuzzRoundTripLossy() panicked with %v; want a round-trip mismatch", r)
+		}
+	}()
+	FuzzRoundTripLossy([]byte(`{"count":1}`))
+}
@@ -0,0 +1,15 @@
+//go:build go1.18
+// +build go1.18
+
+package tests
+
+type Box[T any] struct {
+	Val T
+}
+
+//easyjson:json
+type GenericBoxes struct {
+	Str   Box[string]
+	Int   Box[int]
+	Inner *Box[Box[[]string]]
+}
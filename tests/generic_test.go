@@ -0,0 +1,34 @@
+//go:build go1.18
+// +build go1.18
+
+package tests
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGenericInstantiations(t *testing.T) {
+	v := GenericBoxes{
+		Str:   Box[string]{Val: "a"},
+		Int:   Box[int]{Val: 5},
+		Inner: &Box[Box[[]string]]{Val: Box[[]string]{Val: []string{"b", "c"}}},
+	}
+	want := `{"Str":{"Val":"a"},"Int":{"Val":5},"Inner":{"Val":{"Val":["b","c"]}}}`
+
+	data, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if string(data) != want {
+		t.Errorf("MarshalJSON() = %s; want %s", data, want)
+	}
+
+	var got GenericBoxes
+	if err := got.UnmarshalJSON([]byte(want)); err != nil {
+		t.Fatalf("UnmarshalJSON(%s) error: %v", want, err)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("UnmarshalJSON(%s) = %+v; want %+v", want, got, v)
+	}
+}
@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+)
+
+//easyjson:json
+type TrimmedNames struct {
+	Name    string
+	Aliases []string
+}
+
+// AfterUnmarshal trims whitespace around the decoded names.
+func (v *TrimmedNames) AfterUnmarshal() error {
+	v.Name = strings.TrimSpace(v.Name)
+	for i := range v.Aliases {
+		v.Aliases[i] = strings.TrimSpace(v.Aliases[i])
+	}
+	return nil
+}
+
+// BeforeMarshal uppercases the copy of the name being encoded.
+func (v *TrimmedNames) BeforeMarshal() error {
+	v.Name = strings.ToUpper(v.Name)
+	return nil
+}
+
+var errBadRange = errors.New("min is greater than max")
+
+//easyjson:json
+type CheckedRange struct {
+	Min int
+	Max int
+}
+
+func (v CheckedRange) check() error {
+	if v.Min > v.Max {
+		return errBadRange
+	}
+	return nil
+}
+
+// AfterUnmarshal rejects decoded ranges where Min is greater than Max.
+func (v CheckedRange) AfterUnmarshal() error {
+	return v.check()
+}
+
+// BeforeMarshal refuses to encode ranges where Min is greater than Max.
+func (v CheckedRange) BeforeMarshal() error {
+	return v.check()
+}
+
+//easyjson:json
+type CheckedRanges struct {
+	Ranges []CheckedRange
+}
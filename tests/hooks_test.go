@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mailru/easyjson"
+)
+
+func TestAfterUnmarshal(t *testing.T) {
+	var v TrimmedNames
+	err := easyjson.Unmarshal([]byte(`{"Name":"  foo ","Aliases":[" bar","baz  "]}`), &v)
+	if err != nil {
+		t.Fatalf("easyjson.Unmarshal() error: %v", err)
+	}
+	want := TrimmedNames{Name: "foo", Aliases: []string{"bar", "baz"}}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("easyjson.Unmarshal() got %+v; want %+v", v, want)
+	}
+
+	var r CheckedRange
+	if err := easyjson.Unmarshal([]byte(`{"Min":1,"Max":2}`), &r); err != nil {
+		t.Errorf("easyjson.Unmarshal() error: %v", err)
+	}
+	if err := easyjson.Unmarshal([]byte(`{"Min":3,"Max":2}`), &r); err != errBadRange {
+		t.Errorf("easyjson.Unmarshal() error %v; want %v", err, errBadRange)
+	}
+
+	var rs CheckedRanges
+	err = easyjson.Unmarshal([]byte(`{"Ranges":[{"Min":1,"Max":2},{"Min":3,"Max":2}]}`), &rs)
+	if err != errBadRange {
+		t.Errorf("easyjson.Unmarshal() of nested ranges error %v; want %v", err, errBadRange)
+	}
+
+	// The hook does not run if decoding failed.
+	if err := easyjson.Unmarshal([]byte(`{"Min":3,"Max":"x"}`), &r); err == nil || err == errBadRange {
+		t.Errorf("easyjson.Unmarshal() error %v; want a lexer error", err)
+	}
+}
+
+func TestBeforeMarshal(t *testing.T) {
+	v := TrimmedNames{Name: "foo"}
+	data, err := easyjson.Marshal(v)
+	if err != nil {
+		t.Fatalf("easyjson.Marshal() error: %v", err)
+	}
+	if want := `{"Name":"FOO","Aliases":null}`; string(data) != want {
+		t.Errorf("easyjson.Marshal() = %s; want %s", data, want)
+	}
+	if v.Name != "foo" {
+		t.Errorf("easyjson.Marshal() changed the value to %+v", v)
+	}
+
+	if _, err := easyjson.Marshal(CheckedRange{Min: 1, Max: 2}); err != nil {
+		t.Errorf("easyjson.Marshal() error: %v", err)
+	}
+	rs := CheckedRanges{Ranges: []CheckedRange{{Min: 1, Max: 2}, {Min: 3, Max: 2}}}
+	if _, err := easyjson.Marshal(rs); err != errBadRange {
+		t.Errorf("easyjson.Marshal() error %v; want %v", err, errBadRange)
+	}
+}
@@ -0,0 +1,6 @@
+package tests
+
+//easyjson:json
+type NoEscapeHTMLStruct struct {
+	Test string
+}
@@ -31,3 +31,21 @@ func TestHTML(t *testing.T) {
 		t.Fatal("NoEscapeHTML error:", string(data))
 	}
 }
+
+func TestHTMLEscapeMarshalJSON(t *testing.T) {
+	for i, test := range []struct {
+		marshaler interface{ MarshalJSON() ([]byte, error) }
+		want      string
+	}{
+		{Struct{Test: "<script>&"}, `{"Test":"\u003cscript\u003e\u0026"}`},
+		{NoEscapeHTMLStruct{Test: "<script>&"}, `{"Test":"<script>&"}`},
+	} {
+		data, err := test.marshaler.MarshalJSON()
+		if err != nil {
+			t.Errorf("[%d] MarshalJSON() error: %v", i, err)
+		}
+		if string(data) != test.want {
+			t.Errorf("[%d] MarshalJSON() = %s; want %s", i, data, test.want)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package tests
+
+type IndentInner struct {
+	Name string
+	Tags []string
+}
+
+//easyjson:json
+type IndentStruct struct {
+	Inner      IndentInner
+	List       []IndentInner
+	EmptyList  []int
+	EmptyMap   map[string]int
+	EmptyInner struct{}
+	Values     map[string][]int
+}
+
+var indentStructValue = IndentStruct{
+	Inner:     IndentInner{Name: "first", Tags: []string{"a", "b"}},
+	List:      []IndentInner{{Name: "second"}, {Name: "third", Tags: []string{}}},
+	EmptyList: []int{},
+	EmptyMap:  map[string]int{},
+	Values:    map[string][]int{"x": {1, 2}},
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/mailru/easyjson/jwriter"
+)
+
+func TestIndentGolden(t *testing.T) {
+	want, err := ioutil.ReadFile("testdata/indent.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := indentStructValue.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("MarshalJSON() = %s; want %s", got, want)
+	}
+
+	std, err := json.MarshalIndent(indentStructValue, "", "  ")
+	if err != nil {
+		t.Fatalf("json.MarshalIndent() error: %v", err)
+	}
+	if string(got) != string(std) {
+		t.Errorf("MarshalJSON() = %s; json.MarshalIndent() = %s", got, std)
+	}
+}
+
+func TestWriterIndentPrefix(t *testing.T) {
+	w := jwriter.Writer{Prefix: "// ", Indent: "\t"}
+	kebabStructValue.MarshalEasyJSON(&w)
+
+	got, err := w.BuildBytes()
+	if err != nil {
+		t.Fatalf("BuildBytes() error: %v", err)
+	}
+
+	want := "{\n// \t\"http-server-name\": \"srv\",\n// \t\"content-type\": \"text/plain\",\n// \t\"cUsToM\": \"\"\n// }"
+	if string(got) != want {
+		t.Errorf("BuildBytes() = %q; want %q", got, want)
+	}
+}
+
+func TestWriterIndentUnsupported(t *testing.T) {
+	var sink bytes.Buffer
+	streaming := jwriter.NewStreamingWriter(&sink)
+	streaming.Indent = "  "
+	kebabStructValue.MarshalEasyJSON(streaming)
+	if err := streaming.Flush(); err == nil {
+		t.Errorf("Flush() of a streaming writer with Indent ok, wrote %s; want error", sink.Bytes())
+	}
+
+	pooled := jwriter.GetWriter()
+	defer jwriter.PutWriter(pooled)
+	pooled.Indent = "  "
+	kebabStructValue.MarshalEasyJSON(pooled)
+	if got, err := pooled.BuildBytes(); err == nil {
+		t.Errorf("BuildBytes() of a pooled writer with Indent = %s; want error", got)
+	}
+	if _, err := pooled.DumpTo(&sink); err == nil {
+		t.Errorf("DumpTo() of a pooled writer with Indent ok; want error")
+	}
+}
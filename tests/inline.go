@@ -0,0 +1,18 @@
+package tests
+
+type InlineMeta struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type InlineSpec struct {
+	Replicas int `json:"replicas"`
+}
+
+//easyjson:json
+type InlineObject struct {
+	Kind  string                 `json:"kind"`
+	Meta  InlineMeta             `json:",inline"`
+	Spec  *InlineSpec            `json:"spec,inline"`
+	Extra map[string]interface{} `json:",inline"`
+}
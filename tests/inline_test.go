@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInlineEncode(t *testing.T) {
+	for i, test := range []struct {
+		v    InlineObject
+		want string
+	}{
+		{
+			v:    InlineObject{Kind: "Pod", Meta: InlineMeta{Name: "web"}},
+			want: `{"kind":"Pod","name":"web"}`,
+		},
+		{
+			v: InlineObject{
+				Kind:  "Pod",
+				Meta:  InlineMeta{Name: "web", Labels: map[string]string{"app": "web"}},
+				Spec:  &InlineSpec{Replicas: 3},
+				Extra: map[string]interface{}{"zone": "b", "audit": true},
+			},
+			want: `{"kind":"Pod","replicas":3,"name":"web","labels":{"app":"web"},"audit":true,"zone":"b"}`,
+		},
+		{
+			v:    InlineObject{Extra: map[string]interface{}{"x": 1}},
+			want: `{"kind":"","name":"","x":1}`,
+		},
+	} {
+		data, err := test.v.MarshalJSON()
+		if err != nil {
+			t.Errorf("[%d] MarshalJSON() error: %v", i, err)
+			continue
+		}
+		if string(data) != test.want {
+			t.Errorf("[%d] MarshalJSON() = %s; want %s", i, data, test.want)
+		}
+	}
+}
+
+func TestInlineDecode(t *testing.T) {
+	for i, test := range []struct {
+		data string
+		want InlineObject
+	}{
+		{
+			data: `{"kind":"Pod","name":"web"}`,
+			want: InlineObject{Kind: "Pod", Meta: InlineMeta{Name: "web"}},
+		},
+		{
+			data: `{"zone":"b","kind":"Pod","replicas":3,"name":"web","audit":true,"gone":null,"nested":{"a":[1]}}`,
+			want: InlineObject{
+				Kind: "Pod",
+				Meta: InlineMeta{Name: "web"},
+				Spec: &InlineSpec{Replicas: 3},
+				Extra: map[string]interface{}{
+					"zone":   "b",
+					"audit":  true,
+					"gone":   nil,
+					"nested": map[string]interface{}{"a": []interface{}{1.0}},
+				},
+			},
+		},
+		{
+			// Keys of the inline struct fields and nulls for known fields are
+			// not caught by the map.
+			data: `{"name":"web","labels":null,"spec":{}}`,
+			want: InlineObject{
+				Meta:  InlineMeta{Name: "web"},
+				Extra: map[string]interface{}{"spec": map[string]interface{}{}},
+			},
+		},
+	} {
+		var got InlineObject
+		if err := got.UnmarshalJSON([]byte(test.data)); err != nil {
+			t.Errorf("[%d] UnmarshalJSON(%s) error: %v", i, test.data, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("[%d] UnmarshalJSON(%s) = %+v; want %+v", i, test.data, got, test.want)
+		}
+	}
+}
+
+func TestInlineRoundTrip(t *testing.T) {
+	data := `{"kind":"Pod","replicas":1,"name":"web","a":"1","b":[2],"c":{"d":null}}`
+
+	var v InlineObject
+	if err := v.UnmarshalJSON([]byte(data)); err != nil {
+		t.Fatalf("UnmarshalJSON() error: %v", err)
+	}
+	got, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if string(got) != data {
+		t.Errorf("MarshalJSON() = %s; want %s", got, data)
+	}
+}
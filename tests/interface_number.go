@@ -0,0 +1,7 @@
+package tests
+
+//easyjson:json
+type InterfaceNumbers struct {
+	X interface{}
+	M map[string]interface{}
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mailru/easyjson/jlexer"
+)
+
+const interfaceNumbersString = `{"X": 123456789012345678, "M": {"a": [1.5, -2]}}`
+
+func TestInterfaceNumbersFloat64(t *testing.T) {
+	var v InterfaceNumbers
+	if err := v.UnmarshalJSON([]byte(interfaceNumbersString)); err != nil {
+		t.Fatalf("UnmarshalJSON() error: %v", err)
+	}
+
+	type std InterfaceNumbers
+	var want std
+	if err := json.Unmarshal([]byte(interfaceNumbersString), &want); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+	if !reflect.DeepEqual(v, InterfaceNumbers(want)) {
+		t.Errorf("UnmarshalJSON() got %#v; want %#v", v, want)
+	}
+
+	// float64 cannot hold the number exactly.
+	if x, ok := v.X.(float64); !ok || int64(x) == 123456789012345678 {
+		t.Errorf("UnmarshalJSON() got X %#v; want an inexact float64", v.X)
+	}
+}
+
+func TestInterfaceNumbersJSONNumber(t *testing.T) {
+	var v InterfaceJSONNumbers
+	if err := v.UnmarshalJSON([]byte(interfaceNumbersString)); err != nil {
+		t.Fatalf("UnmarshalJSON() error: %v", err)
+	}
+
+	type std InterfaceJSONNumbers
+	var want std
+	dec := json.NewDecoder(bytes.NewReader([]byte(interfaceNumbersString)))
+	dec.UseNumber()
+	if err := dec.Decode(&want); err != nil {
+		t.Fatalf("json.Decoder.Decode() error: %v", err)
+	}
+	if !reflect.DeepEqual(v, InterfaceJSONNumbers(want)) {
+		t.Errorf("UnmarshalJSON() got %#v; want %#v", v, want)
+	}
+
+	x, ok := v.X.(json.Number)
+	if !ok {
+		t.Fatalf("UnmarshalJSON() got X %#v; want a json.Number", v.X)
+	}
+	if n, err := x.Int64(); err != nil || n != 123456789012345678 {
+		t.Errorf("X.Int64() = %v, %v; want 123456789012345678, nil", n, err)
+	}
+
+	// The mode only applies to UnmarshalJSON, the lexer decides otherwise.
+	var lv InterfaceNumbers
+	l := jlexer.Lexer{Data: []byte(interfaceNumbersString), UseNumber: true}
+	lv.UnmarshalEasyJSON(&l)
+	if _, ok := lv.X.(json.Number); !ok || l.Error() != nil {
+		t.Errorf("UnmarshalEasyJSON() with UseNumber got X %#v, %v; want a json.Number", lv.X, l.Error())
+	}
+}
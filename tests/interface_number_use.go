@@ -0,0 +1,7 @@
+package tests
+
+//easyjson:json
+type InterfaceJSONNumbers struct {
+	X interface{}
+	M map[string]interface{}
+}
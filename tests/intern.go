@@ -10,5 +10,10 @@ type Intern struct {
 	Field string `json:"field,intern"`
 }
 
+//easyjson:json
+type NoInternMapKeys struct {
+	Maps []map[string]int `json:"maps"`
+}
+
 var intern = Intern{Field: "interned"}
 var internString = `{"field":"interned"}`
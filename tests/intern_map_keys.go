@@ -0,0 +1,6 @@
+package tests
+
+//easyjson:json
+type InternMapKeys struct {
+	Maps []map[string]int `json:"maps"`
+}
@@ -1,6 +1,9 @@
 package tests
 
 import (
+	"bytes"
+	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/mailru/easyjson"
@@ -39,3 +42,62 @@ func TestStringIntern(t *testing.T) {
 		t.Fatalf("expected <= 2 allocs, got %f", allocsPerRun)
 	}
 }
+
+// duplicateMapKeysJSON is a map with 1000 keys using only 10 distinct values.
+var duplicateMapKeysJSON = func() []byte {
+	var b bytes.Buffer
+	b.WriteString(`{"maps":[{`)
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `"key%d":%d`, i%10, i)
+	}
+	b.WriteString(`}]}`)
+	return b.Bytes()
+}()
+
+func TestInternMapKeys(t *testing.T) {
+	var interned InternMapKeys
+	internedAllocs := testing.AllocsPerRun(100, func() {
+		interned = InternMapKeys{}
+		if err := easyjson.Unmarshal(duplicateMapKeysJSON, &interned); err != nil {
+			t.Error(err)
+		}
+	})
+
+	var plain NoInternMapKeys
+	plainAllocs := testing.AllocsPerRun(100, func() {
+		plain = NoInternMapKeys{}
+		if err := easyjson.Unmarshal(duplicateMapKeysJSON, &plain); err != nil {
+			t.Error(err)
+		}
+	})
+
+	if !reflect.DeepEqual(interned.Maps, plain.Maps) {
+		t.Errorf("decoded maps differ: %v != %v", interned.Maps, plain.Maps)
+	}
+	if internedAllocs+900 > plainAllocs {
+		t.Errorf("expected interning to save at least 900 allocs, got %f with interning, %f without", internedAllocs, plainAllocs)
+	}
+}
+
+func BenchmarkInternMapKeys(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var v InternMapKeys
+		if err := easyjson.Unmarshal(duplicateMapKeysJSON, &v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNoInternMapKeys(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var v NoInternMapKeys
+		if err := easyjson.Unmarshal(duplicateMapKeysJSON, &v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package tests
+
+// Amount is empty without cents, whatever its currency.
+type Amount struct {
+	Cents    int64
+	Currency string
+}
+
+func (a Amount) IsEmpty() bool {
+	return a.Cents == 0
+}
+
+// Placeholder is empty if it is "-".
+type Placeholder string
+
+func (p Placeholder) IsEmpty() bool {
+	return p == "-"
+}
+
+//easyjson:json
+type IsEmptyFields struct {
+	Price   Amount      `json:",omitempty"`
+	Note    Placeholder `json:",omitempty"`
+	Total   Amount
+	Pointer *Amount `json:",omitempty"`
+}
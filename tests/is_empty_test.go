@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/mailru/easyjson"
+)
+
+func TestIsEmpty(t *testing.T) {
+	for _, test := range []struct {
+		v    IsEmptyFields
+		want string
+	}{
+		{
+			v:    IsEmptyFields{},
+			want: `{"Note":"","Total":{"Cents":0,"Currency":""}}`,
+		},
+		{
+			v: IsEmptyFields{
+				Price: Amount{Currency: "EUR"},
+				Note:  "-",
+				Total: Amount{Currency: "EUR"},
+			},
+			want: `{"Total":{"Cents":0,"Currency":"EUR"}}`,
+		},
+		{
+			v: IsEmptyFields{
+				Price:   Amount{Cents: 150, Currency: "EUR"},
+				Note:    "sale",
+				Pointer: &Amount{},
+			},
+			want: `{"Price":{"Cents":150,"Currency":"EUR"},"Note":"sale","Total":{"Cents":0,"Currency":""},"Pointer":{"Cents":0,"Currency":""}}`,
+		},
+	} {
+		data, err := easyjson.Marshal(test.v)
+		if err != nil {
+			t.Errorf("Marshal(%+v) error: %v", test.v, err)
+			continue
+		}
+		if string(data) != test.want {
+			t.Errorf("Marshal(%+v) = %s; want %s", test.v, data, test.want)
+		}
+	}
+}
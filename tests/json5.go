@@ -0,0 +1,8 @@
+package tests
+
+//easyjson:json
+type JSON5Object struct {
+	A    int      `json:"a"`
+	B    int      `json:"b"`
+	Tags []string `json:"tags"`
+}
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/mailru/easyjson/jlexer"
+)
+
+func TestJSON5(t *testing.T) {
+	for i, test := range []struct {
+		data string
+		want JSON5Object
+	}{
+		{data: `{a:1, b:2,}`, want: JSON5Object{A: 1, B: 2}},
+		{data: `{"a":1,"b":2}`, want: JSON5Object{A: 1, B: 2}},
+		{data: `{tags: ["x", "y",], b: 2, skipped: {c: [1,],},}`, want: JSON5Object{B: 2, Tags: []string{"x", "y"}}},
+	} {
+		var got JSON5Object
+		if err := got.UnmarshalJSON([]byte(test.data)); err != nil {
+			t.Errorf("[%d] UnmarshalJSON(%s) error: %v", i, test.data, err)
+		} else if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("[%d] UnmarshalJSON(%s) = %+v; want %+v", i, test.data, got, test.want)
+		}
+
+		var streamed JSON5Object
+		if err := streamed.DecodeJSON(bytes.NewReader([]byte(test.data))); err != nil {
+			t.Errorf("[%d] DecodeJSON(%s) error: %v", i, test.data, err)
+		} else if !reflect.DeepEqual(streamed, test.want) {
+			t.Errorf("[%d] DecodeJSON(%s) = %+v; want %+v", i, test.data, streamed, test.want)
+		}
+	}
+
+	for _, data := range []string{`{a:1,,}`, `{a:1 b:2}`, `[1]`} {
+		var v JSON5Object
+		if err := v.UnmarshalJSON([]byte(data)); err == nil {
+			t.Errorf("UnmarshalJSON(%s) ok; want error", data)
+		}
+	}
+}
+
+func TestJSON5Strict(t *testing.T) {
+	data := `{a:1, b:2,}`
+	var v JSON5Object
+	l := jlexer.Lexer{Data: []byte(data)}
+	v.UnmarshalEasyJSON(&l)
+	if l.Error() == nil {
+		t.Errorf("strict UnmarshalEasyJSON(%s) ok; want error", data)
+	}
+}
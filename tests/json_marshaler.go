@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// LowerString is always marshaled in lower case.
+type LowerString string
+
+func (s LowerString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ToLower(string(s)))
+}
+
+func (s *LowerString) UnmarshalJSON(data []byte) error {
+	var v string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = LowerString(strings.ToLower(v))
+	return nil
+}
+
+type JSONMarshalerUnmarshaler interface {
+	json.Marshaler
+	json.Unmarshaler
+}
+
+//easyjson:json
+type JSONMarshalerStruct struct {
+	Value  LowerString
+	Ptr    *LowerString
+	Values []LowerString
+}
+
+//easyjson:json
+type JSONMarshalerIfaceStruct struct {
+	Value JSONMarshalerUnmarshaler
+	Nil   JSONMarshalerUnmarshaler
+}
+
+var jsonMarshalerPtr = LowerString("ptr")
+
+var jsonMarshalerValue = JSONMarshalerStruct{
+	Value:  "value",
+	Ptr:    &jsonMarshalerPtr,
+	Values: []LowerString{"a", "b"},
+}
+
+var jsonMarshalerString = `{"Value":"value","Ptr":"ptr","Values":["a","b"]}`
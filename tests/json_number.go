@@ -0,0 +1,31 @@
+package tests
+
+import "encoding/json"
+
+//easyjson:json
+type JSONNumberStruct struct {
+	Big      json.Number
+	Float    json.Number
+	Quoted   json.Number `json:",string"`
+	Ptr      *json.Number
+	Slice    []json.Number
+	Optional json.Number `json:",omitempty"`
+}
+
+var bigJSONNumber = json.Number("10000000000000000001")
+
+var jsonNumberStructValue = JSONNumberStruct{
+	Big:    "10000000000000000001",
+	Float:  "1.0000000000000000000001e+300",
+	Quoted: "42",
+	Ptr:    &bigJSONNumber,
+	Slice:  []json.Number{"-10000000000000000001", "0"},
+}
+
+var jsonNumberStructString = `{` +
+	`"Big":10000000000000000001,` +
+	`"Float":1.0000000000000000000001e+300,` +
+	`"Quoted":"42",` +
+	`"Ptr":10000000000000000001,` +
+	`"Slice":[-10000000000000000001,0]` +
+	`}`
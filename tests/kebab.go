@@ -0,0 +1,11 @@
+package tests
+
+//easyjson:json
+type KebabStruct struct {
+	HTTPServerName   string
+	ContentType      string
+	CustomNamedField string `json:"cUsToM"`
+}
+
+var kebabStructValue = KebabStruct{HTTPServerName: "srv", ContentType: "text/plain"}
+var kebabStructString = `{"http-server-name":"srv","content-type":"text/plain","cUsToM":""}`
@@ -0,0 +1,12 @@
+package tests
+
+//easyjson:json
+type KeyEscaping struct {
+	Plain   int
+	Quote   int `json:"say \"hi\""`
+	Slash   int `json:"a\\b"`
+	Control int `json:"tab\there\x01"`
+	Delete  int `json:"del\x7f"`
+	Unicode int `json:"héllo"`
+	HTML    int `json:"<b>&"`
+}
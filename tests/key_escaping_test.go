@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+func TestKeyEscaping(t *testing.T) {
+	v := KeyEscaping{Plain: 1, Quote: 2, Slash: 3, Control: 4, Delete: 5, Unicode: 6, HTML: 7}
+	data, err := easyjson.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	want := `{"Plain":1,"say \"hi\"":2,"a\\b":3,"tab\there\u0001":4,"del` + "\x7f" + `":5,"héllo":6,"<b>&":7}`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s; want %s", data, want)
+	}
+
+	var keys map[string]int
+	if err := json.Unmarshal(data, &keys); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error: %v", data, err)
+	}
+	wantKeys := map[string]int{"Plain": 1, `say "hi"`: 2, `a\b`: 3, "tab\there\x01": 4, "del\x7f": 5, "héllo": 6, "<b>&": 7}
+	if !reflect.DeepEqual(keys, wantKeys) {
+		t.Errorf("json.Unmarshal(%s) = %v; want %v", data, keys, wantKeys)
+	}
+
+	var got KeyEscaping
+	if err := easyjson.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal(%s) error: %v", data, err)
+	}
+	if got != v {
+		t.Errorf("Unmarshal(%s) = %+v; want %+v", data, got, v)
+	}
+}
+
+// BenchmarkKeyPrefix compares writing an object key as the constant the
+// generated code writes with escaping it while encoding.
+func BenchmarkKeyPrefix(b *testing.B) {
+	const name = "field_name"
+	const prefix = `,"field_name":`
+	b.Run("constant", func(b *testing.B) {
+		var w jwriter.Writer
+		for i := 0; i < b.N; i++ {
+			w.RawString(prefix)
+			if w.Size() > 1<<16 {
+				w = jwriter.Writer{}
+			}
+		}
+	})
+	b.Run("escaped", func(b *testing.B) {
+		var w jwriter.Writer
+		for i := 0; i < b.N; i++ {
+			w.RawByte(',')
+			w.String(name)
+			w.RawByte(':')
+			if w.Size() > 1<<16 {
+				w = jwriter.Writer{}
+			}
+		}
+	})
+}
@@ -0,0 +1,11 @@
+package tests
+
+//easyjson:json
+type LenientTypes struct {
+	Count   int
+	Size    uint16
+	Ratio   float64
+	Enabled bool
+	Flags   []bool
+	Ptr     *int
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestLenientTypes(t *testing.T) {
+	n := 5
+	for i, test := range []struct {
+		data string
+		want LenientTypes
+	}{
+		{
+			data: `{"Count":"12","Size":"7","Ratio":"0.25","Enabled":1,"Flags":[0,1,true],"Ptr":"5"}`,
+			want: LenientTypes{Count: 12, Size: 7, Ratio: 0.25, Enabled: true, Flags: []bool{false, true, true}, Ptr: &n},
+		},
+		{
+			data: `{"Count":12,"Size":7,"Ratio":0.25,"Enabled":false,"Flags":[],"Ptr":5}`,
+			want: LenientTypes{Count: 12, Size: 7, Ratio: 0.25, Flags: []bool{}, Ptr: &n},
+		},
+	} {
+		var got LenientTypes
+		if err := got.UnmarshalJSON([]byte(test.data)); err != nil {
+			t.Errorf("[%d] UnmarshalJSON(%s) error: %v", i, test.data, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("[%d] UnmarshalJSON(%s) = %+v; want %+v", i, test.data, got, test.want)
+		}
+
+		var streamed LenientTypes
+		if err := streamed.DecodeJSON(bytes.NewReader([]byte(test.data))); err != nil {
+			t.Errorf("[%d] DecodeJSON(%s) error: %v", i, test.data, err)
+		} else if !reflect.DeepEqual(streamed, test.want) {
+			t.Errorf("[%d] DecodeJSON(%s) = %+v; want %+v", i, test.data, streamed, test.want)
+		}
+	}
+}
+
+func TestLenientTypesInvalid(t *testing.T) {
+	for _, data := range []string{
+		`{"Count":"12a"}`,
+		`{"Size":"-1"}`,
+		`{"Enabled":2}`,
+		`{"Enabled":"1"}`,
+	} {
+		var v LenientTypes
+		if err := v.UnmarshalJSON([]byte(data)); err == nil {
+			t.Errorf("UnmarshalJSON(%s) ok; want error", data)
+		}
+	}
+}
+
+func TestStrictTypes(t *testing.T) {
+	for _, data := range []string{
+		`{"Int":"12"}`,
+		`{"Uint16":"7"}`,
+		`{"Float64":"0.25"}`,
+		`{"Bool":1}`,
+		`{"Bool":0}`,
+	} {
+		var v PrimitiveTypes
+		if err := v.UnmarshalJSON([]byte(data)); err == nil {
+			t.Errorf("UnmarshalJSON(%s) ok; want error", data)
+		}
+	}
+}
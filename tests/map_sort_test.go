@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+func TestMapSortKeys(t *testing.T) {
+	m := SortedMap{}
+	for i := 0; i < 100; i++ {
+		m["key"+strconv.Itoa(i)] = i
+	}
+	want, err := json.Marshal(map[string]int(m))
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		got, err := m.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON() error: %v", err)
+		}
+		if string(got) != string(want) {
+			t.Fatalf("[%d] MarshalJSON() = %s; want %s", i, got, want)
+		}
+	}
+}
+
+func TestMapSortKeysNested(t *testing.T) {
+	v := SortedMapStruct{
+		Named:  map[Str]int{"b": 2, "a": 1, "c": 3},
+		Nested: map[string]map[string]bool{"y": {"2": true, "1": false}, "x": nil},
+		Ints:   map[int]string{2: "b", 10: "c", -1: "z", 1: "a"},
+		Uints:  map[uint8][]map[int64]string{20: nil, 3: {{2: "b", 1: "a"}}},
+	}
+	want := `{"Named":{"a":1,"b":2,"c":3},"Nested":{"x":null,"y":{"1":false,"2":true}},` +
+		`"Ints":{"-1":"z","1":"a","10":"c","2":"b"},"Uints":{"20":null,"3":[{"1":"a","2":"b"}]}}`
+	type stdSortedMapStruct SortedMapStruct
+	if std, err := json.Marshal(stdSortedMapStruct(v)); err != nil || string(std) != want {
+		t.Fatalf("json.Marshal() = %s, %v; want %s", std, err, want)
+	}
+
+	for i := 0; i < 20; i++ {
+		got, err := v.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON() error: %v", err)
+		}
+		if string(got) != want {
+			t.Fatalf("[%d] MarshalJSON() = %s; want %s", i, got, want)
+		}
+	}
+}
+
+func TestMapNoSortKeys(t *testing.T) {
+	m := UnsortedMap{}
+	for i := 0; i < 100; i++ {
+		m["key"+strconv.Itoa(i)] = i
+	}
+
+	data, err := m.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	var got map[string]int
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error: %v", data, err)
+	}
+	if len(got) != len(m) {
+		t.Errorf("json.Unmarshal(%s) = %d keys; want %d", data, len(got), len(m))
+	}
+	for k, v := range m {
+		if got[k] != v {
+			t.Errorf("json.Unmarshal(%s)[%q] = %d; want %d", data, k, got[k], v)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package tests
+
+//easyjson:json
+type StringPayload struct {
+	ID    int
+	Text  string
+	Tags  []string
+	Score float64
+}
+
+//easyjson:json
+type StringEnvelope struct {
+	Kind    string
+	Payload string
+}
+
+var stringPayloadValue = StringPayload{
+	ID:    7,
+	Text:  "line\n\"quoted\" <&> é",
+	Tags:  []string{"a", `b\c`},
+	Score: 0.5,
+}
@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalJSONString(t *testing.T) {
+	payload, err := stringPayloadValue.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+
+	got, err := stringPayloadValue.MarshalJSONString()
+	if err != nil {
+		t.Fatalf("MarshalJSONString() error: %v", err)
+	}
+	var unquoted string
+	if err := json.Unmarshal([]byte(got), &unquoted); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error: %v", got, err)
+	}
+	if unquoted != string(payload) {
+		t.Errorf("MarshalJSONString() = %s; want %s quoted", got, payload)
+	}
+
+	// A document embedded as a string value is decoded in two steps.
+	data := `{"Kind":"payload","Payload":` + got + `}`
+	var envelope StringEnvelope
+	if err := envelope.UnmarshalJSON([]byte(data)); err != nil {
+		t.Fatalf("UnmarshalJSON(%s) error: %v", data, err)
+	}
+	var decoded StringPayload
+	if err := decoded.UnmarshalJSON([]byte(envelope.Payload)); err != nil {
+		t.Fatalf("UnmarshalJSON(%s) error: %v", envelope.Payload, err)
+	}
+	if !reflect.DeepEqual(decoded, stringPayloadValue) {
+		t.Errorf("decoded %+v; want %+v", decoded, stringPayloadValue)
+	}
+}
+
+func TestMarshalJSONStringError(t *testing.T) {
+	v := StringPayload{Score: math.NaN()}
+	if got, err := v.MarshalJSONString(); err == nil {
+		t.Errorf("MarshalJSONString() of NaN = %s; want error", got)
+	}
+}
@@ -0,0 +1,10 @@
+package tests
+
+//easyjson:json
+type MaxDepthTree []MaxDepthTree
+
+//easyjson:json
+type MaxDepthNode struct {
+	Children []MaxDepthNode
+	Value    interface{}
+}
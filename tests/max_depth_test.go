@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mailru/easyjson/jlexer"
+)
+
+// nestedArrays returns n arrays nested into each other.
+func nestedArrays(n int) string {
+	return strings.Repeat("[", n) + strings.Repeat("]", n)
+}
+
+// nestedNodes returns n MaxDepthNode objects nested through Children, with
+// value as the Value of the innermost one.
+func nestedNodes(n int, value string) string {
+	return strings.Repeat(`{"Children":[`, n-1) + `{"Value":` + value + `}` + strings.Repeat(`]}`, n-1)
+}
+
+func TestMaxDepth(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		data      string
+		v         interface{ UnmarshalJSON([]byte) error }
+		wantError bool
+	}{
+		{name: "shallow tree", data: nestedArrays(100), v: &MaxDepthTree{}},
+		{name: "deep tree", data: nestedArrays(10000), v: &MaxDepthTree{}, wantError: true},
+		{name: "shallow nodes", data: nestedNodes(50, "1"), v: &MaxDepthNode{}},
+		{name: "deep nodes", data: nestedNodes(10000, "1"), v: &MaxDepthNode{}, wantError: true},
+		{name: "shallow interface", data: nestedNodes(50, nestedArrays(50)), v: &MaxDepthNode{}},
+		{name: "deep interface", data: nestedNodes(1, nestedArrays(10000)), v: &MaxDepthNode{}, wantError: true},
+	} {
+		err := test.v.UnmarshalJSON([]byte(test.data))
+		if !test.wantError {
+			if err != nil {
+				t.Errorf("%s: UnmarshalJSON() error: %v", test.name, err)
+			}
+			continue
+		}
+		if _, ok := err.(*jlexer.LexerError); !ok || !strings.Contains(err.Error(), "maximum nesting depth of 100 exceeded") {
+			t.Errorf("%s: UnmarshalJSON() error = %v; want the nesting depth error", test.name, err)
+		}
+	}
+}
+
+func TestMaxDepthValue(t *testing.T) {
+	var v MaxDepthTree
+	if err := v.UnmarshalJSON([]byte(`[[],[[]]]`)); err != nil {
+		t.Fatalf("UnmarshalJSON() error: %v", err)
+	}
+	want := MaxDepthTree{MaxDepthTree{}, MaxDepthTree{MaxDepthTree{}}}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("UnmarshalJSON() = %#v; want %#v", v, want)
+	}
+}
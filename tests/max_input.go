@@ -0,0 +1,7 @@
+package tests
+
+//easyjson:json
+type MaxInput struct {
+	Name string
+	Tags []string
+}
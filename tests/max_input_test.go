@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMaxInputBytes(t *testing.T) {
+	const limit = 1 << 20
+	want := "input exceeds the limit of 1048576 bytes"
+	big := `{"Name":"` + strings.Repeat("x", 2<<20) + `"}`
+
+	var v MaxInput
+	if err := v.UnmarshalJSON([]byte(big)); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("UnmarshalJSON() error = %v; want %q", err, want)
+	}
+	if v.Name != "" {
+		t.Errorf("UnmarshalJSON() decoded a Name of %d bytes", len(v.Name))
+	}
+
+	v = MaxInput{}
+	if err := v.DecodeJSON(strings.NewReader(big)); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("DecodeJSON() error = %v; want %q", err, want)
+	}
+
+	small := `{"Name":"` + strings.Repeat("x", limit/2) + `","Tags":["a"]}`
+	v = MaxInput{}
+	if err := v.UnmarshalJSON([]byte(small)); err != nil || len(v.Name) != limit/2 || len(v.Tags) != 1 {
+		t.Errorf("UnmarshalJSON() = %d bytes of Name, %v, %v; want %d bytes, [a], <nil>", len(v.Name), v.Tags, err, limit/2)
+	}
+	v = MaxInput{}
+	if err := v.DecodeJSON(strings.NewReader(small)); err != nil || len(v.Name) != limit/2 || len(v.Tags) != 1 {
+		t.Errorf("DecodeJSON() = %d bytes of Name, %v, %v; want %d bytes, [a], <nil>", len(v.Name), v.Tags, err, limit/2)
+	}
+}
@@ -0,0 +1,15 @@
+package tests
+
+//easyjson:json
+type MergeTarget struct {
+	Name   string
+	Count  int
+	Tags   map[string]int
+	Inner  MergeInner
+	Ptr    *MergeInner
+	Values []int
+}
+
+type MergeInner struct {
+	A, B string
+}
@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalMerges(t *testing.T) {
+	preset := func() MergeTarget {
+		return MergeTarget{
+			Name:   "kept",
+			Count:  1,
+			Tags:   map[string]int{"a": 1, "b": 2},
+			Inner:  MergeInner{A: "a", B: "b"},
+			Ptr:    &MergeInner{A: "pa", B: "pb"},
+			Values: []int{1, 2, 3},
+		}
+	}
+	for _, data := range []string{
+		`{"Count":5}`,
+		`{"Count":5,"Tags":{"b":3,"c":4},"Inner":{"B":"x"},"Ptr":{"A":"y"}}`,
+		`{"Tags":{},"Values":[7]}`,
+		`{"Tags":null,"Ptr":null,"Values":null}`,
+		`{}`,
+	} {
+		want := preset()
+		if err := json.Unmarshal([]byte(data), &want); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error: %v", data, err)
+		}
+
+		got := preset()
+		if err := got.UnmarshalJSON([]byte(data)); err != nil {
+			t.Errorf("UnmarshalJSON(%s) error: %v", data, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("UnmarshalJSON(%s) into a preset value = %+v; want %+v", data, got, want)
+		}
+	}
+}
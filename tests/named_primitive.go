@@ -0,0 +1,31 @@
+package tests
+
+//easyjson:json
+type Status int
+
+//easyjson:json
+type Label string
+
+//easyjson:json
+type NamedPrimitives struct {
+	Status   Status
+	Statuses map[string]Status
+	Labels   []Label
+	Ptr      *Status
+}
+
+var namedStatus = Status(3)
+
+var namedPrimitivesValue = NamedPrimitives{
+	Status:   2,
+	Statuses: map[string]Status{"a": 1, "b": -1},
+	Labels:   []Label{"new", "hot"},
+	Ptr:      &namedStatus,
+}
+
+var namedPrimitivesString = `{` +
+	`"Status":2,` +
+	`"Statuses":{"a":1,"b":-1},` +
+	`"Labels":["new","hot"],` +
+	`"Ptr":3` +
+	`}`
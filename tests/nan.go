@@ -0,0 +1,8 @@
+package tests
+
+//easyjson:json
+type NaNFloats struct {
+	F64 float64
+	F32 float32
+	Str float64 `json:",string"`
+}
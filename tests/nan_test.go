@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/mailru/easyjson/jwriter"
+)
+
+var nonFiniteFloats = []struct {
+	name  string
+	value float64
+}{
+	{"+Inf", math.Inf(1)},
+	{"-Inf", math.Inf(-1)},
+	{"NaN", math.NaN()},
+}
+
+func TestNaNPolicyError(t *testing.T) {
+	for _, f := range nonFiniteFloats {
+		for i, v := range []NaNFloats{
+			{F64: f.value},
+			{F32: float32(f.value)},
+			{Str: f.value},
+		} {
+			data, err := v.MarshalJSON()
+			if _, ok := err.(*json.UnsupportedValueError); !ok {
+				t.Errorf("[%v, %d] MarshalJSON() = %s, %v; want *json.UnsupportedValueError", f.name, i, data, err)
+			} else if want := "json: unsupported value: " + f.name; err.Error() != want {
+				t.Errorf("[%v, %d] MarshalJSON() error = %v; want %v", f.name, i, err, want)
+			}
+		}
+	}
+}
+
+func TestNaNPolicyNull(t *testing.T) {
+	for _, f := range nonFiniteFloats {
+		v := NaNNullFloats{F64: f.value, F32: float32(f.value), Str: f.value}
+		data, err := v.MarshalJSON()
+		if want := `{"F64":null,"F32":null,"Str":null}`; err != nil || string(data) != want {
+			t.Errorf("[%v] MarshalJSON() = %s, %v; want %s", f.name, data, err, want)
+		}
+	}
+}
+
+func TestNaNPolicyString(t *testing.T) {
+	for _, f := range nonFiniteFloats {
+		v := NaNStringFloats{F64: f.value, F32: float32(f.value), Str: f.value}
+		data, err := v.MarshalJSON()
+		want := `{"F64":"` + f.name + `","F32":"` + f.name + `","Str":"` + f.name + `"}`
+		if err != nil || string(data) != want {
+			t.Errorf("[%v] MarshalJSON() = %s, %v; want %s", f.name, data, err, want)
+		}
+	}
+}
+
+func TestNaNPolicyWriter(t *testing.T) {
+	v := NaNFloats{F64: 1.5, F32: float32(math.Inf(1)), Str: 2}
+	for _, test := range []struct {
+		policy jwriter.NaNPolicy
+		want   string
+	}{
+		{jwriter.NaNNull, `{"F64":1.5,"F32":null,"Str":"2"}`},
+		{jwriter.NaNString, `{"F64":1.5,"F32":"+Inf","Str":"2"}`},
+	} {
+		w := jwriter.Writer{NaNPolicy: test.policy}
+		v.MarshalEasyJSON(&w)
+		data, err := w.BuildBytes()
+		if err != nil || string(data) != test.want {
+			t.Errorf("[%v] MarshalEasyJSON() = %s, %v; want %s", test.policy, data, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package tests
+
+type NestedItem struct {
+	Name string
+	N    int
+}
+
+type NestedItemMaps []map[string]*NestedItem
+
+type NestedItemSlices map[string][]NestedItem
+
+type NestedStrings []map[string]string
+
+//easyjson:json
+type NestedCompositions struct {
+	MapOfSlices    map[string][]NestedItem
+	SliceOfMaps    []map[string]*NestedItem
+	SliceOfIntMaps []map[string]int
+	Deep           map[string][]map[int][]*NestedItem
+	Arrays         [2]map[string][2][]*NestedItem
+	PtrMap         *map[string]*[]NestedItem
+	SliceSlice     [][]map[string][]int
+	Anonymous      map[string][]struct{ A int }
+	PtrPtrs        []**NestedItem
+	Named          NestedItemMaps
+	NamedElems     map[string]NestedItemMaps
+	NamedPtrs      []*NestedItemSlices
+	Interfaces     map[string][]interface{}
+	Bytes          map[string][][]byte
+	Interned       NestedStrings `json:",intern"`
+}
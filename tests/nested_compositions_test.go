@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mailru/easyjson"
+)
+
+// plainNestedCompositions is NestedCompositions without the generated methods,
+// encoded and decoded by encoding/json.
+type plainNestedCompositions NestedCompositions
+
+func TestNestedCompositions(t *testing.T) {
+	items := []NestedItem{{Name: "a", N: 1}, {Name: "b", N: 2}}
+	itemPtr := &items[0]
+	v := NestedCompositions{
+		MapOfSlices:    map[string][]NestedItem{"x": items, "y": nil, "z": {}},
+		SliceOfMaps:    []map[string]*NestedItem{{"a": &items[0], "nil": nil}, nil, {}},
+		SliceOfIntMaps: []map[string]int{{"one": 1, "two": 2}, nil},
+		Deep: map[string][]map[int][]*NestedItem{
+			"d": {{1: {&items[0], nil}}, {2: nil}, nil},
+		},
+		Arrays: [2]map[string][2][]*NestedItem{
+			{"k": {{&items[1]}, nil}},
+		},
+		PtrMap:     &map[string]*[]NestedItem{"p": &items, "nil": nil},
+		SliceSlice: [][]map[string][]int{{{"s": {1, 2}}, nil}, nil},
+		Anonymous:  map[string][]struct{ A int }{"a": {{A: 1}, {A: 2}}},
+		PtrPtrs:    []**NestedItem{&itemPtr, nil, new(*NestedItem)},
+		Named:      NestedItemMaps{{"n": &items[1]}, nil},
+		NamedElems: map[string]NestedItemMaps{"e": {{"a": &items[0]}}, "nil": nil},
+		NamedPtrs:  []*NestedItemSlices{{"s": items}, nil},
+		Interfaces: map[string][]interface{}{"i": {"s", 1.5, true, nil, map[string]interface{}{"k": []interface{}{1.0}}}},
+		Bytes:      map[string][][]byte{"b": {[]byte("abc"), nil}},
+		Interned:   NestedStrings{{"k": "v"}, nil},
+	}
+
+	data, err := easyjson.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	want, err := json.Marshal(plainNestedCompositions(v))
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	if string(data) != string(want) {
+		t.Errorf("Marshal() = %s; want %s", data, want)
+	}
+
+	var got NestedCompositions
+	if err := easyjson.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	var wantV plainNestedCompositions
+	if err := json.Unmarshal(data, &wantV); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+	if !reflect.DeepEqual(got, NestedCompositions(wantV)) {
+		t.Errorf("Unmarshal(%s) = %+v; want %+v", data, got, wantV)
+	}
+}
@@ -0,0 +1,27 @@
+package tests
+
+//easyjson:json
+type NullKinds struct {
+	Slice     []int            `json:"slice"`
+	Map       map[string]int   `json:"map"`
+	Ptr       *int             `json:"ptr"`
+	Interface interface{}      `json:"interface"`
+	Struct    NullKindsInner   `json:"struct"`
+	StructPtr *NullKindsInner  `json:"struct_ptr"`
+	Array     [2]int           `json:"array"`
+	String    string           `json:"string"`
+	Int       int              `json:"int"`
+	Float     float64          `json:"float"`
+	Bool      bool             `json:"bool"`
+	Bytes     []byte           `json:"bytes"`
+	Elems     []*int           `json:"elems"`
+	Values    map[string]*int  `json:"values"`
+	Ints      []int            `json:"ints"`
+	IntValues map[string]int   `json:"int_values"`
+	Structs   []NullKindsInner `json:"structs"`
+}
+
+//easyjson:json
+type NullKindsInner struct {
+	A int `json:"a"`
+}
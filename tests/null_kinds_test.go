@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// plainNullKinds is NullKinds without the generated methods, decoded by
+// encoding/json as the reference.
+type plainNullKinds NullKinds
+
+func newNullKinds() NullKinds {
+	one := 1
+	return NullKinds{
+		Slice:     []int{1},
+		Map:       map[string]int{"a": 1},
+		Ptr:       &one,
+		Interface: "x",
+		Struct:    NullKindsInner{A: 1},
+		StructPtr: &NullKindsInner{A: 1},
+		Array:     [2]int{1, 2},
+		String:    "s",
+		Int:       1,
+		Float:     1.5,
+		Bool:      true,
+		Bytes:     []byte("b"),
+	}
+}
+
+func TestNullKinds(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		data string
+		want func(v *NullKinds)
+	}{
+		{
+			name: "nil kinds",
+			data: `{"slice":null,"map":null,"ptr":null,"interface":null,"struct_ptr":null,"bytes":null}`,
+			want: func(v *NullKinds) {
+				v.Slice, v.Map, v.Ptr, v.Interface, v.StructPtr, v.Bytes = nil, nil, nil, nil, nil, nil
+			},
+		},
+		{
+			name: "no-op kinds",
+			data: `{"struct":null,"array":null,"string":null,"int":null,"float":null,"bool":null}`,
+			want: func(v *NullKinds) {},
+		},
+		{
+			name: "struct members",
+			data: `{"struct":{"a":null},"struct_ptr":{"a":null}}`,
+			want: func(v *NullKinds) {},
+		},
+		{
+			name: "array elements",
+			data: `{"array":[null,3]}`,
+			want: func(v *NullKinds) { v.Array = [2]int{1, 3} },
+		},
+		{
+			name: "slice elements",
+			data: `{"elems":[null,1],"ints":[null,1],"structs":[null]}`,
+			want: func(v *NullKinds) {
+				one := 1
+				v.Elems = []*int{nil, &one}
+				v.Ints = []int{0, 1}
+				v.Structs = []NullKindsInner{{}}
+			},
+		},
+		{
+			name: "map values",
+			data: `{"values":{"a":null},"int_values":{"a":null}}`,
+			want: func(v *NullKinds) {
+				v.Values = map[string]*int{"a": nil}
+				v.IntValues = map[string]int{"a": 0}
+			},
+		},
+	} {
+		want := newNullKinds()
+		test.want(&want)
+
+		std := plainNullKinds(newNullKinds())
+		if err := json.Unmarshal([]byte(test.data), &std); err != nil {
+			t.Errorf("%s: json.Unmarshal(%s) error: %v", test.name, test.data, err)
+		} else if !reflect.DeepEqual(NullKinds(std), want) {
+			t.Errorf("%s: json.Unmarshal(%s) = %+v; want %+v", test.name, test.data, std, want)
+		}
+
+		got := newNullKinds()
+		if err := got.UnmarshalJSON([]byte(test.data)); err != nil {
+			t.Errorf("%s: UnmarshalJSON(%s) error: %v", test.name, test.data, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: UnmarshalJSON(%s) = %+v; want %+v", test.name, test.data, got, want)
+		}
+	}
+}
+
+func TestNullKindsTopLevel(t *testing.T) {
+	v := newNullKinds()
+	if err := v.UnmarshalJSON([]byte(`null`)); err != nil {
+		t.Errorf("UnmarshalJSON(null) error: %v", err)
+	} else if want := newNullKinds(); !reflect.DeepEqual(v, want) {
+		t.Errorf("UnmarshalJSON(null) = %+v; want %+v", v, want)
+	}
+
+	items := Items{{Name: "a"}}
+	if err := items.UnmarshalJSON([]byte(`null`)); err != nil {
+		t.Errorf("Items.UnmarshalJSON(null) error: %v", err)
+	} else if items != nil {
+		t.Errorf("Items.UnmarshalJSON(null) = %+v; want nil", items)
+	}
+}
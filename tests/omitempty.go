@@ -10,3 +10,9 @@ type OmitEmptyDefault struct {
 
 var omitEmptyDefaultValue = OmitEmptyDefault{Field: "test"}
 var omitEmptyDefaultString = `{"Field":"test","s":"","Str2":""}`
+
+//easyjson:json
+type OmitEmptyDefaultPointers struct {
+	Omitted *string `json:"omitted"`
+	Kept    *string `json:"kept,!omitempty"`
+}
@@ -0,0 +1,23 @@
+package tests
+
+//easyjson:json
+type OmitEmptyCommas struct {
+	A int    `json:"a,omitempty"`
+	B string `json:"b,omitempty"`
+	C *int   `json:"c,omitempty"`
+	D []int  `json:"d,omitempty"`
+	E bool   `json:"e,omitempty"`
+	F string `json:"f,readonly"`
+	*OmitEmptyCommasEmbedded
+}
+
+type OmitEmptyCommasEmbedded struct {
+	G int `json:"g,omitempty"`
+}
+
+//easyjson:json
+type OmitEmptyCommasInline struct {
+	A     int               `json:"a,omitempty"`
+	B     string            `json:"b,omitempty"`
+	Extra map[string]string `json:",inline"`
+}
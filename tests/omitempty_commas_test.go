@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// plainOmitEmptyCommas is OmitEmptyCommas without the generated methods, encoded
+// by encoding/json as the reference.
+type plainOmitEmptyCommas OmitEmptyCommas
+
+func TestOmitEmptyCommas(t *testing.T) {
+	one := 1
+	set := []func(v *OmitEmptyCommas){
+		func(v *OmitEmptyCommas) { v.A = 1 },
+		func(v *OmitEmptyCommas) { v.B = "b" },
+		func(v *OmitEmptyCommas) { v.C = &one },
+		func(v *OmitEmptyCommas) { v.D = []int{1} },
+		func(v *OmitEmptyCommas) { v.E = true },
+		func(v *OmitEmptyCommas) { v.OmitEmptyCommasEmbedded = &OmitEmptyCommasEmbedded{} },
+		func(v *OmitEmptyCommas) { v.OmitEmptyCommasEmbedded = &OmitEmptyCommasEmbedded{G: 1} },
+	}
+	// All subsets of the fields set, covering each field being the only one
+	// written, as well as the first or the last of several.
+	for mask := uint(0); mask < 1<<uint(len(set)); mask++ {
+		v := OmitEmptyCommas{F: "readonly"}
+		for i, f := range set {
+			if mask&(1<<uint(i)) != 0 {
+				f(&v)
+			}
+		}
+
+		data, err := v.MarshalJSON()
+		if err != nil {
+			t.Errorf("[%b] MarshalJSON() error: %v", mask, err)
+			continue
+		}
+		if !json.Valid(data) {
+			t.Errorf("[%b] MarshalJSON() = %s, invalid JSON", mask, data)
+			continue
+		}
+		want, err := json.Marshal(plainOmitEmptyCommas(v))
+		if err != nil {
+			t.Fatalf("[%b] json.Marshal() error: %v", mask, err)
+		}
+		// encoding/json cannot skip the readonly field.
+		var wantMap, gotMap map[string]interface{}
+		if err := json.Unmarshal(want, &wantMap); err != nil {
+			t.Fatalf("[%b] json.Unmarshal(%s) error: %v", mask, want, err)
+		}
+		delete(wantMap, "f")
+		if err := json.Unmarshal(data, &gotMap); err != nil {
+			t.Fatalf("[%b] json.Unmarshal(%s) error: %v", mask, data, err)
+		}
+		if w, g := mustMarshal(t, wantMap), mustMarshal(t, gotMap); w != g {
+			t.Errorf("[%b] MarshalJSON() = %s; want the members of %s", mask, data, w)
+		}
+	}
+}
+
+func TestOmitEmptyCommasInline(t *testing.T) {
+	for _, test := range []struct {
+		v    OmitEmptyCommasInline
+		want string
+	}{
+		{v: OmitEmptyCommasInline{}, want: `{}`},
+		{v: OmitEmptyCommasInline{A: 1}, want: `{"a":1}`},
+		{v: OmitEmptyCommasInline{B: "b"}, want: `{"b":"b"}`},
+		{v: OmitEmptyCommasInline{Extra: map[string]string{"x": "y"}}, want: `{"x":"y"}`},
+		{v: OmitEmptyCommasInline{B: "b", Extra: map[string]string{"x": "y"}}, want: `{"b":"b","x":"y"}`},
+		{v: OmitEmptyCommasInline{A: 1, Extra: map[string]string{}}, want: `{"a":1}`},
+	} {
+		data, err := test.v.MarshalJSON()
+		if err != nil {
+			t.Errorf("MarshalJSON(%+v) error: %v", test.v, err)
+			continue
+		}
+		if string(data) != test.want {
+			t.Errorf("MarshalJSON(%+v) = %s; want %s", test.v, data, test.want)
+		}
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal(%v) error: %v", v, err)
+	}
+	return string(data)
+}
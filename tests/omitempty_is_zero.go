@@ -0,0 +1,56 @@
+package tests
+
+import "time"
+
+type Money struct {
+	Amount   int
+	Currency string
+}
+
+// IsZero reports whether m is the default value of Money.
+func (m Money) IsZero() bool {
+	return m.Amount == 0 && m.Currency == ""
+}
+
+type Window struct {
+	Size int
+}
+
+// IsZero reports whether the window has no size.
+func (w *Window) IsZero() bool {
+	return w.Size == 0
+}
+
+type NoZeroCheck struct {
+	Value int
+}
+
+//easyjson:json
+type OmitEmptyIsZero struct {
+	Price    Money     `json:",omitempty"`
+	Window   Window    `json:",omitempty"`
+	Time     time.Time `json:",omitempty"`
+	Kept     Money
+	Other    NoZeroCheck `json:",omitempty"`
+	PriceRef *Money      `json:",omitempty"`
+}
+
+var omitEmptyIsZeroValue = OmitEmptyIsZero{}
+
+var omitEmptyIsZeroString = `{"Kept":{"Amount":0,"Currency":""},"Other":{"Value":0}}`
+
+var omitEmptyIsZeroFilledValue = OmitEmptyIsZero{
+	Price:    Money{Amount: 5, Currency: "EUR"},
+	Window:   Window{Size: 2},
+	Time:     time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+	PriceRef: &Money{},
+}
+
+var omitEmptyIsZeroFilledString = `{` +
+	`"Price":{"Amount":5,"Currency":"EUR"},` +
+	`"Window":{"Size":2},` +
+	`"Time":"2020-01-02T03:04:05Z",` +
+	`"Kept":{"Amount":0,"Currency":""},` +
+	`"Other":{"Value":0},` +
+	`"PriceRef":{"Amount":0,"Currency":""}` +
+	`}`
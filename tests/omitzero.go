@@ -0,0 +1,41 @@
+package tests
+
+import "time"
+
+//easyjson:json
+type OmitZero struct {
+	Inner      OmitZeroInner  `json:"inner,omitzero"`
+	InnerEmpty OmitZeroInner  `json:"inner_empty,omitempty"`
+	Time       time.Time      `json:"time,omitzero"`
+	TimeEmpty  time.Time      `json:"time_empty,omitempty"`
+	TimePtr    *time.Time     `json:"time_ptr,omitzero"`
+	Slice      []int          `json:"slice,omitzero"`
+	SliceEmpty []int          `json:"slice_empty,omitempty"`
+	SliceBoth  []int          `json:"slice_both,omitempty,omitzero"`
+	Ptr        *int           `json:"ptr,omitzero"`
+	Num        float64        `json:"num,omitzero"`
+	Str        string         `json:"str,omitzero"`
+	Array      [2]int         `json:"array,omitzero"`
+	Flag       ZeroByFlag     `json:"flag,omitzero"`
+	Mixed      OmitZeroMixed  `json:"mixed,omitzero"`
+	Map        map[string]int `json:"map,omitzero"`
+}
+
+type OmitZeroInner struct {
+	A int    `json:"a"`
+	B string `json:"b"`
+}
+
+// OmitZeroMixed cannot be compared with == as a whole.
+type OmitZeroMixed struct {
+	Items []string    `json:"items"`
+	Any   interface{} `json:"any"`
+}
+
+// ZeroByFlag is zero whenever Unset is true, as told by its IsZero method.
+type ZeroByFlag struct {
+	Value int  `json:"value"`
+	Unset bool `json:"-"`
+}
+
+func (z ZeroByFlag) IsZero() bool { return z.Unset }
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// plainOmitZero is OmitZero without the generated methods, encoded by
+// encoding/json as the reference.
+type plainOmitZero OmitZero
+
+func TestOmitZero(t *testing.T) {
+	zeroTime := time.Time{}
+	one := 1
+	for _, test := range []struct {
+		v    OmitZero
+		want string
+	}{
+		{
+			v:    OmitZero{},
+			want: `{"inner_empty":{"a":0,"b":""},"time_empty":"0001-01-01T00:00:00Z","flag":{"value":0}}`,
+		},
+		{
+			v: OmitZero{
+				TimePtr:    &zeroTime,
+				Slice:      []int{},
+				SliceEmpty: []int{},
+				SliceBoth:  []int{},
+				Flag:       ZeroByFlag{Value: 1, Unset: true},
+				Mixed:      OmitZeroMixed{Items: []string{}},
+				Map:        map[string]int{},
+			},
+			want: `{"inner_empty":{"a":0,"b":""},"time_empty":"0001-01-01T00:00:00Z","slice":[],"mixed":{"items":[],"any":null},"map":{}}`,
+		},
+		{
+			v: OmitZero{
+				Inner:      OmitZeroInner{B: "b"},
+				InnerEmpty: OmitZeroInner{A: 1},
+				Time:       time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+				Slice:      []int{1},
+				SliceEmpty: []int{1},
+				SliceBoth:  []int{1},
+				Ptr:        &one,
+				Num:        0.5,
+				Str:        "s",
+				Array:      [2]int{0, 1},
+				Mixed:      OmitZeroMixed{Any: 0},
+			},
+			want: `{"inner":{"a":0,"b":"b"},"inner_empty":{"a":1,"b":""},"time":"2020-01-02T03:04:05Z","time_empty":"0001-01-01T00:00:00Z",` +
+				`"slice":[1],"slice_empty":[1],"slice_both":[1],"ptr":1,"num":0.5,"str":"s","array":[0,1],"flag":{"value":0},"mixed":{"items":null,"any":0}}`,
+		},
+	} {
+		data, err := test.v.MarshalJSON()
+		if err != nil {
+			t.Errorf("MarshalJSON(%+v) error: %v", test.v, err)
+			continue
+		}
+		if string(data) != test.want {
+			t.Errorf("MarshalJSON(%+v) = %s; want %s", test.v, data, test.want)
+		}
+		std, err := json.Marshal(plainOmitZero(test.v))
+		if err != nil {
+			t.Errorf("json.Marshal(%+v) error: %v", test.v, err)
+		} else if string(std) != test.want {
+			t.Errorf("json.Marshal(%+v) = %s; want %s", test.v, std, test.want)
+		}
+	}
+}
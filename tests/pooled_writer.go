@@ -0,0 +1,23 @@
+package tests
+
+import "github.com/mailru/easyjson/jwriter"
+
+//easyjson:json
+type PooledStruct struct {
+	ID    int
+	Name  string
+	Tags  []string
+	Attrs map[string]string
+	Child *PooledStruct
+	Panic PanicOnMarshal
+}
+
+// PanicOnMarshal panics when it is encoded if set.
+type PanicOnMarshal bool
+
+func (p PanicOnMarshal) MarshalEasyJSON(w *jwriter.Writer) {
+	if p {
+		panic("PanicOnMarshal is set")
+	}
+	w.Bool(false)
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+func pooledValue(i int) PooledStruct {
+	return PooledStruct{
+		ID:    i,
+		Name:  strings.Repeat(fmt.Sprint("name", i), i%50),
+		Tags:  []string{"a", "b", fmt.Sprint(i)},
+		Attrs: map[string]string{"key": fmt.Sprint("value", i)},
+		Child: &PooledStruct{ID: -i},
+	}
+}
+
+func TestPooledWriterMarshal(t *testing.T) {
+	first, err := pooledValue(1).MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	want := string(first)
+
+	// The data of a result must not be overwritten by later calls.
+	for i := 2; i < 100; i++ {
+		if _, err := pooledValue(i).MarshalJSON(); err != nil {
+			t.Fatalf("MarshalJSON() error: %v", err)
+		}
+	}
+	if string(first) != want {
+		t.Errorf("MarshalJSON() result changed to %s; want %s", first, want)
+	}
+
+	plain, err := easyjson.Marshal(pooledValue(1))
+	if err != nil {
+		t.Fatalf("easyjson.Marshal() error: %v", err)
+	}
+	if string(plain) != want {
+		t.Errorf("MarshalJSON() = %s; easyjson.Marshal() = %s", want, plain)
+	}
+}
+
+func TestPooledWriterConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < 2000; i += 8 {
+				v := pooledValue(i)
+				want, _ := easyjson.Marshal(v)
+				got, err := v.MarshalJSON()
+				if err != nil || string(got) != string(want) {
+					errs <- fmt.Errorf("MarshalJSON() = %s, %v; want %s", got, err, want)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestPooledWriterPanic(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("MarshalJSON() did not panic")
+			}
+		}()
+		v := pooledValue(1)
+		v.Child.Panic = true
+		v.MarshalJSON()
+	}()
+
+	// The writer left by the panic is reset when it is put back.
+	got, err := pooledValue(2).MarshalJSON()
+	want, _ := easyjson.Marshal(pooledValue(2))
+	if err != nil || string(got) != string(want) {
+		t.Errorf("MarshalJSON() after panic = %s, %v; want %s", got, err, want)
+	}
+
+	w := jwriter.GetWriter()
+	w.RawString("garbage")
+	w.Error = errors.New("failed")
+	w.NoEscapeHTML = true
+	jwriter.PutWriter(w)
+
+	w = jwriter.GetWriter()
+	defer jwriter.PutWriter(w)
+	if w.Size() != 0 || w.Error != nil || w.NoEscapeHTML {
+		t.Errorf("GetWriter() returned a dirty writer: size %d, error %v, NoEscapeHTML %v", w.Size(), w.Error, w.NoEscapeHTML)
+	}
+}
+
+func BenchmarkPooledWriter_MarshalJSON(b *testing.B) {
+	v := pooledValue(20)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := v.MarshalJSON(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkUnpooledWriter_Marshal(b *testing.B) {
+	v := pooledValue(20)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := easyjson.Marshal(v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package tests
+
+//easyjson:json
+type PrimitivePointers struct {
+	Int8    *int8
+	Int16   *int16
+	Int32   *int32
+	Int64   *int64
+	Int     *int
+	Uint8   *uint8
+	Uint16  *uint16
+	Uint32  *uint32
+	Uint64  *uint64
+	Uint    *uint
+	Uintptr *uintptr
+	Float32 *float32
+	Float64 *float64
+	String  *string
+	Bool    *bool
+
+	Quoted    *int  `json:",string"`
+	OmitEmpty *bool `json:",omitempty"`
+	PtrPtr    **string
+}
+
+//easyjson:json
+type StringPointers struct {
+	OmitEmpty *string `json:"omit,omitempty"`
+	Plain     *string `json:"plain"`
+}
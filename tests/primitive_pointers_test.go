@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// stdPrimitivePointers has the fields of PrimitivePointers without its easyjson methods.
+type stdPrimitivePointers PrimitivePointers
+
+var primitivePointersValuesString = `{` +
+	`"Int8":-8,"Int16":-16,"Int32":-32,"Int64":-64,"Int":-1,` +
+	`"Uint8":8,"Uint16":16,"Uint32":32,"Uint64":64,"Uint":1,"Uintptr":2,` +
+	`"Float32":1.5,"Float64":-2.25,"String":"str","Bool":false,` +
+	`"Quoted":"7","OmitEmpty":false,"PtrPtr":"deep"` +
+	`}`
+
+var primitivePointersNullsString = `{` +
+	`"Int8":null,"Int16":null,"Int32":null,"Int64":null,"Int":null,` +
+	`"Uint8":null,"Uint16":null,"Uint32":null,"Uint64":null,"Uint":null,"Uintptr":null,` +
+	`"Float32":null,"Float64":null,"String":null,"Bool":null,` +
+	`"Quoted":null,"PtrPtr":null` +
+	`}`
+
+func TestPrimitivePointersDecode(t *testing.T) {
+	for _, test := range []string{
+		primitivePointersValuesString,
+		primitivePointersNullsString,
+		`{}`,
+		`{"Int":0,"String":"","PtrPtr":null}`,
+	} {
+		var got PrimitivePointers
+		if err := got.UnmarshalJSON([]byte(test)); err != nil {
+			t.Errorf("UnmarshalJSON(%s) error: %v", test, err)
+			continue
+		}
+		var want stdPrimitivePointers
+		if err := json.Unmarshal([]byte(test), &want); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error: %v", test, err)
+		}
+		if !reflect.DeepEqual(got, PrimitivePointers(want)) {
+			t.Errorf("UnmarshalJSON(%s) got %+v; want %+v", test, got, want)
+		}
+	}
+}
+
+func TestPrimitivePointersEncode(t *testing.T) {
+	var v PrimitivePointers
+	if err := v.UnmarshalJSON([]byte(primitivePointersValuesString)); err != nil {
+		t.Fatalf("UnmarshalJSON() error: %v", err)
+	}
+	for _, v := range []PrimitivePointers{v, {}} {
+		got, err := v.MarshalJSON()
+		if err != nil {
+			t.Errorf("MarshalJSON() error: %v", err)
+			continue
+		}
+		want, err := json.Marshal(stdPrimitivePointers(v))
+		if err != nil {
+			t.Fatalf("json.Marshal() error: %v", err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("MarshalJSON() = %s; want %s", got, want)
+		}
+	}
+}
+
+func TestPrimitivePointersNullResets(t *testing.T) {
+	var v PrimitivePointers
+	if err := v.UnmarshalJSON([]byte(primitivePointersValuesString)); err != nil {
+		t.Fatalf("UnmarshalJSON() error: %v", err)
+	}
+	if err := v.UnmarshalJSON([]byte(primitivePointersNullsString)); err != nil {
+		t.Fatalf("UnmarshalJSON() error: %v", err)
+	}
+	// OmitEmpty is absent in the nulls string, so it keeps its value.
+	want := PrimitivePointers{OmitEmpty: v.OmitEmpty}
+	if v.OmitEmpty == nil || !reflect.DeepEqual(v, want) {
+		t.Errorf("UnmarshalJSON() of nulls got %+v; want all fields but OmitEmpty nil", v)
+	}
+}
+
+func TestPrimitivePointersAbsentNoAllocs(t *testing.T) {
+	data := []byte(`{"Unknown":1}`)
+	allocs := testing.AllocsPerRun(100, func() {
+		var v PrimitivePointers
+		if err := v.UnmarshalJSON(data); err != nil {
+			t.Fatalf("UnmarshalJSON() error: %v", err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("UnmarshalJSON() without fields made %v allocations; want 0", allocs)
+	}
+}
+
+func TestStringPointersOmitEmpty(t *testing.T) {
+	empty, value := "", "v"
+	for i, test := range []struct {
+		v    interface{ MarshalJSON() ([]byte, error) }
+		want string
+	}{
+		// Set by the omitempty tag option.
+		{v: StringPointers{}, want: `{"plain":null}`},
+		{v: StringPointers{OmitEmpty: &empty, Plain: &empty}, want: `{"omit":"","plain":""}`},
+		{v: StringPointers{OmitEmpty: &value}, want: `{"omit":"v","plain":null}`},
+
+		// Set by -omit_empty, undone by !omitempty.
+		{v: OmitEmptyDefaultPointers{}, want: `{"kept":null}`},
+		{v: OmitEmptyDefaultPointers{Omitted: &empty, Kept: &empty}, want: `{"omitted":"","kept":""}`},
+		{v: OmitEmptyDefaultPointers{Omitted: &value}, want: `{"omitted":"v","kept":null}`},
+	} {
+		got, err := test.v.MarshalJSON()
+		if err != nil {
+			t.Errorf("[%d] MarshalJSON() error: %v", i, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("[%d] MarshalJSON() = %s; want %s", i, got, test.want)
+		}
+	}
+
+	// The omitempty tag option works like in encoding/json.
+	type stdStringPointers StringPointers
+	for _, v := range []StringPointers{{}, {OmitEmpty: &empty}} {
+		got, _ := v.MarshalJSON()
+		want, err := json.Marshal(stdStringPointers(v))
+		if err != nil {
+			t.Fatalf("json.Marshal() error: %v", err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("MarshalJSON() = %s; encoding/json %s", got, want)
+		}
+	}
+}
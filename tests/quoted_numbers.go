@@ -0,0 +1,14 @@
+package tests
+
+//easyjson:json
+type QuotedNumbers struct {
+	N      int       `json:"n"`
+	B      bool      `json:"b"`
+	F      float64   `json:"f"`
+	U8     uint8     `json:"u8"`
+	Ptr    *int64    `json:"ptr"`
+	Flags  []bool    `json:"flags"`
+	Ratios []float32 `json:"ratios"`
+	Tagged int       `json:"tagged,string"`
+	Name   string    `json:"name"`
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestAcceptQuotedNumbers(t *testing.T) {
+	var n int64 = -3
+	for i, test := range []struct {
+		data string
+		want QuotedNumbers
+	}{
+		{
+			data: `{"n":"5","b":"true","f":"1.5"}`,
+			want: QuotedNumbers{N: 5, B: true, F: 1.5},
+		},
+		{
+			data: `{"u8":"255","ptr":"-3","flags":["false",true],"ratios":["0.5",2],"tagged":"7","name":"8"}`,
+			want: QuotedNumbers{U8: 255, Ptr: &n, Flags: []bool{false, true}, Ratios: []float32{0.5, 2}, Tagged: 7, Name: "8"},
+		},
+		{
+			data: `{"n":5,"b":false,"f":1.5,"ptr":-3}`,
+			want: QuotedNumbers{N: 5, F: 1.5, Ptr: &n},
+		},
+	} {
+		var got QuotedNumbers
+		if err := got.UnmarshalJSON([]byte(test.data)); err != nil {
+			t.Errorf("[%d] UnmarshalJSON(%s) error: %v", i, test.data, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("[%d] UnmarshalJSON(%s) = %+v; want %+v", i, test.data, got, test.want)
+		}
+
+		var streamed QuotedNumbers
+		if err := streamed.DecodeJSON(bytes.NewReader([]byte(test.data))); err != nil {
+			t.Errorf("[%d] DecodeJSON(%s) error: %v", i, test.data, err)
+		} else if !reflect.DeepEqual(streamed, test.want) {
+			t.Errorf("[%d] DecodeJSON(%s) = %+v; want %+v", i, test.data, streamed, test.want)
+		}
+	}
+}
+
+func TestAcceptQuotedNumbersInvalid(t *testing.T) {
+	for _, data := range []string{
+		`{"n":"5x"}`,
+		`{"n":" 5"}`,
+		`{"u8":"256"}`,
+		`{"b":"yes"}`,
+		`{"b":1}`,
+		`{"tagged":7}`,
+		`{"name":8}`,
+	} {
+		var v QuotedNumbers
+		if err := v.UnmarshalJSON([]byte(data)); err == nil {
+			t.Errorf("UnmarshalJSON(%s) ok; want error", data)
+		}
+	}
+}
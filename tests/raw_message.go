@@ -0,0 +1,30 @@
+package tests
+
+import "encoding/json"
+
+//easyjson:json
+type RawMessages struct {
+	Object json.RawMessage
+	Array  json.RawMessage `json:",omitempty"`
+	Nil    json.RawMessage
+	NoCopy json.RawMessage `json:",nocopy"`
+	Map    map[string]json.RawMessage
+	Slice  []json.RawMessage
+}
+
+var rawMessagesValue = RawMessages{
+	Object: json.RawMessage(`{ "a" : [1, 2 ,{"b":null}],"c":"é" }`),
+	NoCopy: json.RawMessage(`"x"`),
+	Map: map[string]json.RawMessage{
+		"k": json.RawMessage(`{"nested" :{ "deep":[ ]}}`),
+	},
+	Slice: []json.RawMessage{json.RawMessage(`1.50`), json.RawMessage(`null`), json.RawMessage(`[ true,false ]`)},
+}
+
+var rawMessagesString = `{` +
+	`"Object":{ "a" : [1, 2 ,{"b":null}],"c":"é" },` +
+	`"Nil":null,` +
+	`"NoCopy":"x",` +
+	`"Map":{"k":{"nested" :{ "deep":[ ]}}},` +
+	`"Slice":[1.50,null,[ true,false ]]` +
+	`}`
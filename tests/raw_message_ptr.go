@@ -0,0 +1,10 @@
+package tests
+
+import "encoding/json"
+
+//easyjson:json
+type RawMessagePtr struct {
+	Raw       *json.RawMessage
+	OmitEmpty *json.RawMessage `json:",omitempty"`
+	List      []*json.RawMessage
+}
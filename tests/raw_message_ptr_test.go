@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mailru/easyjson"
+)
+
+// plainRawMessagePtr is RawMessagePtr without the generated methods, encoded
+// and decoded by encoding/json.
+type plainRawMessagePtr RawMessagePtr
+
+func TestRawMessagePtr(t *testing.T) {
+	for _, data := range []string{
+		`{"Raw":null,"List":null}`,
+		`{"Raw":{"a":[1,{"b":null}],"c":"d"},"OmitEmpty":[1,2],"List":[{"x":1},null,"s"]}`,
+		`{"Raw":"str","List":[]}`,
+	} {
+		var got RawMessagePtr
+		if err := easyjson.Unmarshal([]byte(data), &got); err != nil {
+			t.Errorf("Unmarshal(%s) error: %v", data, err)
+			continue
+		}
+		var want plainRawMessagePtr
+		if err := json.Unmarshal([]byte(data), &want); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error: %v", data, err)
+		}
+		if !reflect.DeepEqual(got, RawMessagePtr(want)) {
+			t.Errorf("Unmarshal(%s) = %+v; want %+v", data, got, want)
+		}
+
+		out, err := easyjson.Marshal(got)
+		if err != nil {
+			t.Errorf("Marshal(%+v) error: %v", got, err)
+			continue
+		}
+		wantOut, err := json.Marshal(want)
+		if err != nil {
+			t.Fatalf("json.Marshal(%+v) error: %v", want, err)
+		}
+		if string(out) != string(wantOut) {
+			t.Errorf("Marshal(%+v) = %s; want %s", got, out, wantOut)
+		}
+	}
+}
+
+func TestRawMessagePtrReuse(t *testing.T) {
+	old := json.RawMessage(`"old"`)
+	v := RawMessagePtr{Raw: &old, List: []*json.RawMessage{&old}}
+	if err := easyjson.Unmarshal([]byte(`{"Raw":null,"List":[null]}`), &v); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if v.Raw != nil || len(v.List) != 1 || v.List[0] != nil {
+		t.Errorf("Unmarshal() of nulls = %+v; want nil pointers", v)
+	}
+
+	data := []byte(`{"Raw":{"nested":{"a":1}}}`)
+	if err := easyjson.Unmarshal(data, &v); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	for i := range data {
+		data[i] = 'x'
+	}
+	if v.Raw == nil || string(*v.Raw) != `{"nested":{"a":1}}` {
+		t.Errorf("Unmarshal() = %+v after changing the input; want the raw object kept", v)
+	}
+
+	empty := json.RawMessage{}
+	out, err := easyjson.Marshal(RawMessagePtr{OmitEmpty: &empty})
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	if want := `{"Raw":null,"OmitEmpty":null,"List":null}`; string(out) != want {
+		t.Errorf("Marshal() = %s; want %s", out, want)
+	}
+}
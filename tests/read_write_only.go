@@ -0,0 +1,11 @@
+package tests
+
+//easyjson:json
+type Credentials struct {
+	User     string  `json:"user"`
+	Password string  `json:"pw,readonly"`
+	Token    *string `json:"token,readonly,omitempty"`
+	Hash     string  `json:"hash,computed"`
+	Sessions []int   `json:"sessions,computed"`
+	Note     *string `json:"note,computed"`
+}
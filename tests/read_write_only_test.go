@@ -0,0 +1,31 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadOnlyAndComputedFields(t *testing.T) {
+	token, note := "t", "n"
+	v := Credentials{User: "u", Password: "secret", Token: &token, Hash: "h", Sessions: []int{1}, Note: &note}
+	want := `{"user":"u","hash":"h","sessions":[1],"note":"n"}`
+
+	data, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if string(data) != want {
+		t.Errorf("MarshalJSON() = %s; want %s", data, want)
+	}
+
+	got := Credentials{Hash: "kept", Note: &note}
+	input := `{"user":"v","pw":"secret2","token":"t2","hash":"h2","sessions":[2,3],"note":null}`
+	if err := got.UnmarshalJSON([]byte(input)); err != nil {
+		t.Fatalf("UnmarshalJSON(%s) error: %v", input, err)
+	}
+	token2 := "t2"
+	wantDecoded := Credentials{User: "v", Password: "secret2", Token: &token2, Hash: "kept", Note: &note}
+	if !reflect.DeepEqual(got, wantDecoded) {
+		t.Errorf("UnmarshalJSON(%s) = %+v; want %+v", input, got, wantDecoded)
+	}
+}
@@ -0,0 +1,53 @@
+package tests
+
+//easyjson:json
+type ListNode struct {
+	Val  int
+	Next *ListNode
+}
+
+var listValue = ListNode{Val: 1, Next: &ListNode{Val: 2, Next: &ListNode{Val: 3}}}
+
+var listString = `{"Val":1,"Next":{"Val":2,"Next":{"Val":3,"Next":null}}}`
+
+//easyjson:json
+type RecursiveA struct {
+	Name string
+	B    *RecursiveB
+}
+
+type RecursiveB struct {
+	As   []RecursiveA
+	Next map[string]*RecursiveB
+}
+
+var recursiveAValue = RecursiveA{
+	Name: "root",
+	B: &RecursiveB{
+		As: []RecursiveA{{Name: "leaf"}},
+		Next: map[string]*RecursiveB{
+			"x": {As: []RecursiveA{}},
+		},
+	},
+}
+
+var recursiveAString = `{"Name":"root","B":{"As":[{"Name":"leaf","B":null}],"Next":{"x":{"As":[],"Next":null}}}}`
+
+//easyjson:json
+type RecursiveSlice []RecursiveSlice
+
+//easyjson:json
+type RecursiveMap map[string]RecursiveMap
+
+//easyjson:json
+type RecursiveContainers struct {
+	Slice RecursiveSlice
+	Map   RecursiveMap
+}
+
+var recursiveContainersValue = RecursiveContainers{
+	Slice: RecursiveSlice{RecursiveSlice{}, RecursiveSlice{RecursiveSlice{}}},
+	Map:   RecursiveMap{"a": RecursiveMap{"b": RecursiveMap{}}, "c": nil},
+}
+
+var recursiveContainersString = `{"Slice":[[],[[]]],"Map":{"a":{"b":{}},"c":null}}`
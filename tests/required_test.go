@@ -28,6 +28,30 @@ func TestRequiredField(t *testing.T) {
 	}
 }
 
+func TestRequiredFieldPair(t *testing.T) {
+	cases := []struct{ json, errorMessage string }{
+		{`{"id":1,"name":"Foo"}`, ""},
+		{`{"id":null,"name":null}`, ""},
+		{`{"id":1,"note":"Bar"}`, "key 'name' is required"},
+		{`{"name":"Foo"}`, "key 'id' is required"},
+		{`{"note":null}`, "keys 'id', 'name' are required"},
+	}
+
+	for _, tc := range cases {
+		var v RequiredPairStruct
+		err := v.UnmarshalJSON([]byte(tc.json))
+		if tc.errorMessage == "" {
+			if err != nil {
+				t.Errorf("%s. UnmarshalJSON didn`t expect error: %v", tc.json, err)
+			}
+		} else {
+			if fmt.Sprintf("%v", err) != tc.errorMessage {
+				t.Errorf("%s. UnmarshalJSON expected error: %v. got: %v", tc.json, tc.errorMessage, err)
+			}
+		}
+	}
+}
+
 func TestRequiredOptionalMap(t *testing.T) {
 	baseJson := `{"req_map":{}, "oe_map":{}, "noe_map":{}, "oe_slice":[]}`
 	wantDecoding := RequiredOptionalMap{MapIntString{}, nil, MapIntString{}}
@@ -49,3 +73,26 @@ func TestRequiredOptionalMap(t *testing.T) {
 		t.Errorf("%v. MarshalJSON wanted: %s got %s", baseStruct, wantJson, string(data))
 	}
 }
+
+func TestRequiredEmbedded(t *testing.T) {
+	cases := []struct{ json, errorMessage string }{
+		{`{"a":1,"b":2}`, ""},
+		{`{"a":1}`, "key 'b' is required"},
+		{`{"b":2}`, "key 'a' is required"},
+		{`{}`, "keys 'b', 'a' are required"},
+	}
+
+	for _, tc := range cases {
+		var v EmbeddedRequired
+		err := v.UnmarshalJSON([]byte(tc.json))
+		if tc.errorMessage == "" {
+			if err != nil {
+				t.Errorf("%s. UnmarshalJSON didn`t expect error: %v", tc.json, err)
+			} else if v.EmbeddedRequiredA.X != 1 || v.EmbeddedRequiredB.X != 2 {
+				t.Errorf("%s. UnmarshalJSON got %+v", tc.json, v)
+			}
+		} else if fmt.Sprintf("%v", err) != tc.errorMessage {
+			t.Errorf("%s. UnmarshalJSON expected error: %v. got: %v", tc.json, tc.errorMessage, err)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package tests
+
+//easyjson:json
+type ResetTarget struct {
+	Name   string
+	Count  int
+	OK     bool
+	Tags   map[string]int
+	Values []int
+	Items  []ResetInner
+	Inner  ResetInner
+	Plain  MergeInner
+	Ptr    *int
+	Pair   [2]int
+	Any    interface{}
+
+	hidden string
+}
+
+//easyjson:json
+type ResetInner struct {
+	Name  string
+	Codes []string
+}
+
+//easyjson:json
+type ResetList []int
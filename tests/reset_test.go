@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReset(t *testing.T) {
+	var v ResetTarget
+	data := `{"Name":"a","Count":2,"OK":true,"Tags":{"a":1},"Values":[1,2,3],` +
+		`"Items":[{"Name":"i","Codes":["x"]}],"Inner":{"Name":"b","Codes":["c","d"]},` +
+		`"Plain":{"A":"e"},"Ptr":5,"Pair":[6,7],"Any":"f"}`
+	if err := v.UnmarshalJSON([]byte(data)); err != nil {
+		t.Fatalf("UnmarshalJSON() error: %v", err)
+	}
+	v.hidden = "g"
+	valuesCap, itemsCap, codesCap := cap(v.Values), cap(v.Items), cap(v.Inner.Codes)
+
+	v.Reset()
+
+	if cap(v.Values) != valuesCap || cap(v.Items) != itemsCap || cap(v.Inner.Codes) != codesCap {
+		t.Errorf("Reset() capacities = %d, %d, %d; want %d, %d, %d",
+			cap(v.Values), cap(v.Items), cap(v.Inner.Codes), valuesCap, itemsCap, codesCap)
+	}
+	if len(v.Values) != 0 || len(v.Items) != 0 || len(v.Inner.Codes) != 0 {
+		t.Errorf("Reset() left slice elements: %+v", v)
+	}
+	v.Values, v.Items, v.Inner.Codes = nil, nil, nil
+	if !reflect.DeepEqual(v, ResetTarget{}) {
+		t.Errorf("Reset() = %+v; want zero value", v)
+	}
+
+	if err := v.UnmarshalJSON([]byte(`{"Values":[4]}`)); err != nil {
+		t.Fatalf("UnmarshalJSON() of a reset value error: %v", err)
+	}
+	if !reflect.DeepEqual(v.Values, []int{4}) || v.Name != "" {
+		t.Errorf("UnmarshalJSON() of a reset value = %+v", v)
+	}
+
+	l := ResetList{1, 2}
+	l.Reset()
+	if len(l) != 0 || cap(l) != 2 {
+		t.Errorf("Reset() of a slice type = %v with capacity %d; want empty with capacity 2", l, cap(l))
+	}
+}
@@ -0,0 +1,12 @@
+package tests
+
+//easyjson:json
+type SetValues struct {
+	Tags  map[string]struct{}  `json:",set"`
+	IDs   map[int]struct{}     `json:",set,omitempty"`
+	Ptr   *map[string]struct{} `json:",set"`
+	Named StringSet            `json:",set"`
+	Plain map[string]struct{}
+}
+
+type StringSet map[string]struct{}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetValues(t *testing.T) {
+	ptr := map[string]struct{}{"p": {}}
+	v := SetValues{
+		Tags:  map[string]struct{}{"b": {}, "a": {}},
+		IDs:   map[int]struct{}{7: {}},
+		Ptr:   &ptr,
+		Named: StringSet{"n": {}},
+		Plain: map[string]struct{}{"x": {}},
+	}
+	want := `{"Tags":["a","b"],"IDs":[7],"Ptr":["p"],"Named":["n"],"Plain":{"x":{}}}`
+
+	data, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if string(data) != want {
+		t.Errorf("MarshalJSON() = %s; want %s", data, want)
+	}
+
+	var got SetValues
+	if err := got.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON(%s) error: %v", data, err)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("UnmarshalJSON(%s) = %+v; want %+v", data, got, v)
+	}
+}
+
+func TestSetValuesDecode(t *testing.T) {
+	var v SetValues
+	data := `{"Tags":["b","a","b",null],"Named":[],"Ptr":null}`
+	if err := v.UnmarshalJSON([]byte(data)); err != nil {
+		t.Fatalf("UnmarshalJSON(%s) error: %v", data, err)
+	}
+	want := SetValues{
+		Tags:  map[string]struct{}{"a": {}, "b": {}},
+		Named: StringSet{},
+	}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("UnmarshalJSON(%s) = %+v; want %+v", data, v, want)
+	}
+
+	// Keys are added to an existing set.
+	data = `{"Tags":["c"]}`
+	if err := v.UnmarshalJSON([]byte(data)); err != nil {
+		t.Fatalf("UnmarshalJSON(%s) error: %v", data, err)
+	}
+	if len(v.Tags) != 3 {
+		t.Errorf("UnmarshalJSON(%s) into a set of 2 keys = %v; want 3 keys", data, v.Tags)
+	}
+
+	data = `{"Tags":{"a":{}}}`
+	if err := v.UnmarshalJSON([]byte(data)); err == nil {
+		t.Errorf("UnmarshalJSON(%s) = %+v; want an error", data, v)
+	}
+}
+
+func TestSetValuesNull(t *testing.T) {
+	data, err := SetValues{}.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if want := `{"Tags":null,"Ptr":null,"Named":null,"Plain":null}`; string(data) != want {
+		t.Errorf("MarshalJSON() = %s; want %s", data, want)
+	}
+}
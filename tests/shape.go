@@ -0,0 +1,45 @@
+package tests
+
+// Shape is an interface with concrete types registered with the generator in
+// api_gen.go, chosen by the "kind" member of the object.
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	Kind   string `json:"kind"`
+	Radius float64
+}
+
+func (c Circle) Area() float64 { return 3 * c.Radius * c.Radius }
+
+type Square struct {
+	Kind string `json:"kind"`
+	Side float64
+}
+
+func (s *Square) Area() float64 { return s.Side * s.Side }
+
+type ShapeLabel struct {
+	Kind  string `json:"kind"`
+	Value interface{}
+}
+
+func (ShapeLabel) Area() float64 { return 0 }
+
+type Shapes struct {
+	Main   Shape
+	Others []Shape
+}
+
+var shapesValue = Shapes{
+	Main: Circle{Kind: "circle", Radius: 2},
+	Others: []Shape{
+		&Square{Kind: "square", Side: 3},
+		nil,
+		Circle{Kind: "circle", Radius: 1},
+	},
+}
+
+var shapesString = `{"Main":{"kind":"circle","Radius":2},` +
+	`"Others":[{"kind":"square","Side":3},null,{"kind":"circle","Radius":1}]}`
@@ -0,0 +1,20 @@
+package tests
+
+import "time"
+
+//easyjson:json
+type SizeEstimated struct {
+	Name     string
+	Tags     []string
+	Counts   map[string]int
+	Scores   []float64
+	Children []*SizeEstimated
+	Parent   *SizeEstimated
+	Data     []byte
+	Time     time.Time
+	Flags    map[int]bool
+	*EmbeddedPtrBase
+}
+
+//easyjson:json
+type SizeEstimatedSlice []SizeEstimated
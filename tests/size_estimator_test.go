@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSizeEstimator(t *testing.T) {
+	leaf := &SizeEstimated{Name: "leaf", Tags: []string{"a"}}
+	for i, v := range []SizeEstimated{
+		{},
+		{Name: "small", Time: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{
+			Name:     strings.Repeat("long name ", 100),
+			Tags:     []string{"one", "two", "three", strings.Repeat("x", 500)},
+			Counts:   map[string]int{"apples": 10, "oranges": 200, "pears": 3000},
+			Scores:   []float64{1, 2.5, 1e10, -0.125},
+			Children: []*SizeEstimated{leaf, nil, leaf},
+			Parent:   &SizeEstimated{Name: "parent", Data: make([]byte, 300)},
+			Data:     []byte(strings.Repeat("data", 200)),
+			Flags:    map[int]bool{1: true, 20: false, 300: true},
+
+			EmbeddedPtrBase: &EmbeddedPtrBase{BaseField: 5},
+		},
+	} {
+		data, err := v.MarshalJSON()
+		if err != nil {
+			t.Fatalf("[%d] MarshalJSON() error: %v", i, err)
+		}
+		if est := v.estimatedSize(); est < len(data)/2 || est > len(data)*2 {
+			t.Errorf("[%d] estimatedSize() = %d; want within a factor of 2 of %d", i, est, len(data))
+		}
+	}
+
+	s := SizeEstimatedSlice{{Name: "a"}, {Name: strings.Repeat("b", 1000)}}
+	data, err := s.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if est := s.estimatedSize(); est < len(data)/2 || est > len(data)*2 {
+		t.Errorf("estimatedSize() = %d; want within a factor of 2 of %d", est, len(data))
+	}
+}
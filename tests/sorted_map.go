@@ -0,0 +1,12 @@
+package tests
+
+//easyjson:json
+type SortedMap map[string]int
+
+//easyjson:json
+type SortedMapStruct struct {
+	Named  map[Str]int
+	Nested map[string]map[string]bool
+	Ints   map[int]string
+	Uints  map[uint8][]map[int64]string
+}
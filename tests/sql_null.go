@@ -0,0 +1,15 @@
+package tests
+
+import "database/sql"
+
+//easyjson:json
+type SQLNulls struct {
+	Name    sql.NullString   `json:"name"`
+	Count   sql.NullInt64    `json:"count"`
+	Ratio   sql.NullFloat64  `json:"ratio"`
+	Enabled sql.NullBool     `json:"enabled"`
+	At      sql.NullTime     `json:"at"`
+	Note    sql.NullString   `json:"note,omitempty"`
+	Names   []sql.NullString `json:"names"`
+	Ptr     *sql.NullInt64   `json:"ptr"`
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSQLNullTypes(t *testing.T) {
+	at := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, test := range []struct {
+		v    SQLNulls
+		data string
+	}{
+		{
+			v: SQLNulls{
+				Name:    sql.NullString{String: "x", Valid: true},
+				Count:   sql.NullInt64{Int64: 42, Valid: true},
+				Ratio:   sql.NullFloat64{Float64: 0.5, Valid: true},
+				Enabled: sql.NullBool{Bool: true, Valid: true},
+				At:      sql.NullTime{Time: at, Valid: true},
+				Note:    sql.NullString{String: "", Valid: true},
+				Names:   []sql.NullString{{String: "a", Valid: true}, {}},
+				Ptr:     &sql.NullInt64{Int64: 0, Valid: true},
+			},
+			data: `{"name":"x","count":42,"ratio":0.5,"enabled":true,"at":"2021-01-02T03:04:05Z","note":"","names":["a",null],"ptr":0}`,
+		},
+		{
+			v:    SQLNulls{Ptr: &sql.NullInt64{}},
+			data: `{"name":null,"count":null,"ratio":null,"enabled":null,"at":null,"names":null,"ptr":null}`,
+		},
+	} {
+		data, err := test.v.MarshalJSON()
+		if err != nil {
+			t.Errorf("[%d] MarshalJSON() error: %v", i, err)
+			continue
+		}
+		if string(data) != test.data {
+			t.Errorf("[%d] MarshalJSON() = %s; want %s", i, data, test.data)
+		}
+
+		var got SQLNulls
+		if err := got.UnmarshalJSON(data); err != nil {
+			t.Errorf("[%d] UnmarshalJSON(%s) error: %v", i, data, err)
+			continue
+		}
+		want := test.v
+		if want.Ptr != nil && !want.Ptr.Valid {
+			// A null pointer field decodes to a nil pointer.
+			want.Ptr = nil
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("[%d] UnmarshalJSON(%s) = %+v; want %+v", i, data, got, want)
+		}
+	}
+}
+
+func TestSQLNullTypesDecodeNullIntoValid(t *testing.T) {
+	v := SQLNulls{
+		Name:  sql.NullString{String: "x", Valid: true},
+		Count: sql.NullInt64{Int64: 1, Valid: true},
+		Names: []sql.NullString{{String: "a", Valid: true}},
+	}
+	if err := v.UnmarshalJSON([]byte(`{"name":null,"count":null,"names":[null]}`)); err != nil {
+		t.Fatalf("UnmarshalJSON() error: %v", err)
+	}
+	if v.Name.Valid || v.Name.String != "" || v.Count.Valid || v.Count.Int64 != 0 || len(v.Names) != 1 || v.Names[0].Valid {
+		t.Errorf("UnmarshalJSON() of nulls = %+v; want invalid values", v)
+	}
+}
@@ -0,0 +1,6 @@
+package tests
+
+//easyjson:json
+type StreamingStruct struct {
+	Items []string
+}
@@ -0,0 +1,15 @@
+package tests
+
+//easyjson:json
+type StreamingDecodeStruct struct {
+	Name  string
+	Items []StreamingDecodeItem
+	Tags  map[string]string
+	Extra interface{}
+}
+
+type StreamingDecodeItem struct {
+	ID    int64
+	Value float64
+	Text  string
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStreamingDecodePipe(t *testing.T) {
+	want := StreamingDecodeStruct{
+		Name:  `a "quoted" name with \ escapes`,
+		Tags:  map[string]string{"key": "value", "ключ": "значение"},
+		Extra: map[string]interface{}{"list": []interface{}{1.5, true, nil, "x"}},
+	}
+	for i := 0; i < 1000; i++ {
+		want.Items = append(want.Items, StreamingDecodeItem{
+			ID:    int64(i) * 1000003,
+			Value: float64(i) / 7,
+			Text:  fmt.Sprint("item number ", i, strings.Repeat("é", i%10)),
+		})
+	}
+	data, err := want.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+
+	// Feed the data in small chunks so that tokens span them.
+	pr, pw := io.Pipe()
+	go func() {
+		for rest := data; len(rest) > 0; {
+			n := 7
+			if n > len(rest) {
+				n = len(rest)
+			}
+			if _, err := pw.Write(rest[:n]); err != nil {
+				return
+			}
+			rest = rest[n:]
+		}
+		pw.Close()
+	}()
+
+	var got StreamingDecodeStruct
+	if err := got.DecodeJSON(pr); err != nil {
+		t.Fatalf("DecodeJSON() error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeJSON() = %+v; want %+v", got, want)
+	}
+}
+
+func TestStreamingDecodeErrors(t *testing.T) {
+	errBroken := errors.New("broken pipe")
+	for i, test := range []struct {
+		data    string
+		readErr error
+		wantErr error
+	}{
+		{data: `{"Name":"x"}`},
+		{data: `{"Name":"x"} {}`, wantErr: errors.New("invalid character '{' after top-level value")},
+		{data: `{"Name":"x"`, readErr: errBroken, wantErr: errBroken},
+		{data: `{"Name":"x"}`, readErr: errBroken, wantErr: errBroken},
+		{data: `{"Name":"x`, wantErr: errors.New("unterminated string literal")},
+	} {
+		pr, pw := io.Pipe()
+		go func(data string, err error) {
+			pw.Write([]byte(data))
+			pw.CloseWithError(err)
+		}(test.data, test.readErr)
+
+		var v StreamingDecodeStruct
+		err := v.DecodeJSON(pr)
+		switch {
+		case test.wantErr == nil:
+			if err != nil {
+				t.Errorf("[%d] DecodeJSON(%s) error: %v", i, test.data, err)
+			}
+		case err == nil:
+			t.Errorf("[%d] DecodeJSON(%s) ok; want error %v", i, test.data, test.wantErr)
+		case !strings.Contains(err.Error(), test.wantErr.Error()):
+			t.Errorf("[%d] DecodeJSON(%s) error %v; want %v", i, test.data, err, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+// countingWriter counts the data written to it and keeps track of the largest write.
+type countingWriter struct {
+	written  int
+	maxWrite int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.written += len(p)
+	if len(p) > w.maxWrite {
+		w.maxWrite = len(p)
+	}
+	return len(p), nil
+}
+
+func TestStreamingBoundedMemory(t *testing.T) {
+	v := StreamingStruct{Items: make([]string, 100000)}
+	for i := range v.Items {
+		v.Items[i] = fmt.Sprint("item number ", i)
+	}
+
+	data, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+
+	var w countingWriter
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	err = v.EncodeJSON(&w)
+	runtime.ReadMemStats(&after)
+
+	if err != nil {
+		t.Fatalf("EncodeJSON() error: %v", err)
+	}
+	if w.written != len(data) {
+		t.Errorf("EncodeJSON() wrote %d bytes; want %d", w.written, len(data))
+	}
+	if w.maxWrite > 64*1024 {
+		t.Errorf("EncodeJSON() wrote %d bytes at once; want at most %d", w.maxWrite, 64*1024)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > uint64(len(data)/4) {
+		t.Errorf("EncodeJSON() allocated %d bytes for %d bytes of output", allocated, len(data))
+	}
+}
+
+func TestStreamingOutput(t *testing.T) {
+	v := StreamingStruct{Items: []string{"a", "<b>"}}
+
+	var buf bytes.Buffer
+	if err := v.EncodeJSON(&buf); err != nil {
+		t.Fatalf("EncodeJSON() error: %v", err)
+	}
+	if want := `{"Items":["a","\u003cb\u003e"]}`; buf.String() != want {
+		t.Errorf("EncodeJSON() = %s; want %s", buf.String(), want)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestStreamingWriteError(t *testing.T) {
+	v := StreamingStruct{Items: []string{"a"}}
+	if err := v.EncodeJSON(failingWriter{}); err == nil || err.Error() != "write failed" {
+		t.Errorf("EncodeJSON() error = %v; want write failed", err)
+	}
+}
@@ -0,0 +1,7 @@
+package tests
+
+//easyjson:json
+type StrictArrays struct {
+	Ints  [4]int
+	Bytes [2]byte
+}
@@ -0,0 +1,13 @@
+package tests
+
+//easyjson:json
+type TagKeyStruct struct {
+	Name    string `api:"name" json:"json_name"`
+	Count   int    `api:"count,omitempty"`
+	Skipped int    `api:"-"`
+	Plain   int
+}
+
+var tagKeyValue = TagKeyStruct{Name: "test", Plain: 2}
+
+var tagKeyString = `{"name":"test","Plain":2}`
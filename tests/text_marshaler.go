@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+)
+
+type Color int
+
+const (
+	ColorNone Color = iota
+	ColorRed
+	ColorGreen
+)
+
+var colorNames = []string{"", "red", "green"}
+
+func (c Color) MarshalText() ([]byte, error) {
+	if int(c) >= len(colorNames) {
+		return nil, errors.New("unknown color")
+	}
+	return []byte(colorNames[c]), nil
+}
+
+func (c *Color) UnmarshalText(text []byte) error {
+	for i, name := range colorNames {
+		if strings.EqualFold(name, string(text)) {
+			*c = Color(i)
+			return nil
+		}
+	}
+	return errors.New("unknown color " + string(text))
+}
+
+//easyjson:json
+type TextMarshalerStruct struct {
+	Color  Color
+	None   Color
+	Ptr    *Color
+	Colors []Color
+	ByKey  map[Color]int
+}
+
+var textMarshalerPtr = ColorGreen
+
+var textMarshalerValue = TextMarshalerStruct{
+	Color:  ColorRed,
+	Ptr:    &textMarshalerPtr,
+	Colors: []Color{ColorGreen, ColorRed},
+	ByKey:  map[Color]int{ColorNone: 1},
+}
+
+var textMarshalerString = `{` +
+	`"Color":"red",` +
+	`"None":"",` +
+	`"Ptr":"green",` +
+	`"Colors":["green","red"],` +
+	`"ByKey":{"":1}` +
+	`}`
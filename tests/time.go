@@ -0,0 +1,31 @@
+package tests
+
+import "time"
+
+//easyjson:json
+type TimeStruct struct {
+	Time   time.Time
+	Zero   time.Time
+	Ptr    *time.Time
+	NilPtr *time.Time
+	Date   time.Time   `tformat:"2006-01-02"`
+	Times  []time.Time `json:"times"`
+}
+
+var timeStructPtr = time.Date(2020, 2, 3, 4, 5, 6, 7, time.UTC)
+
+var timeStructValue = TimeStruct{
+	Time:  time.Date(2021, 1, 2, 3, 4, 5, 600000000, time.UTC),
+	Ptr:   &timeStructPtr,
+	Date:  time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC),
+	Times: []time.Time{time.Date(2023, 5, 6, 7, 8, 9, 0, time.FixedZone("", 3*60*60))},
+}
+
+var timeStructString = `{` +
+	`"Time":"2021-01-02T03:04:05.6Z",` +
+	`"Zero":"0001-01-01T00:00:00Z",` +
+	`"Ptr":"2020-02-03T04:05:06.000000007Z",` +
+	`"NilPtr":null,` +
+	`"Date":"2022-12-31",` +
+	`"times":["2023-05-06T07:08:09+03:00"]` +
+	`}`
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mailru/easyjson/jlexer"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+func TestTimeLayoutEscaping(t *testing.T) {
+	at := time.Date(2020, time.March, 4, 0, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		layout string
+		want   string
+	}{
+		{layout: "2006-01-02", want: `"2020-03-04"`},
+		{layout: `"Jan" 2`, want: `"\"Mar\" 4"`},
+		{layout: `2006\01`, want: `"2020\\03"`},
+		{layout: "2006\t01", want: `"2020\t03"`},
+	} {
+		w := jwriter.Writer{}
+		w.Time(at, test.layout)
+		got, err := w.BuildBytes()
+		if err != nil {
+			t.Errorf("Time(%q) error: %v", test.layout, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("Time(%q) = %s; want %s", test.layout, got, test.want)
+		}
+
+		want, _ := time.Parse(test.layout, at.Format(test.layout))
+		l := jlexer.Lexer{Data: got}
+		if back := l.Time(test.layout); !back.Equal(want) || l.Error() != nil {
+			t.Errorf("Time(%q) of %s = %v, %v; want %v", test.layout, got, back, l.Error(), want)
+		}
+	}
+}
+
+func TestTimeYearOutOfRange(t *testing.T) {
+	for _, year := range []int{-1, 10000} {
+		at := time.Date(year, time.March, 4, 0, 0, 0, 0, time.UTC)
+		for _, layout := range []string{time.RFC3339, time.RFC3339Nano} {
+			w := jwriter.Writer{}
+			w.Time(at, layout)
+			if got, err := w.BuildBytes(); err == nil {
+				t.Errorf("Time(%v, %q) = %s; want error", at, layout, got)
+			}
+		}
+
+		// Other layouts have no range of years.
+		w := jwriter.Writer{}
+		w.Time(at, "2006-01-02")
+		if _, err := w.BuildBytes(); err != nil {
+			t.Errorf("Time(%v, %q) error: %v", at, "2006-01-02", err)
+		}
+	}
+}
+
+func TestTimeStructYearOutOfRange(t *testing.T) {
+	v := TimeStruct{Time: time.Date(10000, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	if got, err := v.MarshalJSON(); err == nil {
+		t.Errorf("MarshalJSON() = %s; want error", got)
+	}
+	if _, err := json.Marshal(v.Time); err == nil {
+		t.Errorf("json.Marshal() ok; want error")
+	}
+}
@@ -0,0 +1,13 @@
+package tests
+
+type Item struct {
+	ID   int      `json:"id"`
+	Name string   `json:"name"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+//easyjson:json
+type Items []Item
+
+//easyjson:json
+type ItemIndex map[string]Item
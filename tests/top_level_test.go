@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTopLevelArray(t *testing.T) {
+	v := Items{{ID: 1, Name: "a", Tags: []string{"x", "y"}}, {ID: 2, Name: "b"}}
+	want := `[{"id":1,"name":"a","tags":["x","y"]},{"id":2,"name":"b"}]`
+
+	data, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if string(data) != want {
+		t.Errorf("MarshalJSON() = %s; want %s", data, want)
+	}
+
+	var got Items
+	if err := got.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON(%s) error: %v", data, err)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("UnmarshalJSON(%s) = %+v; want %+v", data, got, v)
+	}
+
+	for _, data := range []string{`[]`, `null`, ` [ {"id":3} ] `} {
+		var want, got Items
+		if err := json.Unmarshal([]byte(data), &want); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error: %v", data, err)
+		}
+		if err := got.UnmarshalJSON([]byte(data)); err != nil {
+			t.Errorf("UnmarshalJSON(%s) error: %v", data, err)
+		} else if !reflect.DeepEqual(got, want) {
+			t.Errorf("UnmarshalJSON(%s) = %#v; want %#v", data, got, want)
+		}
+	}
+
+	if err := got.UnmarshalJSON([]byte(`{"id":1}`)); err == nil {
+		t.Errorf("UnmarshalJSON() of an object into Items ok; want error")
+	}
+}
+
+func TestTopLevelMap(t *testing.T) {
+	v := ItemIndex{"b": {ID: 2, Name: "b"}, "a": {ID: 1, Name: "a"}}
+	want := `{"a":{"id":1,"name":"a"},"b":{"id":2,"name":"b"}}`
+
+	data, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if string(data) != want {
+		t.Errorf("MarshalJSON() = %s; want %s", data, want)
+	}
+
+	var got ItemIndex
+	if err := got.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON(%s) error: %v", data, err)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("UnmarshalJSON(%s) = %+v; want %+v", data, got, v)
+	}
+}
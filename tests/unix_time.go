@@ -0,0 +1,12 @@
+package tests
+
+import "time"
+
+//easyjson:json
+type UnixTimes struct {
+	Seconds time.Time   `json:"seconds" tformat:"unix"`
+	Millis  time.Time   `json:"millis" tformat:"unixmilli"`
+	Ptr     *time.Time  `json:"ptr" tformat:"unixmilli"`
+	List    []time.Time `json:"list" tformat:"unix"`
+	Layout  time.Time   `json:"layout"`
+}
@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnixTimes(t *testing.T) {
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 891234567, time.UTC)
+	before := time.Date(1969, 12, 31, 23, 59, 58, 750000000, time.UTC)
+	v := UnixTimes{
+		Seconds: ts,
+		Millis:  ts,
+		Ptr:     &before,
+		List:    []time.Time{ts, before},
+		Layout:  ts,
+	}
+	want := `{"seconds":1614834367,"millis":1614834367891,"ptr":-1250,"list":[1614834367,-2],"layout":"2021-03-04T05:06:07.891234567Z"}`
+
+	data, err := v.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error: %v", err)
+	}
+	if string(data) != want {
+		t.Errorf("MarshalJSON() = %s; want %s", data, want)
+	}
+
+	var got UnixTimes
+	if err := got.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON(%s) error: %v", data, err)
+	}
+	checks := []struct {
+		name      string
+		got, want time.Time
+	}{
+		{"seconds", got.Seconds, ts.Truncate(time.Second)},
+		{"millis", got.Millis, ts.Truncate(time.Millisecond)},
+		{"list[0]", got.List[0], ts.Truncate(time.Second)},
+		{"list[1]", got.List[1], time.Date(1969, 12, 31, 23, 59, 58, 0, time.UTC)},
+		{"layout", got.Layout, ts},
+	}
+	if got.Ptr == nil {
+		t.Errorf("UnmarshalJSON(%s) ptr = nil", data)
+	} else {
+		checks = append(checks, struct {
+			name      string
+			got, want time.Time
+		}{"ptr", *got.Ptr, before})
+	}
+	for _, c := range checks {
+		if !c.got.Equal(c.want) || c.got.Location() != time.UTC {
+			t.Errorf("UnmarshalJSON(%s) %s = %v; want %v", data, c.name, c.got, c.want)
+		}
+	}
+
+	got = UnixTimes{}
+	if err := got.UnmarshalJSON([]byte(`{"seconds":"1614834367"}`)); err == nil {
+		t.Errorf("UnmarshalJSON() of a string for a unix time ok; want error")
+	}
+}
@@ -0,0 +1,32 @@
+package tests
+
+import "github.com/mailru/easyjson/jlexer"
+
+// UnknownCounter counts the keys matching none of its fields.
+//
+//easyjson:json
+type UnknownCounter struct {
+	Name    string
+	Unknown int `json:"-"`
+}
+
+func (c *UnknownCounter) UnknownField(key string, in *jlexer.Lexer) {
+	c.Unknown++
+	in.SkipRecursive()
+}
+
+// UnknownCapture keeps the trace_id key matching none of its fields.
+//
+//easyjson:json
+type UnknownCapture struct {
+	Name    string
+	TraceID string `json:"-"`
+}
+
+func (c *UnknownCapture) UnknownField(key string, in *jlexer.Lexer) {
+	if key == "trace_id" {
+		c.TraceID = in.String()
+		return
+	}
+	in.SkipRecursive()
+}
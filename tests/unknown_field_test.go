@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/mailru/easyjson"
+)
+
+func TestUnknownFieldCount(t *testing.T) {
+	for _, test := range []struct {
+		data string
+		want UnknownCounter
+	}{
+		{data: `{}`, want: UnknownCounter{}},
+		{data: `{"Name":"n"}`, want: UnknownCounter{Name: "n"}},
+		{data: `{"a":1,"Name":"n","b":{"c":[1,2]},"d":null,"e":"s"}`, want: UnknownCounter{Name: "n", Unknown: 4}},
+		{data: `{"Name":null,"x":null}`, want: UnknownCounter{Unknown: 1}},
+	} {
+		var got UnknownCounter
+		if err := easyjson.Unmarshal([]byte(test.data), &got); err != nil {
+			t.Errorf("Unmarshal(%s) error: %v", test.data, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Unmarshal(%s) = %+v; want %+v", test.data, got, test.want)
+		}
+	}
+}
+
+func TestUnknownFieldCapture(t *testing.T) {
+	data := `{"span":{"id":[1,2]},"trace_id":"abc","Name":"n","other":null}`
+	var got UnknownCapture
+	if err := easyjson.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatalf("Unmarshal(%s) error: %v", data, err)
+	}
+	if want := (UnknownCapture{Name: "n", TraceID: "abc"}); got != want {
+		t.Errorf("Unmarshal(%s) = %+v; want %+v", data, got, want)
+	}
+
+	out, err := easyjson.Marshal(got)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	if want := `{"Name":"n"}`; string(out) != want {
+		t.Errorf("Marshal() = %s; want %s", out, want)
+	}
+}
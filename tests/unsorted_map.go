@@ -0,0 +1,4 @@
+package tests
+
+//easyjson:json
+type UnsortedMap map[string]int
@@ -0,0 +1,12 @@
+package tests
+
+// Account has views registered with the generator in api_gen.go.
+type Account struct {
+	ID     int    `json:"id" view:"public,admin"`
+	Name   string `json:"name" view:"public, admin"`
+	Email  string `json:"email" view:"admin"`
+	Secret string `json:"secret" view:"admin"`
+	Note   string `json:"note"`
+}
+
+var accountValue = Account{ID: 1, Name: "ann", Email: "ann@example.com", Secret: "s3cr3t", Note: "n"}
@@ -0,0 +1,30 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestViews(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		marshal func() ([]byte, error)
+		want    string
+	}{
+		{name: "MarshalJSON", marshal: accountValue.MarshalJSON, want: `{"id":1,"name":"ann","email":"ann@example.com","secret":"s3cr3t","note":"n"}`},
+		{name: "MarshalJSONPublic", marshal: accountValue.MarshalJSONPublic, want: `{"id":1,"name":"ann"}`},
+		{name: "MarshalJSONAdmin", marshal: accountValue.MarshalJSONAdmin, want: `{"id":1,"name":"ann","email":"ann@example.com","secret":"s3cr3t"}`},
+	} {
+		got, err := test.marshal()
+		if err != nil {
+			t.Errorf("%s() error: %v", test.name, err)
+			continue
+		}
+		if string(got) != test.want {
+			t.Errorf("%s() = %s; want %s", test.name, got, test.want)
+		}
+		if !json.Valid(got) {
+			t.Errorf("%s() = %s is not valid JSON", test.name, got)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package tests
+
+//easyjson:json
+type WriterToValue struct {
+	Name  string
+	Lines []string
+	Score float64
+}
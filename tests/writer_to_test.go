@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWriterTo(t *testing.T) {
+	for _, v := range []WriterToValue{
+		{Name: "small", Score: 0.5},
+		// Long enough for the encoding to take several buffer chunks.
+		{Name: "large", Lines: strings.Split(strings.Repeat("line of text,", 10000), ",")},
+	} {
+		want, err := v.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON() error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		buf.WriteString("prefix")
+		n, err := v.WriteTo(&buf)
+		if err != nil {
+			t.Errorf("WriteTo() of %v error: %v", v.Name, err)
+		}
+		if n != int64(len(want)) || n != int64(buf.Len()-len("prefix")) {
+			t.Errorf("WriteTo() of %v = %v bytes; want %v, the length of the written data", v.Name, n, len(want))
+		}
+		if got := strings.TrimPrefix(buf.String(), "prefix"); got != string(want) {
+			t.Errorf("WriteTo() of %v wrote %.100s; want %.100s", v.Name, got, want)
+		}
+	}
+
+	var _ io.WriterTo = WriterToValue{}
+}
+
+func TestWriterToError(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := WriterToValue{Score: math.NaN()}.WriteTo(&buf)
+	if err == nil {
+		t.Errorf("WriteTo() of NaN = %v bytes; want an error", n)
+	}
+	if n != 0 || buf.Len() != 0 {
+		t.Errorf("WriteTo() of NaN = %v bytes, wrote %q; want nothing written", n, buf.String())
+	}
+}